@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/akrovv/filmlibrary/internal/adapters/postgresqldb"
+	"github.com/akrovv/filmlibrary/internal/adapters/redisdb"
+	"github.com/akrovv/filmlibrary/internal/config"
+	"github.com/akrovv/filmlibrary/internal/controllers/cli"
+	"github.com/akrovv/filmlibrary/internal/service"
+	"github.com/akrovv/filmlibrary/pkg/hasher"
+	_ "github.com/lib/pq"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	path     = "."
+	filename = ".env"
+)
+
+// filmctl administers the library directly in the database, with the same
+// configuration as the server.
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "filmctl:", err)
+		if errors.Is(err, cli.ErrUsage) {
+			os.Exit(2)
+		}
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	cfg, err := config.NewConfig(path, filename)
+	if err != nil {
+		return err
+	}
+
+	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+		cfg.DBHost,
+		cfg.DBPort, cfg.DBUser, cfg.DBPassword,
+		cfg.DBName, cfg.SSLMode)
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if err = db.Ping(); err != nil {
+		return err
+	}
+
+	migrator, err := postgresqldb.NewMigrator(context.Background(), db)
+	if err != nil {
+		return err
+	}
+
+	// The legacy salt has to match the server's, or the MD5 hashes left won't log in.
+	userHasher, err := hasher.NewPasswordHasher(cfg.PasswordHash, hasher.LegacySalt)
+	if err != nil {
+		return err
+	}
+
+	// The client dials Redis on first use, so only promote and demote need it
+	// to carry the change over to the sessions and refresh tokens of the user.
+	client := redis.NewClient(&redis.Options{
+		Addr: fmt.Sprintf("%s:%s", cfg.RedisHost, cfg.RedisPort),
+		DB:   0,
+	})
+	defer client.Close()
+
+	var (
+		sessionStorage = redisdb.NewSessionStorage(context.Background(), client, cfg.SessionIdleTimeout, cfg.SessionMaxLifetime)
+		refreshStorage = redisdb.NewRefreshTokenStorage(context.Background(), client, cfg.RefreshTokenTTL)
+	)
+
+	var (
+		actorService  = service.NewActorService(postgresqldb.NewActorStorage(db))
+		movieService  = service.NewMovieService(postgresqldb.NewMovieStorage(db), cfg.SearchSimilarity)
+		userService   = service.NewUserService(postgresqldb.NewUserStorage(db, userHasher), sessionStorage, refreshStorage)
+		importService = service.NewImportService(postgresqldb.NewImportStorage(db))
+		exportService = service.NewExportService(postgresqldb.NewExportStorage(db))
+		imdbService   = service.NewIMDbService(postgresqldb.NewIMDbStorage(db))
+	)
+
+	return cli.NewCLI(os.Stdin, os.Stdout, userService, movieService, actorService, importService, exportService, imdbService, migrator).Run(args)
+}
@@ -3,18 +3,22 @@ package main
 import (
 	"context"
 	"database/sql"
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
+	"os"
 
 	"github.com/akrovv/filmlibrary/internal/adapters/postgresqldb"
 	"github.com/akrovv/filmlibrary/internal/adapters/redisdb"
 	"github.com/akrovv/filmlibrary/internal/config"
+	"github.com/akrovv/filmlibrary/internal/controllers/cli"
 	"github.com/akrovv/filmlibrary/internal/controllers/restapi"
 	"github.com/akrovv/filmlibrary/internal/controllers/restapi/middleware"
 	"github.com/akrovv/filmlibrary/internal/service"
 	"github.com/akrovv/filmlibrary/pkg/hasher"
 	"github.com/akrovv/filmlibrary/pkg/logger"
+	"github.com/akrovv/filmlibrary/pkg/token"
 	"github.com/casbin/casbin/v2"
 	md "github.com/go-openapi/runtime/middleware"
 	_ "github.com/lib/pq"
@@ -26,6 +30,8 @@ const (
 	filename = ".env"
 	model    = "./rbac_model.conf"
 	policy   = "./rbac_policy.csv"
+	scopes   = "./rbac_scopes.csv"
+	issuer   = "filmlibrary"
 )
 
 // @title FilmLibrary
@@ -36,12 +42,64 @@ const (
 // @BasePath  /
 
 func main() {
+	migrateOnStart := flag.Bool("migrate", false, "apply pending schema migrations before serving")
+	flag.Parse()
+
 	logger, err := logger.NewLogger()
 	if err != nil {
 		log.Fatal(err)
 		return
 	}
 
+	cfg, err := config.NewConfig(path, filename)
+	if err != nil {
+		logger.Info(err)
+		return
+	}
+
+	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+		cfg.DBHost,
+		cfg.DBPort, cfg.DBUser, cfg.DBPassword,
+		cfg.DBName, cfg.SSLMode)
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		logger.Info(err)
+		return
+	}
+
+	db.SetMaxOpenConns(10)
+
+	if err = db.Ping(); err != nil {
+		logger.Info(err)
+		return
+	}
+
+	migrator, err := postgresqldb.NewMigrator(context.Background(), db)
+	if err != nil {
+		logger.Info(err)
+		return
+	}
+
+	if flag.Arg(0) == "migrate" {
+		if err = cli.Migrate(os.Stdout, migrator, flag.Args()[1:]); err != nil {
+			logger.Info(err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *migrateOnStart {
+		applied, err := migrator.Up()
+		for _, migration := range applied {
+			logger.Infof("applied migration %04d_%s", migration.Version, migration.Name)
+		}
+		if err != nil {
+			logger.Info(err)
+			return
+		}
+	}
+
 	enforcer, err := casbin.NewEnforcer(model, policy)
 	if err != nil {
 		logger.Info(err)
@@ -54,7 +112,7 @@ func main() {
 		return
 	}
 
-	cfg, err := config.NewConfig(path, filename)
+	scopeEnforcer, err := casbin.NewEnforcer(model, scopes)
 	if err != nil {
 		logger.Info(err)
 		return
@@ -73,47 +131,76 @@ func main() {
 		return
 	}
 
-	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		cfg.DBHost,
-		cfg.DBPort, cfg.DBUser, cfg.DBPassword,
-		cfg.DBName, cfg.SSLMode)
-
-	db, err := sql.Open("postgres", dsn)
+	// MD5 hashes of the former salt are still verified and replaced as
+	// their users log in.
+	userHasher, err := hasher.NewPasswordHasher(cfg.PasswordHash, hasher.LegacySalt)
 	if err != nil {
 		logger.Info(err)
 		return
 	}
 
-	db.SetMaxOpenConns(10)
+	if cfg.JWTKeys == "" {
+		logger.Info("JWT_KEYS must be set in the environment")
+		return
+	}
 
-	if err = db.Ping(); err != nil {
+	keys, err := token.ParseKeys(cfg.JWTKeys)
+	if err != nil {
 		logger.Info(err)
 		return
 	}
 
-	var (
-		userHasher    = hasher.NewHasher([]byte("secret"))
-		sessionHasher = hasher.NewHasher([]byte("session"))
-	)
+	signer, err := token.NewKeyring(issuer, keys...)
+	if err != nil {
+		logger.Info(err)
+		return
+	}
 
 	var (
 		actorStorage   = postgresqldb.NewActorStorage(db)
 		movieStorage   = postgresqldb.NewMovieStorage(db)
 		userStorage    = postgresqldb.NewUserStorage(db, userHasher)
-		sessionStorage = redisdb.NewSessionStorage(ctxRedis, client, sessionHasher)
+		sessionStorage = redisdb.NewSessionStorage(ctxRedis, client, cfg.SessionIdleTimeout, cfg.SessionMaxLifetime)
+		importStorage  = postgresqldb.NewImportStorage(db)
+		exportStorage  = postgresqldb.NewExportStorage(db)
+		suggestStorage = postgresqldb.NewSuggestStorage(db)
+		refreshStorage = redisdb.NewRefreshTokenStorage(ctxRedis, client, cfg.RefreshTokenTTL)
+		apiKeyStorage  = postgresqldb.NewAPIKeyStorage(db)
 	)
 
+	var suggestCache service.SuggestCache
+	if cfg.SuggestCacheTTL > 0 {
+		suggestCache = redisdb.NewSuggestCache(ctxRedis, client, cfg.SuggestCacheTTL)
+	}
+
 	var (
 		actorService   = service.NewActorService(actorStorage)
-		movieService   = service.NewMovieService(movieStorage)
-		userService    = service.NewUserService(userStorage)
+		movieService   = service.NewMovieService(movieStorage, cfg.SearchSimilarity)
+		userService    = service.NewUserService(userStorage, sessionStorage, refreshStorage)
 		sessionService = service.NewSessionService(sessionStorage)
+		importService  = service.NewImportService(importStorage)
+		exportService  = service.NewExportService(exportStorage)
+		suggestService = service.NewSuggestService(suggestStorage, suggestCache)
+		tokenService   = service.NewTokenService(userStorage, refreshStorage, signer, cfg.AccessTokenTTL)
+		apiKeyService  = service.NewAPIKeyService(apiKeyStorage)
 	)
 
+	cookie, err := restapi.NewSessionCookie(cfg.CookieDomain, cfg.CookiePath, cfg.CookieSecure, cfg.CookieHTTPOnly, cfg.CookieSameSite)
+	if err != nil {
+		logger.Info(err)
+		return
+	}
+
 	var (
-		actorController = restapi.NewActorController(logger, actorService)
-		movieController = restapi.NewMovieController(logger, movieService)
-		userController  = restapi.NewUserController(logger, userService, sessionService)
+		actorController   = restapi.NewActorController(logger, actorService)
+		movieController   = restapi.NewMovieController(logger, movieService)
+		userController    = restapi.NewUserController(logger, userService, sessionService, cookie)
+		importController  = restapi.NewImportController(logger, importService, cfg.ImportMaxSize<<20)
+		exportController  = restapi.NewExportController(logger, exportService)
+		suggestController = restapi.NewSuggestController(logger, suggestService)
+		sessionController = restapi.NewSessionController(logger, sessionService, cookie)
+		tokenController   = restapi.NewTokenController(logger, tokenService)
+		apiKeyController  = restapi.NewAPIKeyController(logger, apiKeyService)
 	)
 
 	mux := http.NewServeMux()
@@ -126,20 +213,34 @@ func main() {
 
 	mux.HandleFunc("/register", userController.Register)
 	mux.HandleFunc("/login", userController.Login)
+	mux.HandleFunc("/logout", sessionController.Logout)
+	mux.HandleFunc("/token", tokenController.Token)
+	mux.HandleFunc("/sessions", sessionController.List)
+	mux.HandleFunc("/sessions/", sessionController.ManageItem)
+	mux.HandleFunc("/api-keys", apiKeyController.ManagePath)
+	mux.HandleFunc("/api-keys/", apiKeyController.ManageItem)
 
 	mux.HandleFunc("/actor", actorController.ManagePath)
+	mux.HandleFunc("/actors/", actorController.ManageItem)
 
 	mux.HandleFunc("/movie/all", movieController.GetOrderedList)
 	mux.HandleFunc("/movie", movieController.ManagePath)
+	mux.HandleFunc("/movies/", movieController.ManageItem)
+	mux.HandleFunc("/search", movieController.Search)
+	mux.HandleFunc("/suggest", suggestController.Suggest)
+
+	mux.HandleFunc("/import", importController.Import)
+	mux.HandleFunc("/export", exportController.Export)
 
 	var (
-		roleMiddleware   = middleware.Role(mux, enforcer)
-		authMiddleware   = middleware.Auth(roleMiddleware, sessionService)
-		loggerMiddleware = middleware.Logger(authMiddleware, logger)
+		roleMiddleware      = middleware.Role(mux, enforcer, scopeEnforcer)
+		authMiddleware      = middleware.Auth(roleMiddleware, sessionService, tokenService, apiKeyService, cookie)
+		loggerMiddleware    = middleware.Logger(authMiddleware, logger)
+		requestIDMiddleware = middleware.RequestID(loggerMiddleware)
 	)
 
 	logger.Infof("starting on :%s", cfg.ServerPort)
-	if err = http.ListenAndServe(fmt.Sprintf(":%s", cfg.ServerPort), loggerMiddleware); err != nil {
+	if err = http.ListenAndServe(fmt.Sprintf(":%s", cfg.ServerPort), requestIDMiddleware); err != nil {
 		logger.Info(err)
 		return
 	}
@@ -2,6 +2,10 @@ package postgresqldb
 
 import (
 	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
 
 	"github.com/akrovv/filmlibrary/internal/domain"
 )
@@ -21,51 +25,195 @@ func (s *actorStorage) Create(dto *domain.CreateActor) error {
 		dto.Gender, dto.DateBirth)
 
 	if err != nil {
-		return err
+		return dbError(err)
 	}
 
 	return nil
 }
 
 func (s *actorStorage) Update(dto *domain.Actor) error {
-	_, err := s.db.Exec("UPDATE Actors SET actor_name=$1, gender=$2, date_of_birth=$3 WHERE actor_id=$4", dto.Name, dto.Gender, dto.DateBirth, dto.ID)
+	cond, condParams := versionCond(dto.Version, 5)
+	result, err := s.db.Exec("UPDATE Actors SET actor_name=$1, gender=$2, date_of_birth=$3, version=version+1 WHERE actor_id=$4"+cond,
+		append([]interface{}{dto.Name, dto.Gender, dto.DateBirth, dto.ID}, condParams...)...)
+
+	if err != nil {
+		return dbError(err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return checkVersion(s.db, "Actors", "actor_id", dto.ID, "actor")
+	}
+
+	return nil
+}
+
+func (s *actorStorage) Patch(dto *domain.PatchActor) error {
+	patch := patchSet{}
+	if dto.Name != nil {
+		patch.add("actor_name", *dto.Name)
+	}
+	if dto.Gender != nil {
+		patch.add("gender", *dto.Gender)
+	}
+	if dto.DateBirth != nil {
+		patch.add("date_of_birth", *dto.DateBirth)
+	} else if dto.NullDateBirth {
+		patch.add("date_of_birth", nil)
+	}
 
+	query, params := patch.query("Actors", "actor_id", dto.ID, dto.Version)
+	result, err := s.db.Exec(query, params...)
+	if err != nil {
+		return dbError(err)
+	}
+
+	affected, err := result.RowsAffected()
 	if err != nil {
 		return err
 	}
+	if affected == 0 {
+		return checkVersion(s.db, "Actors", "actor_id", dto.ID, "actor")
+	}
 
 	return nil
 }
 
 func (s *actorStorage) Delete(dto *domain.DeleteActor) error {
-	_, err := s.db.Exec("DELETE FROM Actors WHERE actor_id=$1", dto.ID)
+	cond, condParams := versionCond(dto.Version, 2)
+	result, err := s.db.Exec("DELETE FROM Actors WHERE actor_id=$1"+cond, append([]interface{}{dto.ID}, condParams...)...)
 
+	if err != nil {
+		return dbError(err)
+	}
+
+	affected, err := result.RowsAffected()
 	if err != nil {
 		return err
 	}
+	if affected == 0 {
+		return checkVersion(s.db, "Actors", "actor_id", dto.ID, "actor")
+	}
 
 	return nil
 }
 
-func (s *actorStorage) GetList() ([]domain.ActorWithMovie, error) {
-	actors := make([]domain.ActorWithMovie, 0, 4)
+// filmography is a row of the aggregated filmography as built by json_build_object.
+type filmography struct {
+	ID          int64  `json:"movie_id"`
+	Title       string `json:"movie_title"`
+	ReleaseDate string `json:"release_date"`
+}
+
+func (s *actorStorage) GetList(dto *domain.GetActorList) (*domain.ActorList, error) {
+	actors := make([]domain.ActorWithMovies, 0, dto.Page.Limit+1)
 
-	rows, err := s.db.Query("SELECT actor_name, gender, date_of_birth, movie_title FROM Actors JOIN MovieActors USING(actor_id) JOIN Movies USING(movie_id)")
+	k, err := newKeyset([]keysetColumn{{name: "a.actor_name", kind: keysetText}, {name: "a.actor_id", kind: keysetInt}}, dto.Page)
 	if err != nil {
 		return nil, err
 	}
+
+	where, params := k.where(1)
+	query := fmt.Sprintf("SELECT a.actor_id, a.actor_name, a.gender, a.date_of_birth, f.movies FROM Actors a "+
+		"CROSS JOIN LATERAL (SELECT COALESCE(json_agg(json_build_object('movie_id', m.movie_id, 'movie_title', m.movie_title, 'release_date', m.release_date) "+
+		"ORDER BY m.release_date, m.movie_id), '[]') AS movies FROM MovieActors ma JOIN Movies m ON m.movie_id = ma.movie_id WHERE ma.actor_id = a.actor_id) f "+
+		"WHERE %s ORDER BY %s LIMIT $%d", where, k.orderBy(), len(params)+1)
+
+	rows, err := s.db.Query(query, append(params, k.fetch())...)
+	if err != nil {
+		return nil, dbError(err)
+	}
 	defer rows.Close()
 
 	for rows.Next() {
-		actor := domain.ActorWithMovie{}
+		actor := domain.ActorWithMovies{}
+		var data []byte
 
-		err = rows.Scan(&actor.Name, &actor.Gender, &actor.DateBirth, &actor.Title)
+		err = scanActor(rows, &actor.Actor, &data)
 		if err != nil {
+			return nil, dbError(err)
+		}
+
+		movies := make([]filmography, 0, 4)
+		if err = json.Unmarshal(data, &movies); err != nil {
 			return nil, err
 		}
 
+		actor.Movies = make([]domain.ActorMovie, 0, len(movies))
+		for _, movie := range movies {
+			actorMovie := domain.ActorMovie{
+				ID:    movie.ID,
+				Title: movie.Title,
+			}
+
+			if movie.ReleaseDate != "" {
+				if actorMovie.ReleaseDate, err = time.Parse("2006-01-02", movie.ReleaseDate); err != nil {
+					return nil, err
+				}
+			}
+
+			actor.Movies = append(actor.Movies, actorMovie)
+		}
+
 		actors = append(actors, actor)
 	}
 
-	return actors, nil
+	if err = rows.Err(); err != nil {
+		return nil, dbError(err)
+	}
+
+	list := domain.ActorList{}
+	list.Actors, list.Cursors, err = keysetPage(k, actors, func(actor domain.ActorWithMovies) []interface{} {
+		return []interface{}{actor.Name, actor.ID}
+	})
+	if err != nil {
+		return nil, dbError(err)
+	}
+
+	return &list, nil
+}
+
+func (s *actorStorage) Get(dto *domain.GetActor) (*domain.Actor, error) {
+	actor := domain.Actor{}
+
+	row := s.db.QueryRow("SELECT actor_id, actor_name, gender, date_of_birth, version FROM Actors WHERE actor_id=$1", dto.ID)
+	err := scanActor(row, &actor, &actor.Version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, dbError(err)
+	}
+
+	return &actor, nil
+}
+
+func (s *actorStorage) GetMovies(dto *domain.GetActorMovies) ([]domain.Movie, error) {
+	movies := make([]domain.Movie, 0, 4)
+
+	rows, err := s.db.Query("SELECT movie_id, movie_title, description, release_date, rating FROM Movies JOIN MovieActors USING(movie_id) WHERE actor_id=$1 ORDER BY release_date", dto.ID)
+	if err != nil {
+		return nil, dbError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		movie := domain.Movie{}
+
+		err = scanMovie(rows, &movie)
+		if err != nil {
+			return nil, dbError(err)
+		}
+
+		movies = append(movies, movie)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, dbError(err)
+	}
+
+	return movies, nil
 }
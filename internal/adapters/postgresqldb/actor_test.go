@@ -1,7 +1,10 @@
 package postgresqldb
 
 import (
+	"database/sql"
+	"errors"
 	"reflect"
+	"regexp"
 	"testing"
 	"time"
 
@@ -69,7 +72,7 @@ func TestActorUpdate(t *testing.T) {
 	}
 
 	// OK
-	mock.ExpectExec(`UPDATE Actors SET actor_name=\$1, gender=\$2, date_of_birth=\$3 WHERE actor_id=\$4`).
+	mock.ExpectExec(`UPDATE Actors SET actor_name=\$1, gender=\$2, date_of_birth=\$3, version=version\+1 WHERE actor_id=\$4`).
 		WithArgs(actor.Name, actor.Gender, actor.DateBirth, actor.ID).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
@@ -83,7 +86,7 @@ func TestActorUpdate(t *testing.T) {
 	}
 
 	// Postgres returned error
-	mock.ExpectExec(`UPDATE Actors SET actor_name=\$1, gender=\$2, date_of_birth=\$3 WHERE actor_id=\$4`).
+	mock.ExpectExec(`UPDATE Actors SET actor_name=\$1, gender=\$2, date_of_birth=\$3, version=version\+1 WHERE actor_id=\$4`).
 		WithArgs(actor.Name, actor.Gender, actor.DateBirth, actor.ID).
 		WillReturnError(domain.ErrTest)
 
@@ -98,6 +101,101 @@ func TestActorUpdate(t *testing.T) {
 	}
 }
 
+func TestActorPatch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("can't create mock: %s", err)
+	}
+	defer db.Close()
+
+	storage := NewActorStorage(db)
+
+	gender := "Female"
+	actor := domain.PatchActor{
+		ID:     1,
+		Gender: &gender,
+	}
+
+	// OK
+	mock.ExpectExec(`UPDATE Actors SET gender = \$1, version = version \+ 1 WHERE actor_id = \$2`).
+		WithArgs(gender, actor.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = storage.Patch(&actor)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// OK, null clears the date of birth
+	mock.ExpectExec(`UPDATE Actors SET date_of_birth = \$1, version = version \+ 1 WHERE actor_id = \$2`).
+		WithArgs(nil, actor.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = storage.Patch(&domain.PatchActor{ID: 1, NullDateBirth: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// Not found
+	mock.ExpectExec(`UPDATE Actors SET gender = \$1, version = version \+ 1 WHERE actor_id = \$2`).
+		WithArgs(gender, actor.ID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT version FROM Actors WHERE actor_id = \$1`).
+		WithArgs(actor.ID).
+		WillReturnError(sql.ErrNoRows)
+
+	err = storage.Patch(&actor)
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got: %v", err)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// Stale version
+	actor.Version = 2
+	mock.ExpectExec(`UPDATE Actors SET gender = \$1, version = version \+ 1 WHERE actor_id = \$2 AND version = \$3`).
+		WithArgs(gender, actor.ID, actor.Version).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT version FROM Actors WHERE actor_id = \$1`).
+		WithArgs(actor.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(3))
+
+	err = storage.Patch(&actor)
+	var conflict *domain.VersionConflictError
+	if !errors.As(err, &conflict) || conflict.Version != 3 {
+		t.Errorf("expected VersionConflictError, got: %v", err)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+	actor.Version = 0
+
+	// Postgres returned error
+	mock.ExpectExec(`UPDATE Actors SET gender = \$1, version = version \+ 1 WHERE actor_id = \$2`).
+		WithArgs(gender, actor.ID).
+		WillReturnError(domain.ErrTest)
+
+	err = storage.Patch(&actor)
+	if err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
 func TestActorDelete(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -148,38 +246,87 @@ func TestActorGetList(t *testing.T) {
 	defer db.Close()
 	storage := NewActorStorage(db)
 
-	testTime := time.Now()
-	expectedActors := []domain.ActorWithMovie{
-		{CreateActor: domain.CreateActor{
-			Name: "Actor 1", Gender: "Male", DateBirth: testTime,
-		},
-			Title: "Movie 1"},
+	testTime := time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC)
+	dto := &domain.GetActorList{
+		Page: domain.Page{Limit: 2},
+	}
+	expectedActors := []domain.ActorWithMovies{
 		{
-			CreateActor: domain.CreateActor{
-				Name: "Actor 2", Gender: "Female", DateBirth: testTime,
+			Actor: domain.Actor{ID: 1, Name: "Actor 1", Gender: "Male", DateBirth: testTime},
+			Movies: []domain.ActorMovie{
+				{ID: 1, Title: "Movie 1", ReleaseDate: time.Date(2007, 2, 2, 0, 0, 0, 0, time.UTC)},
+				{ID: 2, Title: "Movie 2", ReleaseDate: time.Date(2010, 7, 16, 0, 0, 0, 0, time.UTC)},
 			},
-			Title: "Movie 2",
+		},
+		{
+			Actor:  domain.Actor{ID: 2, Name: "Actor 2", Gender: "Female", DateBirth: testTime},
+			Movies: []domain.ActorMovie{},
 		},
 	}
 
-	rows := sqlmock.NewRows([]string{"actor_name", "gender", "date_of_birth", "movie_title"}).
-		AddRow("Actor 1", "Male", testTime, "Movie 1").
-		AddRow("Actor 2", "Female", testTime, "Movie 2")
+	query := regexp.QuoteMeta("SELECT a.actor_id, a.actor_name, a.gender, a.date_of_birth, f.movies FROM Actors a " +
+		"CROSS JOIN LATERAL (SELECT COALESCE(json_agg(json_build_object('movie_id', m.movie_id, 'movie_title', m.movie_title, 'release_date', m.release_date) " +
+		"ORDER BY m.release_date, m.movie_id), '[]') AS movies FROM MovieActors ma JOIN Movies m ON m.movie_id = ma.movie_id WHERE ma.actor_id = a.actor_id) f " +
+		"WHERE TRUE ORDER BY a.actor_name ASC, a.actor_id ASC LIMIT $1")
+	columns := []string{"actor_id", "actor_name", "gender", "date_of_birth", "movies"}
+
+	rows := sqlmock.NewRows(columns).
+		AddRow(1, "Actor 1", "Male", testTime, `[{"movie_id": 1, "movie_title": "Movie 1", "release_date": "2007-02-02"}, {"movie_id": 2, "movie_title": "Movie 2", "release_date": "2010-07-16"}]`).
+		AddRow(2, "Actor 2", "Female", testTime, `[]`).
+		AddRow(3, "Actor 3", "Female", testTime, `[]`)
 
 	rowsWithError := sqlmock.NewRows([]string{"actor_name", "gender"}).
 		AddRow("Actor 1", "Male")
 
 	// OK
-	mock.ExpectQuery(`SELECT actor_name, gender, date_of_birth, movie_title FROM Actors JOIN MovieActors USING\(actor_id\) JOIN Movies USING\(movie_id\)`).
+	mock.ExpectQuery(query).
+		WithArgs(3).
 		WillReturnRows(rows)
 
-	actors, err := storage.GetList()
+	list, err := storage.GetList(dto)
 	if err != nil {
 		t.Fatalf("unexpected error: %s", err)
 	}
 
-	if !reflect.DeepEqual(actors, expectedActors) {
-		t.Errorf("expected: %v, got: %v", expectedActors, actors)
+	if !reflect.DeepEqual(list.Actors, expectedActors) {
+		t.Errorf("expected: %v, got: %v", expectedActors, list.Actors)
+	}
+
+	if list.Next == "" || list.Prev != "" {
+		t.Errorf("expected only next cursor, got: %v", list.Cursors)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// Malformed cursor
+	list, err = storage.GetList(&domain.GetActorList{
+		Page: domain.Page{Limit: 2, After: "not a cursor"},
+	})
+
+	if !errors.Is(err, domain.ErrRequest) {
+		t.Errorf("expected: %v, got: %v", domain.ErrRequest, err)
+	}
+
+	if list != nil {
+		t.Errorf("expected nil, got: %v", list)
+	}
+
+	// Malformed filmography
+	mock.ExpectQuery(query).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(1, "Actor 1", "Male", testTime, `{`))
+
+	list, err = storage.GetList(dto)
+
+	if err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	if list != nil {
+		t.Errorf("expected nil, got: %v", list)
 	}
 
 	if err = mock.ExpectationsWereMet(); err != nil {
@@ -187,17 +334,37 @@ func TestActorGetList(t *testing.T) {
 	}
 
 	// Postgres returned error
-	mock.ExpectQuery(`SELECT actor_name, gender, date_of_birth, movie_title FROM Actors JOIN MovieActors USING\(actor_id\) JOIN Movies USING\(movie_id\)`).
+	mock.ExpectQuery(query).
 		WillReturnError(domain.ErrTest)
 
-	actors, err = storage.GetList()
+	list, err = storage.GetList(dto)
 
 	if err == nil {
 		t.Error("expected error, got nil")
 	}
 
-	if actors != nil {
-		t.Errorf("expected nil, got: %v", actors)
+	if list != nil {
+		t.Errorf("expected nil, got: %v", list)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// Rows iteration error
+	mock.ExpectQuery(query).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(1, "Actor 1", "Male", testTime, `[]`).
+			AddRow(2, "Actor 2", "Female", testTime, `[]`).
+			RowError(1, domain.ErrTest))
+
+	if list, err = storage.GetList(dto); !errors.Is(err, domain.ErrTest) {
+		t.Errorf("expected ErrTest, got: %v", err)
+	}
+
+	if list != nil {
+		t.Errorf("expected nil, got: %v", list)
 	}
 
 	if err = mock.ExpectationsWereMet(); err != nil {
@@ -205,17 +372,148 @@ func TestActorGetList(t *testing.T) {
 	}
 
 	// Rows scan error
-	mock.ExpectQuery(`SELECT actor_name, gender, date_of_birth, movie_title FROM Actors JOIN MovieActors USING\(actor_id\) JOIN Movies USING\(movie_id\)`).
+	mock.ExpectQuery(query).
 		WillReturnRows(rowsWithError)
 
-	actors, err = storage.GetList()
+	list, err = storage.GetList(dto)
+
+	if err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	if list != nil {
+		t.Errorf("expected nil, got: %v", list)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestActorGet(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("can't create mock: %s", err)
+	}
+	defer db.Close()
+	storage := NewActorStorage(db)
+
+	testTime := time.Now()
+	dto := &domain.GetActor{
+		ID: 1,
+	}
+	expectedActor := &domain.Actor{
+		ID: 1, Name: "Actor 1", Gender: "Male", DateBirth: testTime, Version: 3,
+	}
+
+	// OK
+	mock.ExpectQuery(`SELECT actor_id, actor_name, gender, date_of_birth, version FROM Actors WHERE actor_id=\$1`).
+		WithArgs(dto.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"actor_id", "actor_name", "gender", "date_of_birth", "version"}).
+			AddRow(1, "Actor 1", "Male", testTime, 3))
+
+	actor, err := storage.Get(dto)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if !reflect.DeepEqual(actor, expectedActor) {
+		t.Errorf("expected: %v, got: %v", expectedActor, actor)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// Actor not found
+	mock.ExpectQuery(`SELECT actor_id, actor_name, gender, date_of_birth, version FROM Actors WHERE actor_id=\$1`).
+		WithArgs(dto.ID).
+		WillReturnError(sql.ErrNoRows)
+
+	actor, err = storage.Get(dto)
+
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected: %v, got: %v", domain.ErrNotFound, err)
+	}
+
+	if actor != nil {
+		t.Errorf("expected nil, got: %v", actor)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// Postgres returned error
+	mock.ExpectQuery(`SELECT actor_id, actor_name, gender, date_of_birth, version FROM Actors WHERE actor_id=\$1`).
+		WithArgs(dto.ID).
+		WillReturnError(domain.ErrTest)
+
+	actor, err = storage.Get(dto)
+
+	if err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	if actor != nil {
+		t.Errorf("expected nil, got: %v", actor)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestActorGetMovies(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("can't create mock: %s", err)
+	}
+	defer db.Close()
+	storage := NewActorStorage(db)
+
+	testTime := time.Now()
+	dto := &domain.GetActorMovies{
+		ID: 1,
+	}
+	expectedMovies := []domain.Movie{
+		{ID: 1, Title: "Movie 1", Description: "Description 1", ReleaseDate: testTime, Rating: 5},
+		{ID: 2, Title: "Movie 2", Description: "Description 2", ReleaseDate: testTime, Rating: 4},
+	}
+
+	// OK
+	mock.ExpectQuery(`SELECT movie_id, movie_title, description, release_date, rating FROM Movies JOIN MovieActors USING\(movie_id\) WHERE actor_id=\$1 ORDER BY release_date`).
+		WithArgs(dto.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"movie_id", "movie_title", "description", "release_date", "rating"}).
+			AddRow(1, "Movie 1", "Description 1", testTime, 5).
+			AddRow(2, "Movie 2", "Description 2", testTime, 4))
+
+	movies, err := storage.GetMovies(dto)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if !reflect.DeepEqual(movies, expectedMovies) {
+		t.Errorf("expected: %v, got: %v", expectedMovies, movies)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// Postgres returned error
+	mock.ExpectQuery(`SELECT movie_id, movie_title, description, release_date, rating FROM Movies JOIN MovieActors USING\(movie_id\) WHERE actor_id=\$1 ORDER BY release_date`).
+		WithArgs(dto.ID).
+		WillReturnError(domain.ErrTest)
+
+	movies, err = storage.GetMovies(dto)
 
 	if err == nil {
 		t.Error("expected error, got nil")
 	}
 
-	if actors != nil {
-		t.Errorf("expected nil, got: %v", actors)
+	if movies != nil {
+		t.Errorf("expected nil, got: %v", movies)
 	}
 
 	if err = mock.ExpectationsWereMet(); err != nil {
@@ -0,0 +1,141 @@
+package postgresqldb
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+	"github.com/lib/pq"
+)
+
+const (
+	apiKeyPrefix       = "flk_"
+	apiKeyLength       = 32
+	apiKeyPrefixLength = len(apiKeyPrefix) + 8
+)
+
+type apiKeyStorage struct {
+	db  *sql.DB
+	key func() (string, error)
+}
+
+func NewAPIKeyStorage(db *sql.DB) *apiKeyStorage {
+	return &apiKeyStorage{
+		db:  db,
+		key: newAPIKey,
+	}
+}
+
+func newAPIKey() (string, error) {
+	buf := make([]byte, apiKeyLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return apiKeyPrefix + base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// hashAPIKey is what a key is stored and looked up under. The keys are
+// random, so a fast hash is as good as a password hash for them.
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func (s *apiKeyStorage) Create(dto *domain.CreateAPIKey) (*domain.CreatedAPIKey, error) {
+	key, err := s.key()
+	if err != nil {
+		return nil, err
+	}
+
+	created := domain.CreatedAPIKey{
+		APIKey: domain.APIKey{
+			Username: dto.Username,
+			Name:     dto.Name,
+			Prefix:   key[:apiKeyPrefixLength],
+			Scopes:   dto.Scopes,
+		},
+		Key: key,
+	}
+
+	err = s.db.QueryRow(`INSERT INTO ApiKeys (username, key_name, prefix, key_hash, scopes)
+VALUES ($1, $2, $3, $4, $5) RETURNING key_id, created_at`,
+		dto.Username, dto.Name, created.Prefix, hashAPIKey(key), pq.Array(dto.Scopes)).
+		Scan(&created.ID, &created.CreatedAt)
+	if err != nil {
+		return nil, dbError(err)
+	}
+
+	return &created, nil
+}
+
+func (s *apiKeyStorage) List(dto *domain.ListAPIKeys) (*domain.APIKeyList, error) {
+	rows, err := s.db.Query(`SELECT key_id, username, key_name, prefix, scopes, created_at, last_used_at
+FROM ApiKeys WHERE username = $1 ORDER BY key_id`, dto.Username)
+	if err != nil {
+		return nil, dbError(err)
+	}
+	defer rows.Close()
+
+	list := domain.APIKeyList{Keys: []domain.APIKey{}}
+	for rows.Next() {
+		key := domain.APIKey{}
+		if err = rows.Scan(&key.ID, &key.Username, &key.Name, &key.Prefix, pq.Array(&key.Scopes),
+			&key.CreatedAt, &key.LastUsedAt); err != nil {
+			return nil, dbError(err)
+		}
+
+		list.Keys = append(list.Keys, key)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, dbError(err)
+	}
+
+	return &list, nil
+}
+
+// Delete revokes a key. A key of someone other than dto.Username is
+// reported as not found, the same as a missing one.
+func (s *apiKeyStorage) Delete(dto *domain.DeleteAPIKey) error {
+	result, err := s.db.Exec("DELETE FROM ApiKeys WHERE key_id = $1 AND ($2 = '' OR username = $2)", dto.ID, dto.Username)
+	if err != nil {
+		return dbError(err)
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return dbError(err)
+	}
+
+	if count == 0 {
+		return fmt.Errorf("%w: api key %d", domain.ErrNotFound, dto.ID)
+	}
+
+	return nil
+}
+
+// Authenticate returns the owner of a key with their current role and the
+// scopes of the key, and records the use of the key.
+func (s *apiKeyStorage) Authenticate(dto *domain.GetAPIKey) (*domain.ScopedUser, error) {
+	user := domain.ScopedUser{User: &domain.User{}}
+
+	err := s.db.QueryRow(`UPDATE ApiKeys k SET last_used_at = NOW()
+FROM Users u
+WHERE k.key_hash = $1 AND u.username = k.username
+RETURNING u.username, u.is_admin, k.scopes`, hashAPIKey(dto.Key)).
+		Scan(&user.User.Username, &user.User.IsAdmin, pq.Array(&user.Scopes))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: unknown or revoked api key", domain.ErrUnauthorized)
+	}
+	if err != nil {
+		return nil, dbError(err)
+	}
+
+	return &user, nil
+}
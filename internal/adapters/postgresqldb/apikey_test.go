@@ -0,0 +1,228 @@
+package postgresqldb
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+const testAPIKey = "flk_0123456789abcdefghijklmnopqrstuvwxyzABCDE"
+
+func TestAPIKeyCreate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("can't create mock: %s", err)
+	}
+	defer db.Close()
+
+	storage := NewAPIKeyStorage(db)
+	storage.key = func() (string, error) { return testAPIKey, nil }
+
+	dto := &domain.CreateAPIKey{Username: "user", Name: "ci import", Scopes: []string{"movies:write", "catalog:import"}}
+	created := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	// OK. Only the hash of the key is stored
+	mock.ExpectQuery(`INSERT INTO ApiKeys \(username, key_name, prefix, key_hash, scopes\)`).
+		WithArgs("user", "ci import", "flk_01234567", hashAPIKey(testAPIKey), "{\"movies:write\",\"catalog:import\"}").
+		WillReturnRows(sqlmock.NewRows([]string{"key_id", "created_at"}).AddRow(7, created))
+
+	got, err := storage.Create(dto)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := &domain.CreatedAPIKey{
+		APIKey: domain.APIKey{
+			ID:        7,
+			Username:  "user",
+			Name:      "ci import",
+			Prefix:    "flk_01234567",
+			Scopes:    dto.Scopes,
+			CreatedAt: created,
+		},
+		Key: testAPIKey,
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected: %+v, got: %+v", expected, got)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// Postgres returned error
+	mock.ExpectQuery(`INSERT INTO ApiKeys`).WillReturnError(domain.ErrTest)
+
+	if _, err = storage.Create(dto); err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// The key couldn't be generated
+	storage.key = func() (string, error) { return "", domain.ErrTest }
+
+	if _, err = storage.Create(dto); !errors.Is(err, domain.ErrTest) {
+		t.Errorf("expected ErrTest, got: %v", err)
+	}
+}
+
+func TestAPIKeyList(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("can't create mock: %s", err)
+	}
+	defer db.Close()
+
+	storage := NewAPIKeyStorage(db)
+
+	created := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	used := created.Add(time.Hour)
+	columns := []string{"key_id", "username", "key_name", "prefix", "scopes", "created_at", "last_used_at"}
+
+	// OK
+	mock.ExpectQuery(`SELECT key_id, username, key_name, prefix, scopes, created_at, last_used_at FROM ApiKeys WHERE username = \$1`).
+		WithArgs("user").
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(1, "user", "ci", "flk_01234567", "{movies:read}", created, used).
+			AddRow(2, "user", "export", "flk_abcdefgh", "{catalog:export}", created, nil))
+
+	list, err := storage.List(&domain.ListAPIKeys{Username: "user"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := &domain.APIKeyList{Keys: []domain.APIKey{
+		{ID: 1, Username: "user", Name: "ci", Prefix: "flk_01234567", Scopes: []string{"movies:read"}, CreatedAt: created, LastUsedAt: &used},
+		{ID: 2, Username: "user", Name: "export", Prefix: "flk_abcdefgh", Scopes: []string{"catalog:export"}, CreatedAt: created},
+	}}
+
+	if !reflect.DeepEqual(list, expected) {
+		t.Errorf("expected: %+v, got: %+v", expected, list)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// OK. No keys
+	mock.ExpectQuery(`SELECT .* FROM ApiKeys`).
+		WithArgs("user").
+		WillReturnRows(sqlmock.NewRows(columns))
+
+	list, err = storage.List(&domain.ListAPIKeys{Username: "user"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if list.Keys == nil || len(list.Keys) != 0 {
+		t.Errorf("expected an empty list, got: %+v", list.Keys)
+	}
+
+	// Postgres returned error
+	mock.ExpectQuery(`SELECT .* FROM ApiKeys`).WillReturnError(domain.ErrTest)
+
+	if _, err = storage.List(&domain.ListAPIKeys{Username: "user"}); err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestAPIKeyDelete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("can't create mock: %s", err)
+	}
+	defer db.Close()
+
+	storage := NewAPIKeyStorage(db)
+
+	// OK
+	mock.ExpectExec(`DELETE FROM ApiKeys WHERE key_id = \$1 AND \(\$2 = '' OR username = \$2\)`).
+		WithArgs(1, "user").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err = storage.Delete(&domain.DeleteAPIKey{ID: 1, Username: "user"}); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	// Key of someone else or missing
+	mock.ExpectExec(`DELETE FROM ApiKeys`).
+		WithArgs(1, "user").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	if err = storage.Delete(&domain.DeleteAPIKey{ID: 1, Username: "user"}); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got: %v", err)
+	}
+
+	// Postgres returned error
+	mock.ExpectExec(`DELETE FROM ApiKeys`).WillReturnError(domain.ErrTest)
+
+	if err = storage.Delete(&domain.DeleteAPIKey{ID: 1}); err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestAPIKeyAuthenticate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("can't create mock: %s", err)
+	}
+	defer db.Close()
+
+	storage := NewAPIKeyStorage(db)
+
+	// OK. The role is the current one of the owner
+	mock.ExpectQuery(`UPDATE ApiKeys k SET last_used_at = NOW\(\) FROM Users u WHERE k.key_hash = \$1`).
+		WithArgs(hashAPIKey(testAPIKey)).
+		WillReturnRows(sqlmock.NewRows([]string{"username", "is_admin", "scopes"}).
+			AddRow("admin", true, "{movies:read,catalog:export}"))
+
+	user, err := storage.Authenticate(&domain.GetAPIKey{Key: testAPIKey})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := &domain.ScopedUser{
+		User:   &domain.User{Username: "admin", IsAdmin: true},
+		Scopes: []string{"movies:read", "catalog:export"},
+	}
+
+	if !reflect.DeepEqual(user, expected) {
+		t.Errorf("expected: %+v, got: %+v", expected, user)
+	}
+
+	// Unknown or revoked key
+	mock.ExpectQuery(`UPDATE ApiKeys`).
+		WithArgs(hashAPIKey("flk_revoked")).
+		WillReturnRows(sqlmock.NewRows([]string{"username", "is_admin", "scopes"}))
+
+	if _, err = storage.Authenticate(&domain.GetAPIKey{Key: "flk_revoked"}); !errors.Is(err, domain.ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized, got: %v", err)
+	}
+
+	// Postgres returned error
+	mock.ExpectQuery(`UPDATE ApiKeys`).WillReturnError(domain.ErrTest)
+
+	if _, err = storage.Authenticate(&domain.GetAPIKey{Key: testAPIKey}); err == nil || errors.Is(err, domain.ErrUnauthorized) {
+		t.Errorf("expected a database error, got: %v", err)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
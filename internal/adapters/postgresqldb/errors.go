@@ -0,0 +1,44 @@
+package postgresqldb
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+	"github.com/lib/pq"
+)
+
+// pqErrors maps the Postgres error codes caused by the data a client sent
+// to the domain error kinds. The messages are ours rather than the server's,
+// which name tables and constraints and end up in responses.
+var pqErrors = map[pq.ErrorCode]struct {
+	kind    error
+	message string
+}{
+	"23505": {domain.ErrConflict, "already exists"},                      // unique_violation
+	"23503": {domain.ErrValidation, "refers to a missing record"},        // foreign_key_violation
+	"23514": {domain.ErrValidation, "value is out of the allowed range"}, // check_violation
+	"23502": {domain.ErrValidation, "required value is missing"},         // not_null_violation
+	"22P02": {domain.ErrValidation, "value has a wrong format"},          // invalid_text_representation
+	"22001": {domain.ErrValidation, "value is too long"},                 // string_data_right_truncation
+	"22007": {domain.ErrValidation, "date has a wrong format"},           // invalid_datetime_format
+	"22008": {domain.ErrValidation, "date is out of range"},              // datetime_field_overflow
+}
+
+// dbError translates a database error into a domain error, errors it knows
+// nothing about are returned as is.
+func dbError(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrNotFound
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		if e, ok := pqErrors[pqErr.Code]; ok {
+			return fmt.Errorf("%w: %s", e.kind, e.message)
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,155 @@
+package postgresqldb
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+)
+
+type exportStorage struct {
+	db *sql.DB
+}
+
+func NewExportStorage(db *sql.DB) *exportStorage {
+	return &exportStorage{
+		db: db,
+	}
+}
+
+// Export reads the whole catalogue into w row by row. It runs in a read-only
+// repeatable read transaction, so the counts of the manifest match the rows
+// that follow even while the catalogue is being edited.
+func (s *exportStorage) Export(w domain.ExportWriter) error {
+	tx, err := s.db.BeginTx(context.Background(), &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return dbError(err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	manifest := domain.ExportManifest{}
+	err = tx.QueryRow("SELECT (SELECT COALESCE(MAX(version), 0) FROM schema_migrations), now(), "+
+		"(SELECT count(*) FROM Movies), (SELECT count(*) FROM Actors), (SELECT count(*) FROM MovieActors)").
+		Scan(&manifest.SchemaVersion, &manifest.ExportedAt, &manifest.Movies, &manifest.Actors, &manifest.MovieActors)
+	if err != nil {
+		return dbError(err)
+	}
+
+	if err = w.WriteManifest(&manifest); err != nil {
+		return err
+	}
+
+	if err = exportActors(tx, w); err != nil {
+		return err
+	}
+
+	return exportMovies(tx, w)
+}
+
+func exportActors(tx *sql.Tx, w domain.ExportWriter) error {
+	rows, err := tx.Query("SELECT actor_id, external_id, actor_name, gender, date_of_birth FROM Actors ORDER BY actor_id")
+	if err != nil {
+		return dbError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		actor := exportActor{}
+		if err = rows.Scan(&actor.id, &actor.externalID, &actor.name, &actor.gender, &actor.dateBirth); err != nil {
+			return err
+		}
+
+		if err = w.WriteActor(actor.domain()); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// exportMovies reads the movies joined with their cast, ordered so that the
+// rows of a movie come one after another.
+func exportMovies(tx *sql.Tx, w domain.ExportWriter) error {
+	rows, err := tx.Query("SELECT m.movie_id, m.external_id, m.movie_title, m.description, m.release_date, m.rating, " +
+		"a.actor_id, a.external_id, a.actor_name, a.gender, a.date_of_birth " +
+		"FROM Movies m LEFT JOIN MovieActors ma ON ma.movie_id = m.movie_id LEFT JOIN Actors a ON a.actor_id = ma.actor_id " +
+		"ORDER BY m.movie_id, a.actor_id")
+	if err != nil {
+		return dbError(err)
+	}
+	defer rows.Close()
+
+	var movie *domain.ExportMovie
+	for rows.Next() {
+		var (
+			id          int64
+			externalID  sql.NullString
+			title       string
+			description sql.NullString
+			releaseDate sql.NullTime
+			rating      sql.NullInt64
+			actor       = exportActor{}
+			actorID     sql.NullInt64
+		)
+
+		err = rows.Scan(&id, &externalID, &title, &description, &releaseDate, &rating,
+			&actorID, &actor.externalID, &actor.name, &actor.gender, &actor.dateBirth)
+		if err != nil {
+			return err
+		}
+
+		if movie == nil || movie.ID != id {
+			if movie != nil {
+				if err = w.WriteMovie(movie); err != nil {
+					return err
+				}
+			}
+
+			movie = &domain.ExportMovie{
+				ID:          id,
+				ExternalID:  externalID.String,
+				Title:       title,
+				Description: description.String,
+				ReleaseDate: releaseDate.Time,
+				Rating:      uint8(rating.Int64),
+				Actors:      make([]domain.ExportActor, 0),
+			}
+		}
+
+		if actorID.Valid {
+			actor.id = actorID.Int64
+			movie.Actors = append(movie.Actors, *actor.domain())
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	if movie != nil {
+		return w.WriteMovie(movie)
+	}
+
+	return nil
+}
+
+// exportActor holds the nullable columns of an actor.
+type exportActor struct {
+	id         int64
+	externalID sql.NullString
+	name       sql.NullString
+	gender     sql.NullString
+	dateBirth  sql.NullTime
+}
+
+func (a *exportActor) domain() *domain.ExportActor {
+	return &domain.ExportActor{
+		ID:         a.id,
+		ExternalID: a.externalID.String,
+		Name:       a.name.String,
+		Gender:     a.gender.String,
+		DateBirth:  a.dateBirth.Time,
+	}
+}
@@ -0,0 +1,107 @@
+package postgresqldb
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+// exportRecorder keeps what an export writes.
+type exportRecorder struct {
+	manifest *domain.ExportManifest
+	actors   []domain.ExportActor
+	movies   []domain.ExportMovie
+}
+
+func (r *exportRecorder) WriteManifest(manifest *domain.ExportManifest) error {
+	r.manifest = manifest
+	return nil
+}
+
+func (r *exportRecorder) WriteActor(actor *domain.ExportActor) error {
+	r.actors = append(r.actors, *actor)
+	return nil
+}
+
+func (r *exportRecorder) WriteMovie(movie *domain.ExportMovie) error {
+	r.movies = append(r.movies, *movie)
+	return nil
+}
+
+func TestExport(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("can't create mock: %s", err)
+	}
+	defer db.Close()
+
+	storage := NewExportStorage(db)
+
+	exportedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	releaseDate := time.Date(1995, 12, 15, 0, 0, 0, 0, time.UTC)
+	dateBirth := time.Date(1940, 4, 25, 0, 0, 0, 0, time.UTC)
+
+	// OK, the joined rows of a movie are merged
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \(SELECT COALESCE\(MAX\(version\), 0\) FROM schema_migrations\), now\(\)`).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "now", "movies", "actors", "movie_actors"}).
+			AddRow(4, exportedAt, 2, 2, 2))
+	mock.ExpectQuery(`SELECT actor_id, external_id, actor_name, gender, date_of_birth FROM Actors ORDER BY actor_id`).
+		WillReturnRows(sqlmock.NewRows([]string{"actor_id", "external_id", "actor_name", "gender", "date_of_birth"}).
+			AddRow(1, "nm0000199", "Al Pacino", "Male", dateBirth).
+			AddRow(2, nil, "Robert De Niro", nil, nil))
+	mock.ExpectQuery(`SELECT m.movie_id, .* FROM Movies m LEFT JOIN MovieActors ma .* ORDER BY m.movie_id, a.actor_id`).
+		WillReturnRows(sqlmock.NewRows([]string{"movie_id", "external_id", "movie_title", "description", "release_date", "rating",
+			"actor_id", "external_id", "actor_name", "gender", "date_of_birth"}).
+			AddRow(1, "tt0113277", "Heat", "description", releaseDate, 8, 1, "nm0000199", "Al Pacino", "Male", dateBirth).
+			AddRow(1, "tt0113277", "Heat", "description", releaseDate, 8, 2, nil, "Robert De Niro", nil, nil).
+			AddRow(2, nil, "Ronin", nil, nil, nil, nil, nil, nil, nil, nil))
+	mock.ExpectRollback()
+
+	recorder := &exportRecorder{}
+	if err = storage.Export(recorder); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expectedManifest := &domain.ExportManifest{SchemaVersion: 4, ExportedAt: exportedAt, Movies: 2, Actors: 2, MovieActors: 2}
+	if !reflect.DeepEqual(recorder.manifest, expectedManifest) {
+		t.Errorf("expected: %+v, got: %+v", expectedManifest, recorder.manifest)
+	}
+
+	pacino := domain.ExportActor{ID: 1, ExternalID: "nm0000199", Name: "Al Pacino", Gender: "Male", DateBirth: dateBirth}
+	deNiro := domain.ExportActor{ID: 2, Name: "Robert De Niro"}
+
+	if !reflect.DeepEqual(recorder.actors, []domain.ExportActor{pacino, deNiro}) {
+		t.Errorf("unexpected actors: %+v", recorder.actors)
+	}
+
+	expectedMovies := []domain.ExportMovie{
+		{ID: 1, ExternalID: "tt0113277", Title: "Heat", Description: "description", ReleaseDate: releaseDate, Rating: 8,
+			Actors: []domain.ExportActor{pacino, deNiro}},
+		{ID: 2, Title: "Ronin", Actors: []domain.ExportActor{}},
+	}
+	if !reflect.DeepEqual(recorder.movies, expectedMovies) {
+		t.Errorf("expected: %+v, got: %+v", expectedMovies, recorder.movies)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// Postgres returned error
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \(SELECT COALESCE`).
+		WillReturnError(domain.ErrTest)
+	mock.ExpectRollback()
+
+	if err = storage.Export(&exportRecorder{}); err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,206 @@
+package postgresqldb
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+)
+
+// The facets a filter condition belongs to, a facet is counted without its
+// own conditions.
+const (
+	yearFacet   = "year"
+	ratingFacet = "rating"
+)
+
+// sqlParams numbers the parameters of a query in the order they are added.
+type sqlParams []interface{}
+
+func (p *sqlParams) add(value interface{}) string {
+	*p = append(*p, value)
+	return fmt.Sprintf("$%d", len(*p))
+}
+
+func (p *sqlParams) list(values []interface{}) string {
+	placeholders := make([]string, 0, len(values))
+	for _, value := range values {
+		placeholders = append(placeholders, p.add(value))
+	}
+
+	return strings.Join(placeholders, ", ")
+}
+
+// filterCond is a condition on a row of Movies. It is rendered with its
+// parameters each time it is used, as their numbers depend on the query.
+type filterCond struct {
+	facet string
+	sql   func(p *sqlParams) string
+}
+
+// getMovieFilter turns a movie filter into conditions on the rows of Movies.
+func getMovieFilter(f *domain.MovieFilter) ([]filterCond, error) {
+	conds := make([]filterCond, 0)
+	add := func(facet string, sql func(p *sqlParams) string) {
+		conds = append(conds, filterCond{facet: facet, sql: sql})
+	}
+
+	if f.FromYear != 0 {
+		add(yearFacet, func(p *sqlParams) string {
+			return fmt.Sprintf("release_date >= make_date(%s, 1, 1)", p.add(f.FromYear))
+		})
+	}
+	if f.ToYear != 0 {
+		add(yearFacet, func(p *sqlParams) string {
+			return fmt.Sprintf("release_date < make_date(%s, 1, 1)", p.add(f.ToYear+1))
+		})
+	}
+
+	if f.MinRating != 0 {
+		add(ratingFacet, func(p *sqlParams) string {
+			return "COALESCE(rating, 0) >= " + p.add(f.MinRating)
+		})
+	}
+	if f.MaxRating != 0 {
+		add(ratingFacet, func(p *sqlParams) string {
+			return "COALESCE(rating, 0) <= " + p.add(f.MaxRating)
+		})
+	}
+
+	if len(f.ActorIDs) > 0 {
+		ids := make([]interface{}, 0, len(f.ActorIDs))
+		for _, id := range f.ActorIDs {
+			ids = append(ids, id)
+		}
+
+		add("", func(p *sqlParams) string {
+			cond := fmt.Sprintf("movie_id IN (SELECT movie_id FROM MovieActors WHERE actor_id IN (%s)", p.list(ids))
+			if f.AllActors {
+				cond += " GROUP BY movie_id HAVING COUNT(*) = " + p.add(len(ids))
+			}
+			return cond + ")"
+		})
+	}
+
+	const castGender = "EXISTS (SELECT 1 FROM MovieActors ma JOIN Actors a ON ma.actor_id = a.actor_id " +
+		"WHERE ma.movie_id = Movies.movie_id AND a.gender "
+
+	for _, gender := range f.WithGenders {
+		gender := gender
+		add("", func(p *sqlParams) string {
+			return castGender + "= " + p.add(gender) + ")"
+		})
+	}
+
+	if len(f.WithoutGenders) > 0 {
+		genders := make([]interface{}, 0, len(f.WithoutGenders))
+		for _, gender := range f.WithoutGenders {
+			genders = append(genders, gender)
+		}
+
+		add("", func(p *sqlParams) string {
+			return "NOT " + castGender + "IN (" + p.list(genders) + "))"
+		})
+	}
+
+	if f.Query != "" {
+		column, ok := searchColumns[f.Language]
+		if !ok {
+			return nil, domain.NewFieldError(domain.ErrRequest, "lang", "unknown search language")
+		}
+
+		add("", func(p *sqlParams) string {
+			return fmt.Sprintf("%s @@ websearch_to_tsquery(%s, %s)", column, p.add(f.Language), p.add(f.Query))
+		})
+	}
+
+	return conds, nil
+}
+
+// renderFilter renders the conditions but the ones of the skipped facet,
+// an empty one skips none.
+func renderFilter(conds []filterCond, skip string, p *sqlParams) []string {
+	parts := make([]string, 0, len(conds))
+	for _, cond := range conds {
+		if skip == "" || cond.facet != skip {
+			parts = append(parts, cond.sql(p))
+		}
+	}
+
+	return parts
+}
+
+// and joins conditions, leaving out the ones that are always true.
+func and(conds ...string) string {
+	parts := make([]string, 0, len(conds))
+	for _, cond := range conds {
+		if cond != "TRUE" {
+			parts = append(parts, cond)
+		}
+	}
+
+	if len(parts) == 0 {
+		return "TRUE"
+	}
+
+	return strings.Join(parts, " AND ")
+}
+
+// getMovieFacets counts the movies matching the filter by release year, in
+// buckets of the given width, and by rating.
+func (s *movieStorage) getMovieFacets(conds []filterCond, bucket int) (*domain.MovieFacets, error) {
+	facets := domain.MovieFacets{
+		Years:   make([]domain.YearBucket, 0),
+		Ratings: make([]domain.RatingCount, 0),
+	}
+
+	params := sqlParams{}
+	size := params.add(bucket)
+	where := and(append([]string{"release_date IS NOT NULL"}, renderFilter(conds, yearFacet, &params)...)...)
+
+	rows, err := s.db.Query(fmt.Sprintf("SELECT EXTRACT(YEAR FROM release_date)::int / %[1]s * %[1]s AS bucket, COUNT(*) "+
+		"FROM Movies WHERE %[2]s GROUP BY bucket ORDER BY bucket", size, where), params...)
+	if err != nil {
+		return nil, dbError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		year := domain.YearBucket{}
+		if err = rows.Scan(&year.From, &year.Count); err != nil {
+			return nil, dbError(err)
+		}
+
+		year.To = year.From + bucket - 1
+		facets.Years = append(facets.Years, year)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, dbError(err)
+	}
+
+	params = sqlParams{}
+	where = and(renderFilter(conds, ratingFacet, &params)...)
+
+	rows, err = s.db.Query("SELECT COALESCE(rating, 0) AS rating, COUNT(*) FROM Movies WHERE "+where+
+		" GROUP BY 1 ORDER BY 1", params...)
+	if err != nil {
+		return nil, dbError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		rating := domain.RatingCount{}
+		if err = rows.Scan(&rating.Rating, &rating.Count); err != nil {
+			return nil, dbError(err)
+		}
+
+		facets.Ratings = append(facets.Ratings, rating)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, dbError(err)
+	}
+
+	return &facets, nil
+}
@@ -0,0 +1,164 @@
+package postgresqldb
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestMovieGetOrderedListFiltered(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("can't create mock: %s", err)
+	}
+	defer db.Close()
+
+	storage := NewMovieStorage(db)
+
+	dto := &domain.GetOrderedMovie{
+		Sort: []domain.SortField{{Field: "title"}},
+		Page: domain.Page{Limit: 10},
+		Filter: domain.MovieFilter{
+			FromYear:       1990,
+			ToYear:         1999,
+			MinRating:      7,
+			ActorIDs:       []int64{4, 9},
+			AllActors:      true,
+			WithGenders:    []string{"Female"},
+			WithoutGenders: []string{"Male"},
+			Query:          "heist",
+			Language:       "english",
+		},
+		Facets:     true,
+		YearBucket: 5,
+	}
+
+	const (
+		years   = "release_date >= make_date($%d, 1, 1) AND release_date < make_date($%d, 1, 1)"
+		rating  = "COALESCE(rating, 0) >= $%d"
+		actors  = "movie_id IN (SELECT movie_id FROM MovieActors WHERE actor_id IN ($%d, $%d) GROUP BY movie_id HAVING COUNT(*) = $%d)"
+		cast    = "EXISTS (SELECT 1 FROM MovieActors ma JOIN Actors a ON ma.actor_id = a.actor_id WHERE ma.movie_id = Movies.movie_id AND a.gender "
+		genders = cast + "= $%d) AND NOT " + cast + "IN ($%d))"
+		text    = "search_english @@ websearch_to_tsquery($%d, $%d)"
+	)
+	sprintf := func(format string, n ...int) string {
+		args := make([]interface{}, 0, len(n))
+		for _, i := range n {
+			args = append(args, i)
+		}
+		return regexp.QuoteMeta(fmt.Sprintf(format, args...))
+	}
+
+	// OK. Every filter, with facets
+	mock.ExpectQuery(sprintf("SELECT movie_id, movie_title, description, release_date, rating FROM Movies WHERE "+
+		years+" AND "+rating+" AND "+actors+" AND "+genders+" AND "+text+" ORDER BY movie_title ASC, movie_id ASC LIMIT $11",
+		1, 2, 3, 4, 5, 6, 7, 8, 9, 10)).
+		WithArgs(1990, 2000, 7, 4, 9, 2, "Female", "Male", "english", "heist", 11).
+		WillReturnRows(sqlmock.NewRows([]string{"movie_id", "movie_title", "description", "release_date", "rating"}).
+			AddRow(1, "Heat", "Heist", nil, 8))
+	mock.ExpectQuery(sprintf("SELECT EXTRACT(YEAR FROM release_date)::int / $1 * $1 AS bucket, COUNT(*) FROM Movies "+
+		"WHERE release_date IS NOT NULL AND "+rating+" AND "+actors+" AND "+genders+" AND "+text+" GROUP BY bucket ORDER BY bucket",
+		2, 3, 4, 5, 6, 7, 8, 9)).
+		WithArgs(5, 7, 4, 9, 2, "Female", "Male", "english", "heist").
+		WillReturnRows(sqlmock.NewRows([]string{"bucket", "count"}).AddRow(1990, 3).AddRow(1995, 1))
+	mock.ExpectQuery(sprintf("SELECT COALESCE(rating, 0) AS rating, COUNT(*) FROM Movies "+
+		"WHERE "+years+" AND "+actors+" AND "+genders+" AND "+text+" GROUP BY 1 ORDER BY 1",
+		1, 2, 3, 4, 5, 6, 7, 8, 9)).
+		WithArgs(1990, 2000, 4, 9, 2, "Female", "Male", "english", "heist").
+		WillReturnRows(sqlmock.NewRows([]string{"rating", "count"}).AddRow(0, 1).AddRow(8, 3))
+
+	list, err := storage.GetOrderedList(dto)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(list.Movies) != 1 || list.Movies[0].Title != "Heat" {
+		t.Errorf("unexpected movies: %v", list.Movies)
+	}
+
+	expectedFacets := &domain.MovieFacets{
+		Years:   []domain.YearBucket{{From: 1990, To: 1994, Count: 3}, {From: 1995, To: 1999, Count: 1}},
+		Ratings: []domain.RatingCount{{Rating: 0, Count: 1}, {Rating: 8, Count: 3}},
+	}
+	if !reflect.DeepEqual(expectedFacets, list.Facets) {
+		t.Errorf("expected: %v, got: %v", expectedFacets, list.Facets)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// OK. Any of the actors, after a cursor, no facets
+	dto = &domain.GetOrderedMovie{
+		Sort:   []domain.SortField{{Field: "title"}},
+		Page:   domain.Page{Limit: 1},
+		Filter: domain.MovieFilter{ActorIDs: []int64{4}},
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT movie_id, movie_title, description, release_date, rating FROM Movies WHERE "+
+		"movie_id IN (SELECT movie_id FROM MovieActors WHERE actor_id IN ($1)) ORDER BY movie_title ASC, movie_id ASC LIMIT $2")).
+		WithArgs(4, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"movie_id", "movie_title", "description", "release_date", "rating"}).
+			AddRow(1, "Heat", "Heist", nil, 8).
+			AddRow(2, "Ronin", "Heist", nil, 7))
+
+	list, err = storage.GetOrderedList(dto)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if list.Facets != nil || list.Next == "" {
+		t.Errorf("expected a next cursor and no facets, got: %v", list)
+	}
+
+	dto.Page.After = list.Next
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT movie_id, movie_title, description, release_date, rating FROM Movies WHERE "+
+		"movie_id IN (SELECT movie_id FROM MovieActors WHERE actor_id IN ($1)) AND (movie_title, movie_id) > ($2, $3) "+
+		"ORDER BY movie_title ASC, movie_id ASC LIMIT $4")).
+		WithArgs(4, "Heat", 1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"movie_id", "movie_title", "description", "release_date", "rating"}).
+			AddRow(2, "Ronin", "Heist", nil, 7))
+
+	if _, err = storage.GetOrderedList(dto); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// Unknown language
+	_, err = storage.GetOrderedList(&domain.GetOrderedMovie{
+		Sort:   []domain.SortField{{Field: "title"}},
+		Page:   domain.Page{Limit: 1},
+		Filter: domain.MovieFilter{Query: "heist", Language: "klingon"},
+	})
+	if !errors.Is(err, domain.ErrRequest) {
+		t.Errorf("expected: %v, got: %v", domain.ErrRequest, err)
+	}
+
+	// Postgres returned error counting facets
+	mock.ExpectQuery("SELECT movie_id").
+		WillReturnRows(sqlmock.NewRows([]string{"movie_id", "movie_title", "description", "release_date", "rating"}))
+	mock.ExpectQuery("SELECT EXTRACT").
+		WillReturnError(domain.ErrTest)
+
+	_, err = storage.GetOrderedList(&domain.GetOrderedMovie{
+		Sort:       []domain.SortField{{Field: "title"}},
+		Page:       domain.Page{Limit: 1},
+		Facets:     true,
+		YearBucket: 10,
+	})
+	if err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
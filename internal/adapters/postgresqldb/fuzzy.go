@@ -0,0 +1,131 @@
+package postgresqldb
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+)
+
+// getSqlForFuzzySearch builds the FROM/WHERE part of a fuzzy movie search
+// together with its score, the trigram word similarity of the title plus the
+// one of the closest cast member. The <% operator matches the terms against
+// the pg_trgm.word_similarity_threshold of the transaction, which lets it use
+// the trigram indexes.
+func getSqlForFuzzySearch(dto *domain.GetMovie) (string, string, []interface{}) {
+	var (
+		from   = "FROM Movies m"
+		where  = ""
+		scores = make([]string, 0, 2)
+		params = make([]interface{}, 0, 2)
+	)
+
+	if dto.ActorName != "" {
+		params = append(params, strings.ToLower(dto.ActorName))
+		from += fmt.Sprintf(" JOIN (SELECT ma.movie_id, MAX(word_similarity($%[1]d, LOWER(a.actor_name))) AS actor_similarity "+
+			"FROM MovieActors ma JOIN Actors a ON ma.actor_id = a.actor_id "+
+			"WHERE $%[1]d <%% LOWER(a.actor_name) GROUP BY ma.movie_id) ar ON ar.movie_id = m.movie_id", len(params))
+		scores = append(scores, "ar.actor_similarity")
+	}
+
+	if dto.Title != "" {
+		params = append(params, strings.ToLower(dto.Title))
+		where = fmt.Sprintf(" WHERE $%d <%% LOWER(m.movie_title)", len(params))
+		scores = append(scores, fmt.Sprintf("word_similarity($%d, LOWER(m.movie_title))", len(params)))
+	}
+
+	return from + where, strings.Join(scores, " + "), params
+}
+
+// getFuzzy fills page with the movies matching the terms of dto at least as
+// closely as its similarity threshold, the most similar first, and suggests
+// the known title and actor name closest to each term.
+func (s *movieStorage) getFuzzy(dto *domain.GetMovie, page *domain.MoviePage) (*domain.MoviePage, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, dbError(err)
+	}
+	// Nothing is written, the transaction only scopes the threshold.
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	threshold := strconv.FormatFloat(dto.Similarity, 'f', -1, 64)
+	if _, err = tx.Exec("SELECT set_config('pg_trgm.word_similarity_threshold', $1, true)", threshold); err != nil {
+		return nil, dbError(err)
+	}
+
+	suggestion := domain.DidYouMean{}
+	if dto.Title != "" {
+		if suggestion.Title, err = suggest(tx, "Movies", "movie_title", dto.Title); err != nil {
+			return nil, err
+		}
+	}
+	if dto.ActorName != "" {
+		if suggestion.Actor, err = suggest(tx, "Actors", "actor_name", dto.ActorName); err != nil {
+			return nil, err
+		}
+	}
+
+	if suggestion.Title != "" && !strings.EqualFold(suggestion.Title, dto.Title) ||
+		suggestion.Actor != "" && !strings.EqualFold(suggestion.Actor, dto.ActorName) {
+		page.DidYouMean = &suggestion
+	}
+
+	page.Fuzzy = true
+	from, score, params := getSqlForFuzzySearch(dto)
+
+	if err = tx.QueryRow("SELECT COUNT(*) "+from, params...).Scan(&page.Total); err != nil {
+		return nil, dbError(err)
+	}
+
+	if page.Total == 0 {
+		return page, nil
+	}
+
+	query := fmt.Sprintf("SELECT m.movie_id, m.movie_title, m.description, m.release_date, m.rating %s "+
+		"ORDER BY %s DESC, m.rating DESC, m.movie_id LIMIT $%d OFFSET $%d", from, score, len(params)+1, len(params)+2)
+	rows, err := tx.Query(query, append(params, dto.Limit, dto.Offset)...)
+	if err != nil {
+		return nil, dbError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		movie := domain.Movie{}
+
+		if err = scanMovie(rows, &movie); err != nil {
+			return nil, dbError(err)
+		}
+
+		page.Movies = append(page.Movies, movie)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, dbError(err)
+	}
+
+	return page, nil
+}
+
+// suggest returns the value of column closest to term, or an empty string
+// when none is similar enough. Ties on word similarity go to the value most
+// similar as a whole, so a short title doesn't win over the one meant.
+func suggest(tx *sql.Tx, table, column, term string) (string, error) {
+	var value string
+
+	err := tx.QueryRow(fmt.Sprintf("SELECT %[2]s FROM %[1]s WHERE $1 <%% LOWER(%[2]s) "+
+		"ORDER BY word_similarity($1, LOWER(%[2]s)) DESC, similarity($1, LOWER(%[2]s)) DESC, %[2]s LIMIT 1", table, column),
+		strings.ToLower(term)).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", dbError(err)
+	}
+
+	return value, nil
+}
@@ -0,0 +1,145 @@
+package postgresqldb
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestMovieGetFuzzy(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("can't create mock: %s", err)
+	}
+	defer db.Close()
+
+	storage := NewMovieStorage(db)
+
+	const (
+		setThreshold  = "SELECT set_config('pg_trgm.word_similarity_threshold', $1, true)"
+		suggestTitle  = "SELECT movie_title FROM Movies WHERE $1 <% LOWER(movie_title) ORDER BY word_similarity($1, LOWER(movie_title)) DESC"
+		suggestActor  = "SELECT actor_name FROM Actors WHERE $1 <% LOWER(actor_name) ORDER BY word_similarity($1, LOWER(actor_name)) DESC"
+		fuzzyActorSQL = "FROM Movies m JOIN (SELECT ma.movie_id, MAX(word_similarity($1, LOWER(a.actor_name))) AS actor_similarity " +
+			"FROM MovieActors ma JOIN Actors a ON ma.actor_id = a.actor_id WHERE $1 <% LOWER(a.actor_name) GROUP BY ma.movie_id) ar ON ar.movie_id = m.movie_id"
+	)
+	columns := []string{"movie_id", "movie_title", "description", "release_date", "rating"}
+	expectTime := time.Now()
+
+	// OK. Misspelt actor, most similar first
+	dto := &domain.GetMovie{
+		ActorName:  "Di Caprio",
+		Similarity: 0.4,
+		Limit:      20,
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM Movies m JOIN")).
+		WithArgs("di caprio", "di caprio").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(setThreshold)).
+		WithArgs("0.4").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(suggestActor)).
+		WithArgs("di caprio").
+		WillReturnRows(sqlmock.NewRows([]string{"actor_name"}).AddRow("Leonardo DiCaprio"))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) " + fuzzyActorSQL)).
+		WithArgs("di caprio").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT m.movie_id, m.movie_title, m.description, m.release_date, m.rating "+fuzzyActorSQL+
+		" ORDER BY ar.actor_similarity DESC, m.rating DESC, m.movie_id LIMIT $2 OFFSET $3")).
+		WithArgs("di caprio", 20, 0).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(1, "Titanic", "Ship", expectTime, 8).
+			AddRow(2, "Inception", nil, nil, nil))
+	mock.ExpectRollback()
+
+	expectedPage := &domain.MoviePage{
+		Movies: []domain.Movie{
+			{ID: 1, Title: "Titanic", Description: "Ship", ReleaseDate: expectTime, Rating: 8},
+			{ID: 2, Title: "Inception"},
+		},
+		Total:      2,
+		Limit:      20,
+		Fuzzy:      true,
+		DidYouMean: &domain.DidYouMean{Actor: "Leonardo DiCaprio"},
+	}
+
+	page, err := storage.Get(dto)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	if !reflect.DeepEqual(expectedPage, page) {
+		t.Errorf("expected: %v, got: %v", expectedPage, page)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// OK. Nothing similar, the title as typed is no suggestion
+	dto = &domain.GetMovie{
+		Title:      "titanic",
+		ActorName:  "Nobody",
+		Similarity: 0.75,
+		Limit:      20,
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM Movies m JOIN")).
+		WithArgs("nobody", "nobody", "titanic", "titanic").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(setThreshold)).
+		WithArgs("0.75").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(suggestTitle)).
+		WithArgs("titanic").
+		WillReturnRows(sqlmock.NewRows([]string{"movie_title"}).AddRow("Titanic"))
+	mock.ExpectQuery(regexp.QuoteMeta(suggestActor)).
+		WithArgs("nobody").
+		WillReturnRows(sqlmock.NewRows([]string{"actor_name"}))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) "+fuzzyActorSQL+" WHERE $2 <% LOWER(m.movie_title)")).
+		WithArgs("nobody", "titanic").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectRollback()
+
+	page, err = storage.Get(dto)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	if page == nil || !page.Fuzzy || page.Total != 0 || len(page.Movies) != 0 || page.DidYouMean != nil {
+		t.Errorf("expected an empty fuzzy page, got: %v", page)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// Postgres returned error
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM Movies m WHERE")).
+		WithArgs("tytanic", "tytanic").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(setThreshold)).
+		WithArgs("0.4").
+		WillReturnError(domain.ErrTest)
+	mock.ExpectRollback()
+
+	page, err = storage.Get(&domain.GetMovie{Title: "Tytanic", Similarity: 0.4, Limit: 20})
+	if err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	if page != nil {
+		t.Errorf("expected nil, got: %v", page)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,180 @@
+package postgresqldb
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+	"github.com/lib/pq"
+)
+
+// The files are copied into temporary tables first and merged with a few
+// statements, which is what makes loading millions of rows fast.
+var createIMDbTables = []string{
+	"CREATE TEMP TABLE imdb_titles (tconst TEXT, title TEXT, start_year INT) ON COMMIT DROP",
+	"CREATE TEMP TABLE imdb_principals (tconst TEXT, nconst TEXT, gender gender) ON COMMIT DROP",
+	"CREATE TEMP TABLE imdb_names (nconst TEXT, name TEXT, birth_year INT) ON COMMIT DROP",
+}
+
+// keepDate keeps a stored date when the dataset, which only knows the year,
+// agrees with it or doesn't know the year at all.
+func keepDate(stored, year string) string {
+	return fmt.Sprintf("CASE WHEN %[2]s IS NULL OR date_part('year', %[1]s) = date_part('year', %[2]s) THEN %[1]s ELSE %[2]s END",
+		stored, year)
+}
+
+var (
+	mergeIMDbMovies = fmt.Sprintf(`WITH upserted AS (
+	INSERT INTO Movies (movie_title, release_date, external_id)
+	SELECT title, make_date(start_year, 1, 1), tconst FROM imdb_titles
+	ON CONFLICT (external_id) DO UPDATE SET movie_title = EXCLUDED.movie_title, release_date = %[1]s, version = Movies.version + 1
+	WHERE (Movies.movie_title, Movies.release_date) IS DISTINCT FROM (EXCLUDED.movie_title, %[1]s)
+	RETURNING xmax = 0 AS created
+)
+SELECT count(*) FILTER (WHERE created), count(*) FILTER (WHERE NOT created) FROM upserted`,
+		keepDate("Movies.release_date", "EXCLUDED.release_date"))
+
+	mergeIMDbActors = fmt.Sprintf(`WITH upserted AS (
+	INSERT INTO Actors (actor_name, gender, date_of_birth, external_id)
+	SELECT n.name, g.gender, make_date(n.birth_year, 1, 1), n.nconst FROM imdb_names n
+	LEFT JOIN (SELECT nconst, mode() WITHIN GROUP (ORDER BY gender) AS gender FROM imdb_principals GROUP BY nconst) g
+	ON g.nconst = n.nconst
+	ON CONFLICT (external_id) DO UPDATE SET actor_name = EXCLUDED.actor_name, gender = COALESCE(EXCLUDED.gender, Actors.gender),
+	date_of_birth = %[1]s, version = Actors.version + 1
+	WHERE (Actors.actor_name, Actors.gender, Actors.date_of_birth) IS DISTINCT FROM
+	(EXCLUDED.actor_name, COALESCE(EXCLUDED.gender, Actors.gender), %[1]s)
+	RETURNING xmax = 0 AS created
+)
+SELECT count(*) FILTER (WHERE created), count(*) FILTER (WHERE NOT created) FROM upserted`,
+		keepDate("Actors.date_of_birth", "EXCLUDED.date_of_birth"))
+
+	// The movies whose cast grew get a new version, like any other edit.
+	linkIMDbActors = `WITH linked AS (
+	INSERT INTO MovieActors (movie_id, actor_id)
+	SELECT DISTINCT m.movie_id, a.actor_id FROM imdb_principals p
+	JOIN Movies m ON m.external_id = p.tconst
+	JOIN Actors a ON a.external_id = p.nconst
+	ON CONFLICT DO NOTHING
+	RETURNING movie_id
+), bumped AS (
+	UPDATE Movies SET version = version + 1 WHERE movie_id IN (SELECT movie_id FROM linked)
+)
+SELECT count(*) FROM linked`
+)
+
+type imdbStorage struct {
+	db *sql.DB
+}
+
+func NewIMDbStorage(db *sql.DB) *imdbStorage {
+	return &imdbStorage{
+		db: db,
+	}
+}
+
+// ImportIMDb loads a dataset within one transaction. Movies and actors are
+// matched by their tconst and nconst kept as external IDs: the missing ones
+// are created, and the title, name, gender and year of the others updated.
+func (s *imdbStorage) ImportIMDb(source domain.IMDbSource) (report *domain.IMDbReport, err error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, dbError(err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+		if err = tx.Commit(); err != nil {
+			report, err = nil, dbError(err)
+		}
+	}()
+
+	for _, create := range createIMDbTables {
+		if _, err = tx.Exec(create); err != nil {
+			return nil, dbError(err)
+		}
+	}
+
+	report = &domain.IMDbReport{}
+
+	report.Titles, err = copyRows(tx, "imdb_titles", []string{"tconst", "title", "start_year"}, func(put func(...interface{}) error) error {
+		return source.Titles(func(title *domain.IMDbTitle) error {
+			return put(title.TConst, title.Title, nullYear(title.Year))
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report.Principals, err = copyRows(tx, "imdb_principals", []string{"tconst", "nconst", "gender"}, func(put func(...interface{}) error) error {
+		return source.Principals(func(principal *domain.IMDbPrincipal) error {
+			return put(principal.TConst, principal.NConst, principal.Gender)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report.Names, err = copyRows(tx, "imdb_names", []string{"nconst", "name", "birth_year"}, func(put func(...interface{}) error) error {
+		return source.Names(func(name *domain.IMDbName) error {
+			return put(name.NConst, name.Name, nullYear(name.BirthYear))
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Temporary tables are left out by autovacuum, the planner needs their
+	// statistics to pick hash joins over millions of rows.
+	if _, err = tx.Exec("ANALYZE imdb_titles, imdb_principals, imdb_names"); err != nil {
+		return nil, dbError(err)
+	}
+
+	if err = tx.QueryRow(mergeIMDbMovies).Scan(&report.MoviesCreated, &report.MoviesUpdated); err != nil {
+		return nil, dbError(err)
+	}
+
+	if err = tx.QueryRow(mergeIMDbActors).Scan(&report.ActorsCreated, &report.ActorsUpdated); err != nil {
+		return nil, dbError(err)
+	}
+
+	if err = tx.QueryRow(linkIMDbActors).Scan(&report.LinksCreated); err != nil {
+		return nil, dbError(err)
+	}
+
+	return report, nil
+}
+
+// copyRows streams the rows fill puts into a table with COPY and returns
+// how many there were.
+func copyRows(tx *sql.Tx, table string, columns []string, fill func(put func(values ...interface{}) error) error) (int64, error) {
+	stmt, err := tx.Prepare(pq.CopyIn(table, columns...))
+	if err != nil {
+		return 0, dbError(err)
+	}
+
+	var count int64
+	err = fill(func(values ...interface{}) error {
+		count++
+		if _, err := stmt.Exec(values...); err != nil {
+			return dbError(err)
+		}
+		return nil
+	})
+	if err != nil {
+		_ = stmt.Close()
+		return 0, err
+	}
+
+	if _, err = stmt.Exec(); err != nil {
+		_ = stmt.Close()
+		return 0, dbError(err)
+	}
+
+	return count, stmt.Close()
+}
+
+func nullYear(year int) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(year), Valid: year != 0}
+}
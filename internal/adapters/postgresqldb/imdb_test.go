@@ -0,0 +1,128 @@
+package postgresqldb
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+// imdbRows is a dataset already read into memory.
+type imdbRows struct {
+	titles     []domain.IMDbTitle
+	principals []domain.IMDbPrincipal
+	names      []domain.IMDbName
+}
+
+func (r *imdbRows) Titles(fn func(title *domain.IMDbTitle) error) error {
+	for i := range r.titles {
+		if err := fn(&r.titles[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *imdbRows) Principals(fn func(principal *domain.IMDbPrincipal) error) error {
+	for i := range r.principals {
+		if err := fn(&r.principals[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *imdbRows) Names(fn func(name *domain.IMDbName) error) error {
+	for i := range r.names {
+		if err := fn(&r.names[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func expectIMDbTables(mock sqlmock.Sqlmock) {
+	for _, table := range []string{"imdb_titles", "imdb_principals", "imdb_names"} {
+		mock.ExpectExec(`CREATE TEMP TABLE ` + table).WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+}
+
+func TestImportIMDb(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("can't create mock: %s", err)
+	}
+	defer db.Close()
+
+	storage := NewIMDbStorage(db)
+
+	source := &imdbRows{
+		titles: []domain.IMDbTitle{
+			{TConst: "tt0113277", Title: "Heat", Year: 1995},
+			{TConst: "tt0000001", Title: "Unknown"},
+		},
+		principals: []domain.IMDbPrincipal{{TConst: "tt0113277", NConst: "nm0000199", Gender: "Male"}},
+		names:      []domain.IMDbName{{NConst: "nm0000199", Name: "Al Pacino", BirthYear: 1940}},
+	}
+
+	// OK
+	mock.ExpectBegin()
+	expectIMDbTables(mock)
+	titles := mock.ExpectPrepare(`COPY "imdb_titles" \("tconst", "title", "start_year"\) FROM STDIN`)
+	titles.ExpectExec().WithArgs("tt0113277", "Heat", 1995).WillReturnResult(sqlmock.NewResult(0, 0))
+	titles.ExpectExec().WithArgs("tt0000001", "Unknown", nil).WillReturnResult(sqlmock.NewResult(0, 0))
+	titles.ExpectExec().WillReturnResult(sqlmock.NewResult(0, 2))
+	principals := mock.ExpectPrepare(`COPY "imdb_principals" \("tconst", "nconst", "gender"\) FROM STDIN`)
+	principals.ExpectExec().WithArgs("tt0113277", "nm0000199", "Male").WillReturnResult(sqlmock.NewResult(0, 0))
+	principals.ExpectExec().WillReturnResult(sqlmock.NewResult(0, 1))
+	names := mock.ExpectPrepare(`COPY "imdb_names" \("nconst", "name", "birth_year"\) FROM STDIN`)
+	names.ExpectExec().WithArgs("nm0000199", "Al Pacino", 1940).WillReturnResult(sqlmock.NewResult(0, 0))
+	names.ExpectExec().WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`ANALYZE imdb_titles, imdb_principals, imdb_names`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`INSERT INTO Movies \(movie_title, release_date, external_id\)`).
+		WillReturnRows(sqlmock.NewRows([]string{"created", "updated"}).AddRow(1, 1))
+	mock.ExpectQuery(`INSERT INTO Actors \(actor_name, gender, date_of_birth, external_id\)`).
+		WillReturnRows(sqlmock.NewRows([]string{"created", "updated"}).AddRow(1, 0))
+	mock.ExpectQuery(`INSERT INTO MovieActors \(movie_id, actor_id\)`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectCommit()
+
+	report, err := storage.ImportIMDb(source)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expectedReport := &domain.IMDbReport{
+		Titles:        2,
+		Principals:    1,
+		Names:         1,
+		MoviesCreated: 1,
+		MoviesUpdated: 1,
+		ActorsCreated: 1,
+		LinksCreated:  1,
+	}
+
+	if !reflect.DeepEqual(report, expectedReport) {
+		t.Errorf("expected: %+v, got: %+v", expectedReport, report)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// Copy failed, nothing is kept
+	mock.ExpectBegin()
+	expectIMDbTables(mock)
+	titles = mock.ExpectPrepare(`COPY "imdb_titles"`)
+	titles.ExpectExec().WithArgs("tt0113277", "Heat", 1995).WillReturnError(domain.ErrTest)
+	mock.ExpectRollback()
+
+	if _, err = storage.ImportIMDb(source); err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,325 @@
+package postgresqldb
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+)
+
+const defaultImportBatch = 100
+
+type importStorage struct {
+	db *sql.DB
+}
+
+func NewImportStorage(db *sql.DB) *importStorage {
+	return &importStorage{
+		db: db,
+	}
+}
+
+// ImportMovies imports the rows batch by batch, each batch in a transaction
+// of its own, so a failure of the database keeps the batches before it. A row
+// the database rejects is rolled back to a savepoint and reported as failed
+// without affecting the rest of the batch. A dry run is a single batch rolled
+// back at the end, so that a row sees the actors and movies the rows before
+// it would have created, the same as in a real run.
+func (s *importStorage) ImportMovies(dto *domain.ImportMovies) (*domain.ImportReport, error) {
+	batch := dto.BatchSize
+	if batch <= 0 {
+		batch = defaultImportBatch
+	}
+	if dto.DryRun && len(dto.Rows) > 0 {
+		batch = len(dto.Rows)
+	}
+
+	report := domain.ImportReport{
+		DryRun: dto.DryRun,
+		Rows:   make([]domain.ImportResult, 0, len(dto.Rows)),
+	}
+
+	for start := 0; start < len(dto.Rows); start += batch {
+		end := start + batch
+		if end > len(dto.Rows) {
+			end = len(dto.Rows)
+		}
+
+		results, err := s.importBatch(dto.Rows[start:end], dto.DryRun)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, result := range results {
+			report.Add(result)
+		}
+	}
+
+	return &report, nil
+}
+
+func (s *importStorage) importBatch(rows []domain.ImportRow, dryRun bool) (results []domain.ImportResult, err error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, dbError(err)
+	}
+	defer func() {
+		if err != nil || dryRun {
+			_ = tx.Rollback()
+			return
+		}
+		if err = tx.Commit(); err != nil {
+			results, err = nil, dbError(err)
+		}
+	}()
+
+	results = make([]domain.ImportResult, 0, len(rows))
+	for _, row := range rows {
+		result := domain.ImportResult{
+			Row:        row.Row,
+			ExternalID: row.Movie.ExternalID,
+			Title:      row.Movie.Title,
+		}
+		if row.Actor != nil {
+			result.ExternalID, result.Title, result.Name = row.Actor.ExternalID, "", row.Actor.Name
+		}
+
+		if row.Err != nil {
+			result.Status, result.Error = domain.ImportFailed, row.Err.Error()
+			results = append(results, result)
+			continue
+		}
+
+		if _, err = tx.Exec("SAVEPOINT import_row"); err != nil {
+			return nil, dbError(err)
+		}
+
+		var (
+			status string
+			rowErr error
+		)
+		if row.Actor != nil {
+			_, status, rowErr = importActor(tx, row.Actor, "")
+		} else {
+			status, rowErr = importMovie(tx, &row.Movie)
+		}
+		if rowErr != nil && !isRowError(rowErr) {
+			return nil, rowErr
+		}
+
+		if rowErr != nil {
+			result.Status, result.Error = domain.ImportFailed, rowErr.Error()
+			_, err = tx.Exec("ROLLBACK TO SAVEPOINT import_row")
+		} else {
+			result.Status = status
+			_, err = tx.Exec("RELEASE SAVEPOINT import_row")
+		}
+		if err != nil {
+			return nil, dbError(err)
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// isRowError tells the errors caused by the data of a row from the failures
+// of the database itself.
+func isRowError(err error) bool {
+	for _, kind := range []error{domain.ErrValidation, domain.ErrConflict, domain.ErrNotFound, domain.ErrRequest} {
+		if errors.Is(err, kind) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// importMovie upserts a movie and its cast and links them, the cast links
+// already in place are kept.
+func importMovie(tx *sql.Tx, movie *domain.ImportMovie) (string, error) {
+	actorsChanged := false
+	actorIDs := make([]int64, 0, len(movie.Actors))
+
+	for i := range movie.Actors {
+		id, status, err := importActor(tx, &movie.Actors[i], fmt.Sprintf("actors[%d].", i))
+		if err != nil {
+			return "", err
+		}
+
+		actorsChanged = actorsChanged || status != domain.ImportSkipped
+		actorIDs = append(actorIDs, id)
+	}
+
+	id, status, err := importMovieRow(tx, movie)
+	if err != nil {
+		return "", err
+	}
+
+	linked := false
+	if len(actorIDs) > 0 {
+		cmd, params, err := getSqlForMovieActors(actorIDs, id)
+		if err != nil {
+			return "", err
+		}
+
+		result, err := tx.Exec(cmd+" ON CONFLICT DO NOTHING", params...)
+		if err != nil {
+			return "", dbError(err)
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return "", err
+		}
+		linked = affected > 0
+	}
+
+	if status == domain.ImportSkipped && (actorsChanged || linked) {
+		if _, err = tx.Exec("UPDATE Movies SET version = version + 1 WHERE movie_id = $1", id); err != nil {
+			return "", dbError(err)
+		}
+		status = domain.ImportUpdated
+	}
+
+	return status, nil
+}
+
+// importMovieRow creates the movie or updates the fields that differ. A zero
+// release date or rating is stored as NULL.
+func importMovieRow(tx *sql.Tx, movie *domain.ImportMovie) (int64, string, error) {
+	var (
+		id          int64
+		title       string
+		description sql.NullString
+		releaseDate sql.NullTime
+		rating      sql.NullInt64
+		err         error
+	)
+
+	var date, score interface{}
+	if !movie.ReleaseDate.IsZero() {
+		date = movie.ReleaseDate.Format("2006-01-02")
+	}
+	if movie.Rating != 0 {
+		score = movie.Rating
+	}
+
+	query := "SELECT movie_id, movie_title, description, release_date, rating FROM Movies WHERE "
+	if movie.ExternalID != "" {
+		err = tx.QueryRow(query+"external_id = $1", movie.ExternalID).
+			Scan(&id, &title, &description, &releaseDate, &rating)
+	} else {
+		err = tx.QueryRow(query+"movie_title = $1 AND release_date IS NOT DISTINCT FROM $2 ORDER BY movie_id LIMIT 1", movie.Title, date).
+			Scan(&id, &title, &description, &releaseDate, &rating)
+	}
+
+	if errors.Is(err, sql.ErrNoRows) {
+		err = tx.QueryRow("INSERT INTO Movies (movie_title, description, release_date, rating, external_id) "+
+			"VALUES ($1, $2, $3, $4, COALESCE(NULLIF($5, ''), local_external_id())) RETURNING movie_id",
+			movie.Title, movie.Description, date, score, movie.ExternalID).Scan(&id)
+		if err != nil {
+			return 0, "", dbError(err)
+		}
+
+		return id, domain.ImportCreated, nil
+	}
+	if err != nil {
+		return 0, "", dbError(err)
+	}
+
+	sets := patchSet{}
+	if title != movie.Title {
+		sets.add("movie_title", movie.Title)
+	}
+	if description.String != movie.Description {
+		sets.add("description", movie.Description)
+	}
+	if releaseDate.Valid != (date != nil) || releaseDate.Valid && releaseDate.Time.Format("2006-01-02") != date {
+		sets.add("release_date", date)
+	}
+	if rating.Valid != (score != nil) || rating.Int64 != int64(movie.Rating) {
+		sets.add("rating", score)
+	}
+
+	if len(sets.sets) == 0 {
+		return id, domain.ImportSkipped, nil
+	}
+
+	update, params := sets.query("Movies", "movie_id", id, 0)
+	if _, err = tx.Exec(update, params...); err != nil {
+		return 0, "", dbError(err)
+	}
+
+	return id, domain.ImportUpdated, nil
+}
+
+// importActor finds an actor, creating it or updating the fields the file
+// gives. A new actor without a date of birth gets NULL for it. prefix names
+// the actor in the errors, such as actors[0]. for a cast member of a movie.
+func importActor(tx *sql.Tx, actor *domain.ImportActor, prefix string) (int64, string, error) {
+	var (
+		id        int64
+		name      string
+		gender    sql.NullString
+		dateBirth sql.NullTime
+		err       error
+	)
+
+	query := "SELECT actor_id, actor_name, gender, date_of_birth FROM Actors WHERE "
+	if actor.ExternalID != "" {
+		err = tx.QueryRow(query+"external_id = $1", actor.ExternalID).Scan(&id, &name, &gender, &dateBirth)
+	} else {
+		err = tx.QueryRow(query+"actor_name = $1 ORDER BY actor_id LIMIT 1", actor.Name).Scan(&id, &name, &gender, &dateBirth)
+	}
+
+	if errors.Is(err, sql.ErrNoRows) {
+		if actor.Name == "" {
+			return 0, "", domain.NewFieldError(domain.ErrValidation, prefix+"actor_name", "is required for a new actor")
+		}
+		if actor.Gender == "" {
+			return 0, "", domain.NewFieldError(domain.ErrValidation, prefix+"gender", "is required for a new actor")
+		}
+
+		var birth interface{}
+		if !actor.DateBirth.IsZero() {
+			birth = actor.DateBirth
+		}
+
+		err = tx.QueryRow("INSERT INTO Actors (actor_name, gender, date_of_birth, external_id) "+
+			"VALUES ($1, $2, $3, COALESCE(NULLIF($4, ''), local_external_id())) RETURNING actor_id",
+			actor.Name, actor.Gender, birth, actor.ExternalID).Scan(&id)
+		if err != nil {
+			return 0, "", dbError(err)
+		}
+
+		return id, domain.ImportCreated, nil
+	}
+	if err != nil {
+		return 0, "", dbError(err)
+	}
+
+	sets := patchSet{}
+	if actor.Name != "" && actor.Name != name {
+		sets.add("actor_name", actor.Name)
+	}
+	if actor.Gender != "" && actor.Gender != gender.String {
+		sets.add("gender", actor.Gender)
+	}
+	if !actor.DateBirth.IsZero() && (!dateBirth.Valid || dateBirth.Time.Format("2006-01-02") != actor.DateBirth.Format("2006-01-02")) {
+		sets.add("date_of_birth", actor.DateBirth)
+	}
+
+	if len(sets.sets) == 0 {
+		return id, domain.ImportSkipped, nil
+	}
+
+	update, params := sets.query("Actors", "actor_id", id, 0)
+	if _, err = tx.Exec(update, params...); err != nil {
+		return 0, "", dbError(err)
+	}
+
+	return id, domain.ImportUpdated, nil
+}
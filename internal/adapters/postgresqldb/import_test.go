@@ -0,0 +1,396 @@
+package postgresqldb
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+const (
+	selectActorByName = `SELECT actor_id, actor_name, gender, date_of_birth FROM Actors WHERE actor_name = \$1 ORDER BY actor_id LIMIT 1`
+	selectActorByID   = `SELECT actor_id, actor_name, gender, date_of_birth FROM Actors WHERE external_id = \$1`
+	selectMovieByID   = `SELECT movie_id, movie_title, description, release_date, rating FROM Movies WHERE external_id = \$1`
+	selectMovieByName = `SELECT movie_id, movie_title, description, release_date, rating FROM Movies WHERE movie_title = \$1 AND release_date IS NOT DISTINCT FROM \$2`
+	insertActor       = `INSERT INTO Actors \(actor_name, gender, date_of_birth, external_id\) VALUES \(\$1, \$2, \$3, COALESCE\(NULLIF\(\$4, ''\), local_external_id\(\)\)\) RETURNING actor_id`
+	insertMovie       = `INSERT INTO Movies \(movie_title, description, release_date, rating, external_id\) VALUES \(\$1, \$2, \$3, \$4, COALESCE\(NULLIF\(\$5, ''\), local_external_id\(\)\)\) RETURNING movie_id`
+	linkActors        = `INSERT INTO MovieActors \(movie_id, actor_id\) VALUES \(\$1, \$2\) ON CONFLICT DO NOTHING`
+)
+
+func TestImportMovies(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("can't create mock: %s", err)
+	}
+	defer db.Close()
+
+	storage := NewImportStorage(db)
+
+	releaseDate := time.Date(1995, 12, 15, 0, 0, 0, 0, time.UTC)
+	dateBirth := time.Date(1940, 4, 25, 0, 0, 0, 0, time.UTC)
+
+	heat := domain.ImportMovie{
+		ExternalID:  "tt0113277",
+		Title:       "Heat",
+		Description: "description",
+		ReleaseDate: releaseDate,
+		Rating:      8,
+		Actors:      []domain.ImportActor{{Name: "Al Pacino", Gender: "Male", DateBirth: dateBirth}},
+	}
+	ronin := domain.ImportMovie{
+		Title:       "Ronin",
+		ReleaseDate: time.Date(1998, 9, 25, 0, 0, 0, 0, time.UTC),
+		Rating:      7,
+		Actors:      []domain.ImportActor{{ExternalID: "nm0000134"}},
+	}
+
+	dto := domain.ImportMovies{
+		Rows: []domain.ImportRow{
+			{Row: 1, Movie: heat},
+			{Row: 2, Movie: ronin},
+			{Row: 3, Movie: domain.ImportMovie{Title: "Broken"}, Err: domain.ErrRequest},
+		},
+		BatchSize: 2,
+	}
+
+	// First batch: Heat and its actor are created, Ronin's actor is unknown
+	mock.ExpectBegin()
+	mock.ExpectExec(`SAVEPOINT import_row`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(selectActorByName).
+		WithArgs("Al Pacino").
+		WillReturnRows(sqlmock.NewRows([]string{"actor_id", "actor_name", "gender", "date_of_birth"}))
+	mock.ExpectQuery(insertActor).
+		WithArgs("Al Pacino", "Male", dateBirth, "").
+		WillReturnRows(sqlmock.NewRows([]string{"actor_id"}).AddRow(5))
+	mock.ExpectQuery(selectMovieByID).
+		WithArgs("tt0113277").
+		WillReturnRows(sqlmock.NewRows([]string{"movie_id", "movie_title", "description", "release_date", "rating"}))
+	mock.ExpectQuery(insertMovie).
+		WithArgs("Heat", "description", "1995-12-15", heat.Rating, "tt0113277").
+		WillReturnRows(sqlmock.NewRows([]string{"movie_id"}).AddRow(9))
+	mock.ExpectExec(linkActors).
+		WithArgs(9, 5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`RELEASE SAVEPOINT import_row`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`SAVEPOINT import_row`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(selectActorByID).
+		WithArgs("nm0000134").
+		WillReturnRows(sqlmock.NewRows([]string{"actor_id", "actor_name", "gender", "date_of_birth"}))
+	mock.ExpectExec(`ROLLBACK TO SAVEPOINT import_row`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	// Second batch: the row that couldn't be read
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	report, err := storage.ImportMovies(&dto)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expectedReport := &domain.ImportReport{
+		Created: 1,
+		Failed:  2,
+		Rows: []domain.ImportResult{
+			{Row: 1, ExternalID: "tt0113277", Title: "Heat", Status: domain.ImportCreated},
+			{Row: 2, Title: "Ronin", Status: domain.ImportFailed, Error: "validation failed: actors[0].actor_name: is required for a new actor"},
+			{Row: 3, Title: "Broken", Status: domain.ImportFailed, Error: domain.ErrRequest.Error()},
+		},
+	}
+
+	if !reflect.DeepEqual(report, expectedReport) {
+		t.Errorf("expected: %+v, got: %+v", expectedReport, report)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// Dry run: Heat is there already and unchanged, Ronin's actor gets a new name
+	dto = domain.ImportMovies{
+		Rows:   []domain.ImportRow{{Row: 1, Movie: heat}, {Row: 2, Movie: ronin}},
+		DryRun: true,
+	}
+	dto.Rows[1].Movie.Actors = []domain.ImportActor{{ExternalID: "nm0000134", Name: "Robert De Niro"}}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`SAVEPOINT import_row`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(selectActorByName).
+		WithArgs("Al Pacino").
+		WillReturnRows(sqlmock.NewRows([]string{"actor_id", "actor_name", "gender", "date_of_birth"}).
+			AddRow(5, "Al Pacino", "Male", dateBirth))
+	mock.ExpectQuery(selectMovieByID).
+		WithArgs("tt0113277").
+		WillReturnRows(sqlmock.NewRows([]string{"movie_id", "movie_title", "description", "release_date", "rating"}).
+			AddRow(9, "Heat", "description", releaseDate, 8))
+	mock.ExpectExec(linkActors).
+		WithArgs(9, 5).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`RELEASE SAVEPOINT import_row`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`SAVEPOINT import_row`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(selectActorByID).
+		WithArgs("nm0000134").
+		WillReturnRows(sqlmock.NewRows([]string{"actor_id", "actor_name", "gender", "date_of_birth"}).
+			AddRow(6, "R. De Niro", nil, nil))
+	mock.ExpectExec(`UPDATE Actors SET actor_name = \$1, version = version \+ 1 WHERE actor_id = \$2`).
+		WithArgs("Robert De Niro", 6).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(selectMovieByName).
+		WithArgs("Ronin", "1998-09-25").
+		WillReturnRows(sqlmock.NewRows([]string{"movie_id", "movie_title", "description", "release_date", "rating"}).
+			AddRow(10, "Ronin", nil, ronin.ReleaseDate, 7))
+	mock.ExpectExec(linkActors).
+		WithArgs(10, 6).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`UPDATE Movies SET version = version \+ 1 WHERE movie_id = \$1`).
+		WithArgs(10).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`RELEASE SAVEPOINT import_row`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	report, err = storage.ImportMovies(&dto)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expectedReport = &domain.ImportReport{
+		DryRun:  true,
+		Updated: 1,
+		Skipped: 1,
+		Rows: []domain.ImportResult{
+			{Row: 1, ExternalID: "tt0113277", Title: "Heat", Status: domain.ImportSkipped},
+			{Row: 2, Title: "Ronin", Status: domain.ImportUpdated},
+		},
+	}
+
+	if !reflect.DeepEqual(report, expectedReport) {
+		t.Errorf("expected: %+v, got: %+v", expectedReport, report)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// Dry run: an actor created by an earlier row is found by the later ones
+	// whatever the batch size
+	pacino := domain.ImportActor{Name: "Al Pacino", Gender: "Male"}
+	dto = domain.ImportMovies{
+		Rows: []domain.ImportRow{
+			{Row: 1, Actor: &pacino},
+			{Row: 2, Actor: &pacino},
+		},
+		BatchSize: 1,
+		DryRun:    true,
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`SAVEPOINT import_row`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(selectActorByName).
+		WithArgs("Al Pacino").
+		WillReturnRows(sqlmock.NewRows([]string{"actor_id", "actor_name", "gender", "date_of_birth"}))
+	mock.ExpectQuery(insertActor).
+		WithArgs("Al Pacino", "Male", nil, "").
+		WillReturnRows(sqlmock.NewRows([]string{"actor_id"}).AddRow(5))
+	mock.ExpectExec(`RELEASE SAVEPOINT import_row`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`SAVEPOINT import_row`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(selectActorByName).
+		WithArgs("Al Pacino").
+		WillReturnRows(sqlmock.NewRows([]string{"actor_id", "actor_name", "gender", "date_of_birth"}).
+			AddRow(5, "Al Pacino", "Male", nil))
+	mock.ExpectExec(`RELEASE SAVEPOINT import_row`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	report, err = storage.ImportMovies(&dto)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expectedReport = &domain.ImportReport{
+		DryRun:  true,
+		Created: 1,
+		Skipped: 1,
+		Rows: []domain.ImportResult{
+			{Row: 1, Name: "Al Pacino", Status: domain.ImportCreated},
+			{Row: 2, Name: "Al Pacino", Status: domain.ImportSkipped},
+		},
+	}
+
+	if !reflect.DeepEqual(report, expectedReport) {
+		t.Errorf("expected: %+v, got: %+v", expectedReport, report)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// An actor of its own, without a date of birth
+	dto = domain.ImportMovies{
+		Rows: []domain.ImportRow{{Row: 1, Actor: &domain.ImportActor{ExternalID: "nm0000199", Name: "Al Pacino", Gender: "Male"}}},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`SAVEPOINT import_row`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(selectActorByID).
+		WithArgs("nm0000199").
+		WillReturnRows(sqlmock.NewRows([]string{"actor_id", "actor_name", "gender", "date_of_birth"}))
+	mock.ExpectQuery(insertActor).
+		WithArgs("Al Pacino", "Male", nil, "nm0000199").
+		WillReturnRows(sqlmock.NewRows([]string{"actor_id"}).AddRow(5))
+	mock.ExpectExec(`RELEASE SAVEPOINT import_row`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	report, err = storage.ImportMovies(&dto)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expectedReport = &domain.ImportReport{
+		Created: 1,
+		Rows:    []domain.ImportResult{{Row: 1, ExternalID: "nm0000199", Name: "Al Pacino", Status: domain.ImportCreated}},
+	}
+
+	if !reflect.DeepEqual(report, expectedReport) {
+		t.Errorf("expected: %+v, got: %+v", expectedReport, report)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// Namesakes of an export are told apart by their external IDs
+	dto = domain.ImportMovies{
+		Rows: []domain.ImportRow{
+			{Row: 1, Actor: &domain.ImportActor{ExternalID: "filmlibrary:a", Name: "John Smith", Gender: "Male"}},
+			{Row: 2, Actor: &domain.ImportActor{ExternalID: "filmlibrary:b", Name: "John Smith", Gender: "Male"}},
+		},
+	}
+
+	mock.ExpectBegin()
+	for i, externalID := range []string{"filmlibrary:a", "filmlibrary:b"} {
+		mock.ExpectExec(`SAVEPOINT import_row`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(selectActorByID).
+			WithArgs(externalID).
+			WillReturnRows(sqlmock.NewRows([]string{"actor_id", "actor_name", "gender", "date_of_birth"}).
+				AddRow(i+1, "John Smith", "Male", nil))
+		mock.ExpectExec(`RELEASE SAVEPOINT import_row`).WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+	mock.ExpectCommit()
+
+	report, err = storage.ImportMovies(&dto)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if report.Skipped != 2 || report.Created != 0 {
+		t.Errorf("expected both actors skipped, got: %+v", report)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// Postgres returned error, the import stops
+	dto = domain.ImportMovies{Rows: []domain.ImportRow{{Row: 1, Movie: heat}}}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`SAVEPOINT import_row`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(selectActorByName).
+		WithArgs("Al Pacino").
+		WillReturnError(domain.ErrTest)
+	mock.ExpectRollback()
+
+	if _, err = storage.ImportMovies(&dto); err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestImportMoviesWithoutRating(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("can't create mock: %s", err)
+	}
+	defer db.Close()
+
+	storage := NewImportStorage(db)
+
+	ronin := domain.ImportMovie{ExternalID: "tt0122690", Title: "Ronin", Actors: []domain.ImportActor{}}
+	dto := domain.ImportMovies{Rows: []domain.ImportRow{{Row: 1, Movie: ronin}}}
+
+	// OK. Created with a NULL rating and release date
+	mock.ExpectBegin()
+	mock.ExpectExec(`SAVEPOINT import_row`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(selectMovieByID).
+		WithArgs("tt0122690").
+		WillReturnRows(sqlmock.NewRows([]string{"movie_id", "movie_title", "description", "release_date", "rating"}))
+	mock.ExpectQuery(insertMovie).
+		WithArgs("Ronin", "", nil, nil, "tt0122690").
+		WillReturnRows(sqlmock.NewRows([]string{"movie_id"}).AddRow(10))
+	mock.ExpectExec(`RELEASE SAVEPOINT import_row`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	report, err := storage.ImportMovies(&dto)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if report.Created != 1 {
+		t.Errorf("expected 1 created, got: %+v", report)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// OK. Imported again it is unchanged
+	mock.ExpectBegin()
+	mock.ExpectExec(`SAVEPOINT import_row`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(selectMovieByID).
+		WithArgs("tt0122690").
+		WillReturnRows(sqlmock.NewRows([]string{"movie_id", "movie_title", "description", "release_date", "rating"}).
+			AddRow(10, "Ronin", nil, nil, nil))
+	mock.ExpectExec(`RELEASE SAVEPOINT import_row`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	if report, err = storage.ImportMovies(&dto); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if report.Skipped != 1 {
+		t.Errorf("expected 1 skipped, got: %+v", report)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// The rating of a movie rated before is cleared
+	mock.ExpectBegin()
+	mock.ExpectExec(`SAVEPOINT import_row`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(selectMovieByID).
+		WithArgs("tt0122690").
+		WillReturnRows(sqlmock.NewRows([]string{"movie_id", "movie_title", "description", "release_date", "rating"}).
+			AddRow(10, "Ronin", nil, nil, 7))
+	mock.ExpectExec(`UPDATE Movies SET rating = \$1, version = version \+ 1 WHERE movie_id = \$2`).
+		WithArgs(nil, 10).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`RELEASE SAVEPOINT import_row`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	if report, err = storage.ImportMovies(&dto); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if report.Updated != 1 {
+		t.Errorf("expected 1 updated, got: %+v", report)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
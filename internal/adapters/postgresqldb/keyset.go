@@ -0,0 +1,275 @@
+package postgresqldb
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"strings"
+	"time"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+)
+
+// keysetKind is the type of the values of a sort column, which the values of
+// a cursor are checked against before they go into the query.
+type keysetKind int
+
+const (
+	keysetInt keysetKind = iota
+	keysetText
+	keysetDate
+)
+
+type keysetColumn struct {
+	name string
+	kind keysetKind
+	desc bool
+}
+
+// keyset paginates a query by the values of its ORDER BY columns instead of
+// OFFSET, so every page costs O(limit) however deep into the listing it is.
+// The columns must end with a unique key to make the order total.
+type keyset struct {
+	columns  []keysetColumn
+	limit    int
+	values   []interface{}
+	backward bool
+}
+
+// cursor is the opaque position of a row: the values of its sort columns and a
+// checksum of the order they belong to.
+type cursor struct {
+	Order  uint32        `json:"o"`
+	Values []interface{} `json:"v"`
+}
+
+func newKeyset(columns []keysetColumn, page domain.Page) (*keyset, error) {
+	k := &keyset{
+		columns: columns,
+		limit:   page.Limit,
+	}
+
+	token := page.After
+	if page.Before != "" {
+		if page.After != "" {
+			return nil, fmt.Errorf("%w: after and before are mutually exclusive", domain.ErrRequest)
+		}
+		token, k.backward = page.Before, true
+	}
+
+	if token == "" {
+		return k, nil
+	}
+
+	values, err := k.decode(token)
+	if err != nil {
+		return nil, err
+	}
+	k.values = values
+
+	return k, nil
+}
+
+func (k *keyset) order(reverse bool) string {
+	parts := make([]string, 0, len(k.columns))
+
+	for _, column := range k.columns {
+		if column.desc != reverse {
+			parts = append(parts, column.name+" DESC")
+		} else {
+			parts = append(parts, column.name+" ASC")
+		}
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// orderBy returns the ORDER BY list of the query. Pages before a cursor are
+// fetched in reverse and flipped back by keysetPage.
+func (k *keyset) orderBy() string {
+	return k.order(k.backward)
+}
+
+// where returns the predicate selecting the rows past the cursor, with the
+// parameters numbered from $n. When all the columns go the same way it is a
+// row comparison, which an index on the columns serves directly, such as
+// (movie_title, movie_id) > ($n, $n+1). Mixed directions have to be spelled
+// out, for (rating DESC, movie_id ASC) it is
+// (rating < $n OR (rating = $n AND movie_id > $n+1)).
+func (k *keyset) where(n int) (string, []interface{}) {
+	if k.values == nil {
+		return "TRUE", nil
+	}
+
+	if k.uniform() {
+		op := ">"
+		if k.columns[0].desc != k.backward {
+			op = "<"
+		}
+
+		names := make([]string, 0, len(k.columns))
+		params := make([]string, 0, len(k.columns))
+		for i, column := range k.columns {
+			names = append(names, column.name)
+			params = append(params, fmt.Sprintf("$%d", n+i))
+		}
+
+		return fmt.Sprintf("(%s) %s (%s)", strings.Join(names, ", "), op, strings.Join(params, ", ")), k.values
+	}
+
+	terms := make([]string, 0, len(k.columns))
+
+	for i, column := range k.columns {
+		op := ">"
+		if column.desc != k.backward {
+			op = "<"
+		}
+
+		conds := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			conds = append(conds, fmt.Sprintf("%s = $%d", k.columns[j].name, n+j))
+		}
+		conds = append(conds, fmt.Sprintf("%s %s $%d", column.name, op, n+i))
+
+		terms = append(terms, "("+strings.Join(conds, " AND ")+")")
+	}
+
+	return "(" + strings.Join(terms, " OR ") + ")", k.values
+}
+
+// uniform tells whether all the columns are sorted in the same direction.
+func (k *keyset) uniform() bool {
+	for _, column := range k.columns {
+		if column.desc != k.columns[0].desc {
+			return false
+		}
+	}
+
+	return true
+}
+
+// fetch is the LIMIT of the query: one row more than the page to find out
+// whether there is anything past it.
+func (k *keyset) fetch() int {
+	return k.limit + 1
+}
+
+func (k *keyset) encode(values []interface{}) (string, error) {
+	data, err := json.Marshal(cursor{
+		Order:  crc32.ChecksumIEEE([]byte(k.order(false))),
+		Values: values,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func (k *keyset) decode(token string) ([]interface{}, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", domain.ErrRequest)
+	}
+
+	c := cursor{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	if err = decoder.Decode(&c); err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", domain.ErrRequest)
+	}
+
+	if c.Order != crc32.ChecksumIEEE([]byte(k.order(false))) || len(c.Values) != len(k.columns) {
+		return nil, fmt.Errorf("%w: cursor does not match the sort order", domain.ErrRequest)
+	}
+
+	values := make([]interface{}, 0, len(c.Values))
+	for i, column := range k.columns {
+		value, ok := column.kind.value(c.Values[i])
+		if !ok {
+			return nil, fmt.Errorf("%w: malformed cursor", domain.ErrRequest)
+		}
+
+		values = append(values, value)
+	}
+
+	return values, nil
+}
+
+// value converts a value decoded from a cursor to the parameter of a column
+// of the kind, it is false for a value of another type.
+func (kind keysetKind) value(v interface{}) (interface{}, bool) {
+	switch kind {
+	case keysetInt:
+		number, ok := v.(json.Number)
+		if !ok {
+			return nil, false
+		}
+
+		n, err := number.Int64()
+		if err != nil {
+			return nil, false
+		}
+
+		return n, true
+	case keysetText:
+		text, ok := v.(string)
+		return text, ok
+	case keysetDate:
+		text, ok := v.(string)
+		if !ok {
+			return nil, false
+		}
+
+		date, err := time.Parse(time.RFC3339Nano, text)
+		if err != nil {
+			return nil, false
+		}
+
+		return date.Format("2006-01-02"), true
+	}
+
+	return nil, false
+}
+
+// keysetPage trims the look-ahead row, restores the requested order and builds
+// the cursors of the neighbouring pages from the key values of the edge rows.
+func keysetPage[T any](k *keyset, rows []T, key func(T) []interface{}) ([]T, domain.Cursors, error) {
+	cursors := domain.Cursors{}
+
+	more := len(rows) > k.limit
+	if more {
+		rows = rows[:k.limit]
+	}
+
+	hasNext, hasPrev := more, k.values != nil
+	if k.backward {
+		hasNext, hasPrev = k.values != nil, more
+
+		for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+			rows[i], rows[j] = rows[j], rows[i]
+		}
+	}
+
+	if len(rows) == 0 {
+		return rows, cursors, nil
+	}
+
+	var err error
+	if hasNext {
+		if cursors.Next, err = k.encode(key(rows[len(rows)-1])); err != nil {
+			return nil, cursors, err
+		}
+	}
+
+	if hasPrev {
+		if cursors.Prev, err = k.encode(key(rows[0])); err != nil {
+			return nil, cursors, err
+		}
+	}
+
+	return rows, cursors, nil
+}
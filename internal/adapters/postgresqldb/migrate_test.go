@@ -0,0 +1,283 @@
+package postgresqldb
+
+import (
+	"context"
+	"reflect"
+	"regexp"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+var testMigrations = fstest.MapFS{
+	"0001_init.up.sql":       {Data: []byte("CREATE TABLE a (id INT)")},
+	"0001_init.down.sql":     {Data: []byte("DROP TABLE a")},
+	"0002_second.up.sql":     {Data: []byte("CREATE TABLE b (id INT)")},
+	"0002_second.down.sql":   {Data: []byte("DROP TABLE b")},
+	"0010_numbered.up.sql":   {Data: []byte("CREATE TABLE c (id INT)")},
+	"0010_numbered.down.sql": {Data: []byte("DROP TABLE c")},
+}
+
+func expectLock(mock sqlmock.Sqlmock) {
+	mock.ExpectExec(`SELECT pg_advisory_lock\(\$1\)`).
+		WithArgs(migrationLock).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`CREATE TABLE IF NOT EXISTS schema_migrations`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func expectUnlock(mock sqlmock.Sqlmock) {
+	mock.ExpectExec(`SELECT pg_advisory_unlock\(\$1\)`).
+		WithArgs(migrationLock).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func TestLoadMigrations(t *testing.T) {
+	// Embedded migrations
+	db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("can't create mock: %s", err)
+	}
+	defer db.Close()
+
+	m, err := NewMigrator(context.Background(), db)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for i, migration := range m.migrations {
+		if migration.Version != int64(i+1) {
+			t.Errorf("expected version %d, got: %d", i+1, migration.Version)
+		}
+	}
+
+	// Ordered by version
+	migrations, err := loadMigrations(testMigrations)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	versions := make([]int64, 0, len(migrations))
+	for _, migration := range migrations {
+		versions = append(versions, migration.Version)
+	}
+
+	if !reflect.DeepEqual(versions, []int64{1, 2, 10}) {
+		t.Errorf("expected versions 1, 2, 10, got: %v", versions)
+	}
+
+	// Broken sets of files
+	for name, files := range map[string]fstest.MapFS{
+		"missing down":  {"0001_init.up.sql": {}},
+		"bad name":      {"init.sql": {}},
+		"taken version": {"0001_a.up.sql": {}, "0001_a.down.sql": {}, "0001_b.up.sql": {}, "0001_b.down.sql": {}},
+		"zero version":  {"0000_a.up.sql": {}, "0000_a.down.sql": {}},
+	} {
+		if _, err = loadMigrations(files); err == nil {
+			t.Errorf("%s: expected error, got nil", name)
+		}
+	}
+}
+
+func TestMigratorUp(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("can't create mock: %s", err)
+	}
+	defer db.Close()
+
+	m, err := newMigrator(context.Background(), db, testMigrations)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// OK, the first migration is applied already
+	expectLock(mock)
+	mock.ExpectQuery(`SELECT version, applied_at FROM schema_migrations`).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}).AddRow(1, time.Now()))
+	for _, migration := range m.migrations[1:] {
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(migration.up)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`INSERT INTO schema_migrations \(version, name\) VALUES \(\$1, \$2\)`).
+			WithArgs(migration.Version, migration.Name).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+	}
+	expectUnlock(mock)
+
+	applied, err := m.Up()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if !reflect.DeepEqual(applied, m.migrations[1:]) {
+		t.Errorf("expected %v, got: %v", m.migrations[1:], applied)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// Script failed, the migrations before it stay applied
+	expectLock(mock)
+	mock.ExpectQuery(`SELECT version, applied_at FROM schema_migrations`).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}).AddRow(1, time.Now()))
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(m.migrations[1].up)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`INSERT INTO schema_migrations`).
+		WithArgs(m.migrations[1].Version, m.migrations[1].Name).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(m.migrations[2].up)).
+		WillReturnError(domain.ErrTest)
+	mock.ExpectRollback()
+	expectUnlock(mock)
+
+	applied, err = m.Up()
+	if err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	if len(applied) != 1 || applied[0].Version != 2 {
+		t.Errorf("expected migration 2 applied, got: %v", applied)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// Lock returned error
+	mock.ExpectExec(`SELECT pg_advisory_lock\(\$1\)`).
+		WithArgs(migrationLock).
+		WillReturnError(domain.ErrTest)
+
+	if _, err = m.Up(); err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestMigratorDown(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("can't create mock: %s", err)
+	}
+	defer db.Close()
+
+	m, err := newMigrator(context.Background(), db, testMigrations)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// OK, the latest applied migration goes first
+	expectLock(mock)
+	mock.ExpectQuery(`SELECT version, applied_at FROM schema_migrations`).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}).AddRow(1, time.Now()).AddRow(2, time.Now()))
+	for _, migration := range []Migration{m.migrations[1], m.migrations[0]} {
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(migration.down)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`DELETE FROM schema_migrations WHERE version = \$1`).
+			WithArgs(migration.Version).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+	}
+	expectUnlock(mock)
+
+	rolledBack, err := m.Down(5)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(rolledBack) != 2 || rolledBack[0].Version != 2 || rolledBack[1].Version != 1 {
+		t.Errorf("expected migrations 2 and 1 rolled back, got: %v", rolledBack)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// Nothing applied
+	expectLock(mock)
+	mock.ExpectQuery(`SELECT version, applied_at FROM schema_migrations`).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}))
+	expectUnlock(mock)
+
+	rolledBack, err = m.Down(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(rolledBack) != 0 {
+		t.Errorf("expected nothing rolled back, got: %v", rolledBack)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestMigratorStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("can't create mock: %s", err)
+	}
+	defer db.Close()
+
+	m, err := newMigrator(context.Background(), db, testMigrations)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	appliedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	// OK
+	expectLock(mock)
+	mock.ExpectQuery(`SELECT version, applied_at FROM schema_migrations`).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}).AddRow(1, appliedAt))
+	expectUnlock(mock)
+
+	statuses, err := m.Status()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(statuses) != 3 {
+		t.Fatalf("expected 3 migrations, got: %d", len(statuses))
+	}
+
+	if statuses[0].AppliedAt == nil || !statuses[0].AppliedAt.Equal(appliedAt) {
+		t.Errorf("expected migration 1 applied at %s, got: %v", appliedAt, statuses[0].AppliedAt)
+	}
+
+	if statuses[1].AppliedAt != nil || statuses[2].AppliedAt != nil {
+		t.Errorf("expected migrations 2 and 10 pending, got: %v", statuses[1:])
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// Postgres returned error
+	expectLock(mock)
+	mock.ExpectQuery(`SELECT version, applied_at FROM schema_migrations`).
+		WillReturnError(domain.ErrTest)
+	expectUnlock(mock)
+
+	if _, err = m.Status(); err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS Users;
+DROP TABLE IF EXISTS MovieActors;
+DROP TABLE IF EXISTS Movies;
+DROP TABLE IF EXISTS Actors;
+DROP TYPE IF EXISTS gender;
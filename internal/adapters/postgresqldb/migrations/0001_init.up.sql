@@ -1,13 +1,21 @@
-CREATE TYPE gender AS ENUM ('Male', 'Female');
+-- The schema deploy/init.sql used to create. It is idempotent, so databases
+-- initialised by that script are adopted as they are.
+DO $$
+BEGIN
+    CREATE TYPE gender AS ENUM ('Male', 'Female');
+EXCEPTION
+    WHEN duplicate_object THEN NULL;
+END
+$$;
 
-CREATE TABLE Actors (
+CREATE TABLE IF NOT EXISTS Actors (
     actor_id SERIAL PRIMARY KEY,
     actor_name VARCHAR(100) NOT NULL,
     gender gender,
     date_of_birth DATE
 );
 
-CREATE TABLE Movies (
+CREATE TABLE IF NOT EXISTS Movies (
     movie_id SERIAL PRIMARY KEY,
     movie_title VARCHAR(150) NOT NULL,
     description VARCHAR(1000),
@@ -15,7 +23,7 @@ CREATE TABLE Movies (
     rating INTEGER CHECK (rating BETWEEN 1 AND 10)
 );
 
-CREATE TABLE MovieActors (
+CREATE TABLE IF NOT EXISTS MovieActors (
     movie_id INT,
     actor_id INT,
     PRIMARY KEY (movie_id, actor_id),
@@ -23,10 +31,11 @@ CREATE TABLE MovieActors (
     FOREIGN KEY (actor_id) REFERENCES Actors(actor_id) ON DELETE CASCADE
 );
 
-CREATE TABLE Users (
+CREATE TABLE IF NOT EXISTS Users (
     username VARCHAR(256) UNIQUE NOT NULL,
     password VARCHAR(256) NOT NULL,
     is_admin BOOLEAN NOT NULL DEFAULT FALSE
 );
 
-INSERT INTO Users VALUES ('admin', '73656372657421232f297a57a5a743894a0e4a801fc3', TRUE);
+INSERT INTO Users VALUES ('admin', '73656372657421232f297a57a5a743894a0e4a801fc3', TRUE)
+ON CONFLICT (username) DO NOTHING;
@@ -0,0 +1,2 @@
+ALTER TABLE Movies DROP COLUMN IF EXISTS version;
+ALTER TABLE Actors DROP COLUMN IF EXISTS version;
@@ -0,0 +1,3 @@
+-- Row versions behind ETags and If-Match.
+ALTER TABLE Actors ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE Movies ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
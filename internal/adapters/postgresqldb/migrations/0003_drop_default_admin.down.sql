@@ -0,0 +1,2 @@
+INSERT INTO Users VALUES ('admin', '73656372657421232f297a57a5a743894a0e4a801fc3', TRUE)
+ON CONFLICT (username) DO NOTHING;
@@ -0,0 +1,3 @@
+-- Admins are created with filmctl now. The admin/admin account is removed
+-- unless its password was changed.
+DELETE FROM Users WHERE username = 'admin' AND password = '73656372657421232f297a57a5a743894a0e4a801fc3';
@@ -0,0 +1,2 @@
+ALTER TABLE Movies DROP COLUMN external_id;
+ALTER TABLE Actors DROP COLUMN external_id;
@@ -0,0 +1,3 @@
+-- IDs of movies and actors in the catalogues they were imported from.
+ALTER TABLE Actors ADD COLUMN external_id VARCHAR(64) UNIQUE;
+ALTER TABLE Movies ADD COLUMN external_id VARCHAR(64) UNIQUE;
@@ -0,0 +1,4 @@
+ALTER TABLE Movies
+    DROP COLUMN search_english,
+    DROP COLUMN search_russian,
+    DROP COLUMN search_simple;
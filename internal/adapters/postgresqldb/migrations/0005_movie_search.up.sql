@@ -0,0 +1,16 @@
+-- Full-text search over titles and descriptions. A column for each language
+-- a search can choose, as the configuration of a generated column is fixed.
+ALTER TABLE Movies
+    ADD COLUMN search_english tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', movie_title), 'A') ||
+        setweight(to_tsvector('english', coalesce(description, '')), 'B')) STORED,
+    ADD COLUMN search_russian tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('russian', movie_title), 'A') ||
+        setweight(to_tsvector('russian', coalesce(description, '')), 'B')) STORED,
+    ADD COLUMN search_simple tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', movie_title), 'A') ||
+        setweight(to_tsvector('simple', coalesce(description, '')), 'B')) STORED;
+
+CREATE INDEX movies_search_english_idx ON Movies USING GIN (search_english);
+CREATE INDEX movies_search_russian_idx ON Movies USING GIN (search_russian);
+CREATE INDEX movies_search_simple_idx ON Movies USING GIN (search_simple);
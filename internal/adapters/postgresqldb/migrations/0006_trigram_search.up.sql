@@ -0,0 +1,7 @@
+-- Trigram indexes over lowercased titles and actor names. They serve both the
+-- substring LIKE of a movie search and the fuzzy word similarity matching of
+-- misspelt terms.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX movies_title_trgm_idx ON Movies USING GIN (LOWER(movie_title) gin_trgm_ops);
+CREATE INDEX actors_name_trgm_idx ON Actors USING GIN (LOWER(actor_name) gin_trgm_ops);
@@ -0,0 +1,2 @@
+DROP INDEX actors_name_prefix_idx;
+DROP INDEX movies_title_prefix_idx;
@@ -0,0 +1,5 @@
+-- Prefix indexes for the typeahead. A left-anchored LIKE can only use a btree
+-- with the pattern operator class, the trigram indexes of 0006 serve the
+-- matches of a later word.
+CREATE INDEX movies_title_prefix_idx ON Movies (LOWER(movie_title) text_pattern_ops);
+CREATE INDEX actors_name_prefix_idx ON Actors (LOWER(actor_name) text_pattern_ops);
@@ -0,0 +1,14 @@
+-- Personal API keys. Only the SHA-256 of a key is kept, the key itself is
+-- shown once when it is created; prefix tells the keys apart in listings.
+CREATE TABLE ApiKeys (
+    key_id BIGSERIAL PRIMARY KEY,
+    username VARCHAR(256) NOT NULL REFERENCES Users(username) ON DELETE CASCADE,
+    key_name VARCHAR(100) NOT NULL,
+    prefix VARCHAR(16) NOT NULL,
+    key_hash CHAR(64) UNIQUE NOT NULL,
+    scopes TEXT[] NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_used_at TIMESTAMPTZ
+);
+
+CREATE INDEX api_keys_username_idx ON ApiKeys (username);
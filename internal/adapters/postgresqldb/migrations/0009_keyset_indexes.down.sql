@@ -0,0 +1,4 @@
+DROP INDEX actors_name_keyset_idx;
+DROP INDEX movies_release_date_keyset_idx;
+DROP INDEX movies_rating_keyset_idx;
+DROP INDEX movies_title_keyset_idx;
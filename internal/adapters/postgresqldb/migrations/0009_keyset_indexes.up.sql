@@ -0,0 +1,7 @@
+-- Indexes matching the ORDER BY of the paginated listings, so a page is read
+-- off the index past the cursor instead of sorting every row. The expressions
+-- must be the ones in movieSortColumns for the planner to use them.
+CREATE INDEX movies_title_keyset_idx ON Movies (movie_title, movie_id);
+CREATE INDEX movies_rating_keyset_idx ON Movies ((COALESCE(rating, 0)), movie_id);
+CREATE INDEX movies_release_date_keyset_idx ON Movies ((COALESCE(release_date, '0001-01-01')), movie_id);
+CREATE INDEX actors_name_keyset_idx ON Actors (actor_name, actor_id);
@@ -0,0 +1,7 @@
+ALTER TABLE Actors ALTER COLUMN external_id DROP NOT NULL, ALTER COLUMN external_id DROP DEFAULT;
+ALTER TABLE Movies ALTER COLUMN external_id DROP NOT NULL, ALTER COLUMN external_id DROP DEFAULT;
+
+UPDATE Actors SET external_id = NULL WHERE external_id LIKE 'filmlibrary:%';
+UPDATE Movies SET external_id = NULL WHERE external_id LIKE 'filmlibrary:%';
+
+DROP FUNCTION local_external_id();
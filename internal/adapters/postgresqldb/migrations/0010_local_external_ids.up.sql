@@ -0,0 +1,12 @@
+-- Every movie and actor gets an external ID, the ones entered by hand one of
+-- ours. An export then names each row uniquely, and importing it back finds
+-- the same rows instead of merging namesakes by title or name.
+CREATE FUNCTION local_external_id() RETURNS VARCHAR AS $$
+    SELECT 'filmlibrary:' || gen_random_uuid()
+$$ LANGUAGE SQL VOLATILE;
+
+UPDATE Movies SET external_id = local_external_id() WHERE external_id IS NULL;
+UPDATE Actors SET external_id = local_external_id() WHERE external_id IS NULL;
+
+ALTER TABLE Movies ALTER COLUMN external_id SET DEFAULT local_external_id(), ALTER COLUMN external_id SET NOT NULL;
+ALTER TABLE Actors ALTER COLUMN external_id SET DEFAULT local_external_id(), ALTER COLUMN external_id SET NOT NULL;
@@ -46,10 +46,10 @@ func getSqlForMovieActors(actors []int64, lastID int64) (string, []interface{},
 	return queryBuilder.String(), params, nil
 }
 
-func (s *movieStorage) Create(dto *domain.CreateMovie) error {
+func (s *movieStorage) Create(dto *domain.CreateMovie) (err error) {
 	tx, err := s.db.Begin()
 	if err != nil {
-		return err
+		return dbError(err)
 	}
 	defer func() {
 		if err != nil {
@@ -62,7 +62,11 @@ func (s *movieStorage) Create(dto *domain.CreateMovie) error {
 	var lastID int64
 	err = tx.QueryRow("INSERT INTO Movies (movie_title, description, release_date, rating) VALUES ($1, $2, $3, $4) RETURNING movie_id", dto.Title, dto.Description, dto.ReleaseDate.Format("2006-01-02"), dto.Rating).Scan(&lastID)
 	if err != nil {
-		return err
+		return dbError(err)
+	}
+
+	if len(dto.Actors) == 0 {
+		return nil
 	}
 
 	cmd, params, err := getSqlForMovieActors(dto.Actors, lastID)
@@ -72,17 +76,17 @@ func (s *movieStorage) Create(dto *domain.CreateMovie) error {
 
 	_, err = tx.Exec(cmd, params...)
 	if err != nil {
-		return err
+		return dbError(err)
 	}
 
 	return nil
 }
 
-func (s *movieStorage) Update(dto *domain.Movie) error {
+func (s *movieStorage) Update(dto *domain.Movie) (err error) {
 	tx, err := s.db.Begin()
 
 	if err != nil {
-		return err
+		return dbError(err)
 	}
 
 	defer func() {
@@ -94,17 +98,105 @@ func (s *movieStorage) Update(dto *domain.Movie) error {
 		err = tx.Commit()
 	}()
 
-	_, err = tx.Exec("UPDATE Movies SET movie_title = $1, description = $2, release_date = $3, rating = $4 WHERE movie_id = $5",
-		dto.Title, dto.Description, dto.ReleaseDate.Format("2006-01-02"), dto.Rating, dto.ID)
+	cond, condParams := versionCond(dto.Version, 6)
+	result, err := tx.Exec("UPDATE Movies SET movie_title = $1, description = $2, release_date = $3, rating = $4, version = version + 1 WHERE movie_id = $5"+cond,
+		append([]interface{}{dto.Title, dto.Description, dto.ReleaseDate.Format("2006-01-02"), dto.Rating, dto.ID}, condParams...)...)
 	if err != nil {
+		return dbError(err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		err = checkVersion(tx, "Movies", "movie_id", dto.ID, "movie")
 		return err
 	}
 
 	_, err = tx.Exec("DELETE FROM MovieActors WHERE movie_id = $1", dto.ID)
 	if err != nil {
+		return dbError(err)
+	}
+
+	if len(dto.Actors) == 0 {
+		return nil
+	}
+
+	cmd, params, err := getSqlForMovieActors(dto.Actors, dto.ID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(cmd, params...)
+	if err != nil {
+		return dbError(err)
+	}
+
+	return nil
+}
+
+func (s *movieStorage) Patch(dto *domain.PatchMovie) (err error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return dbError(err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	patch := patchSet{}
+	if dto.Title != nil {
+		patch.add("movie_title", *dto.Title)
+	}
+	if dto.Description != nil {
+		patch.add("description", *dto.Description)
+	} else if dto.NullDescription {
+		patch.add("description", nil)
+	}
+	if dto.ReleaseDate != nil {
+		patch.add("release_date", dto.ReleaseDate.Format("2006-01-02"))
+	} else if dto.NullReleaseDate {
+		patch.add("release_date", nil)
+	}
+	if dto.Rating != nil {
+		patch.add("rating", *dto.Rating)
+	} else if dto.NullRating {
+		patch.add("rating", nil)
+	}
+
+	query, params := patch.query("Movies", "movie_id", dto.ID, dto.Version)
+	result, err := tx.Exec(query, params...)
+	if err != nil {
+		return dbError(err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		err = checkVersion(tx, "Movies", "movie_id", dto.ID, "movie")
 		return err
 	}
 
+	if dto.Actors == nil {
+		return nil
+	}
+
+	_, err = tx.Exec("DELETE FROM MovieActors WHERE movie_id = $1", dto.ID)
+	if err != nil {
+		return dbError(err)
+	}
+
+	if len(dto.Actors) == 0 {
+		return nil
+	}
+
 	cmd, params, err := getSqlForMovieActors(dto.Actors, dto.ID)
 	if err != nil {
 		return err
@@ -112,62 +204,349 @@ func (s *movieStorage) Update(dto *domain.Movie) error {
 
 	_, err = tx.Exec(cmd, params...)
 	if err != nil {
+		return dbError(err)
+	}
+
+	return nil
+}
+
+// AddActor adds a single cast member. The cast is a part of the movie, so it
+// is conditional on the movie version and bumps it.
+func (s *movieStorage) AddActor(dto *domain.MovieActor) (err error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return dbError(err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	if err = lockVersion(tx, "Movies", "movie_id", dto.MovieID, dto.Version, "movie"); err != nil {
+		return err
+	}
+
+	result, err := tx.Exec("INSERT INTO MovieActors (movie_id, actor_id) VALUES ($1, $2) ON CONFLICT DO NOTHING", dto.MovieID, dto.ActorID)
+	if err != nil {
+		return dbError(err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return nil
+	}
+
+	_, err = tx.Exec("UPDATE Movies SET version = version + 1 WHERE movie_id = $1", dto.MovieID)
+	if err != nil {
+		return dbError(err)
+	}
+
+	return nil
+}
+
+// RemoveActor removes a single cast member, conditional on the movie version
+// and bumping it like AddActor.
+func (s *movieStorage) RemoveActor(dto *domain.MovieActor) (err error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return dbError(err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	if err = lockVersion(tx, "Movies", "movie_id", dto.MovieID, dto.Version, "movie"); err != nil {
+		return err
+	}
+
+	result, err := tx.Exec("DELETE FROM MovieActors WHERE movie_id = $1 AND actor_id = $2", dto.MovieID, dto.ActorID)
+	if err != nil {
+		return dbError(err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		err = domain.ErrNotFound
 		return err
 	}
 
+	_, err = tx.Exec("UPDATE Movies SET version = version + 1 WHERE movie_id = $1", dto.MovieID)
+	if err != nil {
+		return dbError(err)
+	}
+
 	return nil
 }
 
 func (s *movieStorage) Delete(dto *domain.DeleteMovie) error {
-	_, err := s.db.Exec("DELETE FROM Movies WHERE movie_id = $1", dto.ID)
+	cond, condParams := versionCond(dto.Version, 2)
+	result, err := s.db.Exec("DELETE FROM Movies WHERE movie_id = $1"+cond, append([]interface{}{dto.ID}, condParams...)...)
+	if err != nil {
+		return dbError(err)
+	}
+
+	affected, err := result.RowsAffected()
 	if err != nil {
 		return err
 	}
+	if affected == 0 {
+		return checkVersion(s.db, "Movies", "movie_id", dto.ID, "movie")
+	}
 
 	return nil
 }
 
-func (s *movieStorage) Get(dto *domain.GetMovie) (*domain.MovieWithoudID, error) {
-	var query string
-	var searchTerm string
+// escapeLike escapes the LIKE wildcards so that user input is matched literally.
+func escapeLike(term string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(term)
+}
 
-	switch dto.SearchType {
-	case "title":
-		query = "SELECT movie_title, description, release_date, rating FROM Movies WHERE LOWER(movie_title) LIKE '%' || $1 || '%' LIMIT 1;"
-		searchTerm = strings.ToLower(dto.Title)
-	case "actor":
-		query = "SELECT m.movie_title, m.description, m.release_date, m.rating FROM Movies m JOIN MovieActors ma ON m.movie_id = ma.movie_id JOIN Actors a ON ma.actor_id = a.actor_id WHERE LOWER(a.actor_name) LIKE '%' || $1 || '%' LIMIT 1;"
-		searchTerm = strings.ToLower(dto.ActorName)
-	default:
-		return nil, errors.New("invalid search type")
+// getSqlForMovieSearch builds the FROM/WHERE part of a movie search together with
+// a rank expression: 0 for an exact match, 1 for a prefix match, 2 for a substring match.
+// When both title and actor are given, the ranks are summed.
+func getSqlForMovieSearch(dto *domain.GetMovie) (string, string, []interface{}) {
+	var (
+		from   = "FROM Movies m"
+		where  = ""
+		ranks  = make([]string, 0, 2)
+		params = make([]interface{}, 0, 4)
+	)
+
+	if dto.ActorName != "" {
+		name := strings.ToLower(dto.ActorName)
+		params = append(params, name, escapeLike(name))
+		from += fmt.Sprintf(" JOIN (SELECT ma.movie_id, MIN(CASE WHEN LOWER(a.actor_name) = $%[1]d THEN 0 "+
+			"WHEN LOWER(a.actor_name) LIKE $%[2]d || '%%' THEN 1 ELSE 2 END) AS actor_rank "+
+			"FROM MovieActors ma JOIN Actors a ON ma.actor_id = a.actor_id "+
+			"WHERE LOWER(a.actor_name) LIKE '%%' || $%[2]d || '%%' GROUP BY ma.movie_id) ar ON ar.movie_id = m.movie_id",
+			len(params)-1, len(params))
+		ranks = append(ranks, "ar.actor_rank")
 	}
 
-	movie := domain.MovieWithoudID{}
-	if err := s.db.QueryRow(query, searchTerm).Scan(&movie.Title, &movie.Description, &movie.ReleaseDate, &movie.Rating); err != nil {
-		return nil, err
+	if dto.Title != "" {
+		title := strings.ToLower(dto.Title)
+		params = append(params, title, escapeLike(title))
+		where = fmt.Sprintf(" WHERE LOWER(m.movie_title) LIKE '%%' || $%d || '%%'", len(params))
+		ranks = append(ranks, fmt.Sprintf("CASE WHEN LOWER(m.movie_title) = $%[1]d THEN 0 "+
+			"WHEN LOWER(m.movie_title) LIKE $%[2]d || '%%' THEN 1 ELSE 2 END", len(params)-1, len(params)))
 	}
 
-	return &movie, nil
+	return from + where, strings.Join(ranks, " + "), params
+}
+
+// Get finds the movies whose title and cast contain the terms searched for.
+// When none does, it falls back to a fuzzy search of the misspelt terms.
+func (s *movieStorage) Get(dto *domain.GetMovie) (*domain.MoviePage, error) {
+	if dto.Title == "" && dto.ActorName == "" {
+		return nil, domain.ErrRequest
+	}
+
+	page := domain.MoviePage{
+		Movies: make([]domain.Movie, 0, dto.Limit),
+		Limit:  dto.Limit,
+		Offset: dto.Offset,
+	}
+
+	from, rank, params := getSqlForMovieSearch(dto)
+
+	if err := s.db.QueryRow("SELECT COUNT(*) "+from, params...).Scan(&page.Total); err != nil {
+		return nil, dbError(err)
+	}
+
+	if page.Total == 0 {
+		return s.getFuzzy(dto, &page)
+	}
+
+	query := fmt.Sprintf("SELECT m.movie_id, m.movie_title, m.description, m.release_date, m.rating %s "+
+		"ORDER BY %s, m.rating DESC, m.movie_id LIMIT $%d OFFSET $%d", from, rank, len(params)+1, len(params)+2)
+	rows, err := s.db.Query(query, append(params, dto.Limit, dto.Offset)...)
+	if err != nil {
+		return nil, dbError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		movie := domain.Movie{}
+
+		err = scanMovie(rows, &movie)
+		if err != nil {
+			return nil, dbError(err)
+		}
+
+		page.Movies = append(page.Movies, movie)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, dbError(err)
+	}
+
+	return &page, nil
+}
+
+// movieSortColumns maps the sort fields to their columns. Ratings and release
+// dates missing from imported movies sort as the zero values they are read
+// as, or the keyset of a page ending with them wouldn't match any row. The
+// keyset indexes of migration 0009 are built on the same expressions.
+var movieSortColumns = map[string]keysetColumn{
+	"title":        {name: "movie_title", kind: keysetText},
+	"rating":       {name: "COALESCE(rating, 0)", kind: keysetInt},
+	"release_date": {name: "COALESCE(release_date, '0001-01-01')", kind: keysetDate},
+}
+
+// getMovieKeyset maps the sort fields to known columns and appends movie_id
+// as a tiebreaker, so that movies with equal keys keep a stable order. It
+// runs the same way as the last sort field, which keeps a single-field sort
+// uniform for the row comparison and its index.
+func getMovieKeyset(sort []domain.SortField, page domain.Page) (*keyset, error) {
+	columns := make([]keysetColumn, 0, len(sort)+1)
+
+	for _, field := range sort {
+		column, ok := movieSortColumns[field.Field]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown sort field %q", domain.ErrRequest, field.Field)
+		}
+
+		column.desc = field.Desc
+		columns = append(columns, column)
+	}
+
+	tiebreaker := keysetColumn{name: "movie_id", kind: keysetInt}
+	if len(columns) > 0 {
+		tiebreaker.desc = columns[len(columns)-1].desc
+	}
+
+	columns = append(columns, tiebreaker)
+
+	return newKeyset(columns, page)
 }
 
-func (s *movieStorage) GetOrderedList(dto *domain.GetOrderedMovie) ([]domain.MovieWithoudID, error) {
-	movies := make([]domain.MovieWithoudID, 0, 4)
+func (s *movieStorage) GetOrderedList(dto *domain.GetOrderedMovie) (*domain.MovieList, error) {
+	movies := make([]domain.Movie, 0, dto.Page.Limit+1)
 
-	query := fmt.Sprintf("SELECT movie_title, description, release_date, rating FROM Movies ORDER BY %s DESC", dto.Order)
-	rows, err := s.db.Query(query)
+	k, err := getMovieKeyset(dto.Sort, dto.Page)
 	if err != nil {
 		return nil, err
 	}
+
+	conds, err := getMovieFilter(&dto.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	params := sqlParams{}
+	filter := renderFilter(conds, "", &params)
+	where, keysetParams := k.where(len(params) + 1)
+	params = append(params, keysetParams...)
+
+	query := fmt.Sprintf("SELECT movie_id, movie_title, description, release_date, rating FROM Movies WHERE %s ORDER BY %s LIMIT %s",
+		and(append(filter, where)...), k.orderBy(), params.add(k.fetch()))
+
+	rows, err := s.db.Query(query, params...)
+	if err != nil {
+		return nil, dbError(err)
+	}
 	defer rows.Close()
 
-	var movie domain.MovieWithoudID
 	for rows.Next() {
-		err = rows.Scan(&movie.Title, &movie.Description, &movie.ReleaseDate, &movie.Rating)
+		movie := domain.Movie{}
+
+		err = scanMovie(rows, &movie)
 		if err != nil {
-			return nil, err
+			return nil, dbError(err)
 		}
 		movies = append(movies, movie)
 	}
 
-	return movies, nil
+	if err = rows.Err(); err != nil {
+		return nil, dbError(err)
+	}
+
+	list := domain.MovieList{}
+	list.Movies, list.Cursors, err = keysetPage(k, movies, func(movie domain.Movie) []interface{} {
+		values := make([]interface{}, 0, len(dto.Sort)+1)
+
+		for _, field := range dto.Sort {
+			switch field.Field {
+			case "title":
+				values = append(values, movie.Title)
+			case "rating":
+				values = append(values, movie.Rating)
+			case "release_date":
+				values = append(values, movie.ReleaseDate)
+			}
+		}
+
+		return append(values, movie.ID)
+	})
+	if err != nil {
+		return nil, dbError(err)
+	}
+
+	if dto.Facets {
+		if list.Facets, err = s.getMovieFacets(conds, dto.YearBucket); err != nil {
+			return nil, err
+		}
+	}
+
+	return &list, nil
+}
+
+func (s *movieStorage) GetByID(dto *domain.GetMovieByID) (*domain.MovieWithActors, error) {
+	movie := domain.MovieWithActors{}
+
+	row := s.db.QueryRow("SELECT movie_id, movie_title, description, release_date, rating, version FROM Movies WHERE movie_id=$1", dto.ID)
+	err := scanMovie(row, &movie.Movie, &movie.Version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, dbError(err)
+	}
+
+	movie.Actors, err = s.GetActors(&domain.GetMovieActors{ID: dto.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	return &movie, nil
+}
+
+func (s *movieStorage) GetActors(dto *domain.GetMovieActors) ([]domain.Actor, error) {
+	actors := make([]domain.Actor, 0, 4)
+
+	rows, err := s.db.Query("SELECT actor_id, actor_name, gender, date_of_birth FROM Actors JOIN MovieActors USING(actor_id) WHERE movie_id=$1 ORDER BY actor_name", dto.ID)
+	if err != nil {
+		return nil, dbError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		actor := domain.Actor{}
+
+		err = scanActor(rows, &actor)
+		if err != nil {
+			return nil, dbError(err)
+		}
+
+		actors = append(actors, actor)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, dbError(err)
+	}
+
+	return actors, nil
 }
@@ -1,12 +1,16 @@
 package postgresqldb
 
 import (
+	"database/sql"
+	"errors"
+	"fmt"
 	"reflect"
-	"strings"
+	"regexp"
 	"testing"
 	"time"
 
 	"github.com/akrovv/filmlibrary/internal/domain"
+	"github.com/lib/pq"
 	"gopkg.in/DATA-DOG/go-sqlmock.v1"
 )
 
@@ -73,6 +77,42 @@ func TestMovieCreate(t *testing.T) {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 	}
 
+	// Postgres Commit() returned error
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO Movies \(movie_title, description, release_date, rating\) VALUES \(\$1, \$2, \$3, \$4\) RETURNING movie_i`).
+		WithArgs(dto.Title, dto.Description, dto.ReleaseDate.Format("2006-01-02"), dto.Rating).
+		WillReturnRows(sqlmock.NewRows([]string{"movie_id"}).AddRow(1))
+	mock.ExpectExec(`INSERT INTO MovieActors \(movie_id, actor_id\) VALUES \(\$1, \$2\), \(\$3, \$4\)`).
+		WithArgs(1, 1, 1, 2).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit().WillReturnError(domain.ErrTest)
+
+	if err = storage.Create(dto); !errors.Is(err, domain.ErrTest) {
+		t.Errorf("expected ErrTest, got: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// OK. No cast
+	noCast := *dto
+	noCast.Actors = []int64{}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO Movies \(movie_title, description, release_date, rating\) VALUES \(\$1, \$2, \$3, \$4\) RETURNING movie_i`).
+		WithArgs(dto.Title, dto.Description, dto.ReleaseDate.Format("2006-01-02"), dto.Rating).
+		WillReturnRows(sqlmock.NewRows([]string{"movie_id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	if err = storage.Create(&noCast); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
 	// Postgres second CMD returned error
 	mock.ExpectBegin()
 	mock.ExpectQuery(`INSERT INTO Movies \(movie_title, description, release_date, rating\) VALUES \(\$1, \$2, \$3, \$4\) RETURNING movie_i`).
@@ -113,7 +153,7 @@ func TestMovieUpdate(t *testing.T) {
 
 	// OK
 	mock.ExpectBegin()
-	mock.ExpectExec(`UPDATE Movies SET movie_title = \$1, description = \$2, release_date = \$3, rating = \$4 WHERE movie_id = \$5`).
+	mock.ExpectExec(`UPDATE Movies SET movie_title = \$1, description = \$2, release_date = \$3, rating = \$4, version = version \+ 1 WHERE movie_id = \$5`).
 		WithArgs(dto.Title, dto.Description, dto.ReleaseDate.Format("2006-01-02"), dto.Rating, dto.ID).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 	mock.ExpectExec(`DELETE FROM MovieActors WHERE movie_id = \$1`).
@@ -133,6 +173,27 @@ func TestMovieUpdate(t *testing.T) {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 	}
 
+	// OK. The cast is cleared
+	noCast := *dto
+	noCast.Actors = []int64{}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE Movies SET movie_title = \$1, description = \$2, release_date = \$3, rating = \$4, version = version \+ 1 WHERE movie_id = \$5`).
+		WithArgs(dto.Title, dto.Description, dto.ReleaseDate.Format("2006-01-02"), dto.Rating, dto.ID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`DELETE FROM MovieActors WHERE movie_id = \$1`).
+		WithArgs(dto.ID).
+		WillReturnResult(sqlmock.NewResult(1, 2))
+	mock.ExpectCommit()
+
+	if err = storage.Update(&noCast); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
 	// Postgres Begin() returned error
 	mock.ExpectBegin().WillReturnError(domain.ErrTest)
 	err = storage.Update(dto)
@@ -146,7 +207,7 @@ func TestMovieUpdate(t *testing.T) {
 
 	// Postgres first CMD returned error
 	mock.ExpectBegin()
-	mock.ExpectExec(`UPDATE Movies SET movie_title = \$1, description = \$2, release_date = \$3, rating = \$4 WHERE movie_id = \$5`).
+	mock.ExpectExec(`UPDATE Movies SET movie_title = \$1, description = \$2, release_date = \$3, rating = \$4, version = version \+ 1 WHERE movie_id = \$5`).
 		WithArgs(dto.Title, dto.Description, dto.ReleaseDate.Format("2006-01-02"), dto.Rating, dto.ID).
 		WillReturnError(domain.ErrTest)
 	mock.ExpectRollback()
@@ -162,7 +223,7 @@ func TestMovieUpdate(t *testing.T) {
 
 	// Postgres second CMD returned error
 	mock.ExpectBegin()
-	mock.ExpectExec(`UPDATE Movies SET movie_title = \$1, description = \$2, release_date = \$3, rating = \$4 WHERE movie_id = \$5`).
+	mock.ExpectExec(`UPDATE Movies SET movie_title = \$1, description = \$2, release_date = \$3, rating = \$4, version = version \+ 1 WHERE movie_id = \$5`).
 		WithArgs(dto.Title, dto.Description, dto.ReleaseDate.Format("2006-01-02"), dto.Rating, dto.ID).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 	mock.ExpectExec(`DELETE FROM MovieActors WHERE movie_id = \$1`).
@@ -181,7 +242,7 @@ func TestMovieUpdate(t *testing.T) {
 
 	// Postgres third CMD returned error
 	mock.ExpectBegin()
-	mock.ExpectExec(`UPDATE Movies SET movie_title = \$1, description = \$2, release_date = \$3, rating = \$4 WHERE movie_id = \$5`).
+	mock.ExpectExec(`UPDATE Movies SET movie_title = \$1, description = \$2, release_date = \$3, rating = \$4, version = version \+ 1 WHERE movie_id = \$5`).
 		WithArgs(dto.Title, dto.Description, dto.ReleaseDate.Format("2006-01-02"), dto.Rating, dto.ID).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 	mock.ExpectExec(`DELETE FROM MovieActors WHERE movie_id = \$1`).
@@ -200,143 +261,316 @@ func TestMovieUpdate(t *testing.T) {
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 	}
-}
 
-func TestMovieDelete(t *testing.T) {
+	// Stale version
+	dto.Version = 2
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE Movies SET movie_title = \$1, description = \$2, release_date = \$3, rating = \$4, version = version \+ 1 WHERE movie_id = \$5 AND version = \$6`).
+		WithArgs(dto.Title, dto.Description, dto.ReleaseDate.Format("2006-01-02"), dto.Rating, dto.ID, dto.Version).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT version FROM Movies WHERE movie_id = \$1`).
+		WithArgs(dto.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(3))
+	mock.ExpectRollback()
+
+	err = storage.Update(dto)
+	var conflict *domain.VersionConflictError
+	if !errors.As(err, &conflict) || conflict.Version != 3 {
+		t.Errorf("expected VersionConflictError, got: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+func TestMoviePatch(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("can't create mock: %s", err)
-		return
 	}
 	defer db.Close()
 
 	storage := NewMovieStorage(db)
-	deleteMovie := domain.DeleteMovie{
-		ID: 1,
+
+	title := "Test Movie"
+	rating := uint8(8)
+	dto := &domain.PatchMovie{
+		ID:     1,
+		Title:  &title,
+		Rating: &rating,
 	}
 
-	// OK
-	mock.ExpectExec(`DELETE FROM Movies WHERE movie_id = \$1`).
-		WithArgs(deleteMovie.ID).
-		WillReturnResult(sqlmock.NewResult(1, 1))
+	// OK, only the supplied columns
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE Movies SET movie_title = \$1, rating = \$2, version = version \+ 1 WHERE movie_id = \$3`).
+		WithArgs(title, rating, dto.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
 
-	err = storage.Delete(&deleteMovie)
+	err = storage.Patch(dto)
 	if err != nil {
-		t.Fatalf("unexpected error: %s", err)
-		return
+		t.Errorf("unexpected error: %s", err)
 	}
 
-	if err = mock.ExpectationsWereMet(); err != nil {
+	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("there were unfulfilled expectations: %s", err)
-		return
 	}
 
-	// Postgres returned error
-	mock.ExpectExec(`DELETE FROM Movies WHERE movie_id = \$1`).
-		WithArgs(deleteMovie.ID).
-		WillReturnError(domain.ErrTest)
-
-	err = storage.Delete(&deleteMovie)
+	// Postgres Commit() returned error
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE Movies SET movie_title = \$1, rating = \$2, version = version \+ 1 WHERE movie_id = \$3`).
+		WithArgs(title, rating, dto.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit().WillReturnError(domain.ErrTest)
 
-	if err == nil {
-		t.Error("expected error, got nil")
-		return
+	if err = storage.Patch(dto); !errors.Is(err, domain.ErrTest) {
+		t.Errorf("expected ErrTest, got: %v", err)
 	}
 
-	if err = mock.ExpectationsWereMet(); err != nil {
+	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("there were unfulfilled expectations: %s", err)
-		return
 	}
-}
 
-func TestMovieGet(t *testing.T) {
-	db, mock, err := sqlmock.New()
+	// OK, null clears the column
+	nullDTO := &domain.PatchMovie{
+		ID:              1,
+		NullDescription: true,
+		NullReleaseDate: true,
+		NullRating:      true,
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE Movies SET description = \$1, release_date = \$2, rating = \$3, version = version \+ 1 WHERE movie_id = \$4`).
+		WithArgs(nil, nil, nil, nullDTO.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err = storage.Patch(nullDTO)
 	if err != nil {
-		t.Fatalf("can't create mock: %s", err)
+		t.Errorf("unexpected error: %s", err)
 	}
-	defer db.Close()
 
-	storage := NewMovieStorage(db)
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
 
-	dto1 := &domain.GetMovie{
-		Title:      "Test Movie",
-		SearchType: "title",
+	// OK, cast only
+	castDTO := &domain.PatchMovie{
+		ID:     1,
+		Actors: []int64{3},
 	}
 
-	expectTime := time.Now()
-	expectedMovie := &domain.MovieWithoudID{
-		Title:       "Test Movie",
-		Description: "Test Description",
-		ReleaseDate: expectTime,
-		Rating:      5,
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE Movies SET version = version \+ 1 WHERE movie_id = \$1`).
+		WithArgs(castDTO.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM MovieActors WHERE movie_id = \$1`).
+		WithArgs(castDTO.ID).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`INSERT INTO MovieActors \(movie_id, actor_id\) VALUES \(\$1, \$2\)`).
+		WithArgs(1, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err = storage.Patch(castDTO)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
 	}
 
-	// OK. Search by title
-	mock.ExpectQuery("SELECT movie_title, description, release_date, rating FROM Movies WHERE LOWER(movie_title) LIKE '%' || \\$1 || '%' LIMIT 1").
-		WithArgs(strings.ToLower(dto1.Title)).
-		WillReturnRows(sqlmock.NewRows([]string{"movie_title", "description", "release_date", "rating"}).
-			AddRow("Test Movie", "Test Description", expectTime, 5))
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// OK, empty cast
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE Movies SET version = version \+ 1 WHERE movie_id = \$1`).
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM MovieActors WHERE movie_id = \$1`).
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
 
-	movie, err := storage.Get(dto1)
+	err = storage.Patch(&domain.PatchMovie{ID: 1, Actors: []int64{}})
 	if err != nil {
 		t.Errorf("unexpected error: %s", err)
 	}
 
-	if movie == nil {
-		t.Error("expected movie, got nil")
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
 	}
 
-	if !reflect.DeepEqual(expectedMovie, movie) {
-		t.Errorf("expected: %v, got: %v", expectedMovie, movie)
+	// Not found
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE Movies SET movie_title = \$1, rating = \$2, version = version \+ 1 WHERE movie_id = \$3`).
+		WithArgs(title, rating, dto.ID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT version FROM Movies WHERE movie_id = \$1`).
+		WithArgs(dto.ID).
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+
+	err = storage.Patch(dto)
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got: %v", err)
 	}
 
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 	}
 
-	// OK. Search by actor
-	dto2 := &domain.GetMovie{
-		ActorName:  "Test Actor",
-		SearchType: "actor",
+	// Postgres Begin() returned error
+	mock.ExpectBegin().WillReturnError(domain.ErrTest)
+	err = storage.Patch(dto)
+	if err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// Postgres UPDATE returned error
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE Movies SET movie_title = \$1, rating = \$2, version = version \+ 1 WHERE movie_id = \$3`).
+		WithArgs(title, rating, dto.ID).
+		WillReturnError(domain.ErrTest)
+	mock.ExpectRollback()
+
+	err = storage.Patch(dto)
+	if err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// Postgres INSERT returned error
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE Movies SET version = version \+ 1 WHERE movie_id = \$1`).
+		WithArgs(castDTO.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM MovieActors WHERE movie_id = \$1`).
+		WithArgs(castDTO.ID).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`INSERT INTO MovieActors \(movie_id, actor_id\) VALUES \(\$1, \$2\)`).
+		WithArgs(1, 3).
+		WillReturnError(domain.ErrTest)
+	mock.ExpectRollback()
+
+	err = storage.Patch(castDTO)
+	if err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestMovieAddActor(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("can't create mock: %s", err)
 	}
-	mock.ExpectQuery("SELECT m.movie_title, m.description, m.release_date, m.rating FROM Movies m JOIN MovieActors ma ON m.movie_id = ma.movie_id JOIN Actors a ON ma.actor_id = a.actor_id WHERE LOWER(a.actor_name) LIKE '%' || \\$1 || '%' LIMIT 1").
-		WithArgs(strings.ToLower(dto2.ActorName)).
-		WillReturnRows(sqlmock.NewRows([]string{"movie_title", "description", "release_date", "rating"}).
-			AddRow("Test Movie", "Test Description", expectTime, 5))
+	defer db.Close()
+
+	storage := NewMovieStorage(db)
+	dto := &domain.MovieActor{
+		MovieID: 1,
+		ActorID: 2,
+		Version: 3,
+	}
+	lock := `SELECT version FROM Movies WHERE movie_id = \$1 FOR UPDATE`
+
+	// OK
+	mock.ExpectBegin()
+	mock.ExpectQuery(lock).
+		WithArgs(dto.MovieID).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(3))
+	mock.ExpectExec(`INSERT INTO MovieActors \(movie_id, actor_id\) VALUES \(\$1, \$2\) ON CONFLICT DO NOTHING`).
+		WithArgs(dto.MovieID, dto.ActorID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE Movies SET version = version \+ 1 WHERE movie_id = \$1`).
+		WithArgs(dto.MovieID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
 
-	movie, err = storage.Get(dto2)
+	err = storage.AddActor(dto)
 	if err != nil {
 		t.Errorf("unexpected error: %s", err)
 	}
 
-	if movie == nil {
-		t.Error("expected movie, got nil")
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
 	}
 
-	if !reflect.DeepEqual(expectedMovie, movie) {
-		t.Errorf("expected: %v, got: %v", expectedMovie, movie)
+	// Stale version
+	mock.ExpectBegin()
+	mock.ExpectQuery(lock).
+		WithArgs(dto.MovieID).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(4))
+	mock.ExpectRollback()
+
+	var conflict *domain.VersionConflictError
+	err = storage.AddActor(dto)
+	if !errors.As(err, &conflict) || conflict.Version != 4 {
+		t.Errorf("expected VersionConflictError, got: %v", err)
 	}
 
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 	}
 
-	// Invalid search type
-	dto3 := &domain.GetMovie{
-		SearchType: "invalid",
+	// Movie doesn't exist
+	mock.ExpectBegin()
+	mock.ExpectQuery(lock).
+		WithArgs(dto.MovieID).
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+
+	err = storage.AddActor(dto)
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got: %v", err)
 	}
 
-	movie, err = storage.Get(dto3)
-	if err == nil {
-		t.Error("expected error, got nil")
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// Already in the cast
+	mock.ExpectBegin()
+	mock.ExpectQuery(lock).
+		WithArgs(dto.MovieID).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(3))
+	mock.ExpectExec(`INSERT INTO MovieActors \(movie_id, actor_id\) VALUES \(\$1, \$2\) ON CONFLICT DO NOTHING`).
+		WithArgs(dto.MovieID, dto.ActorID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	err = storage.AddActor(dto)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
 	}
 
-	if err.Error() != "invalid search type" {
-		t.Errorf("expected error 'invalid search type', got '%s'", err.Error())
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
 	}
 
-	if movie != nil {
-		t.Errorf("expected nil, got: %v", movie)
+	// Actor doesn't exist
+	mock.ExpectBegin()
+	mock.ExpectQuery(lock).
+		WithArgs(dto.MovieID).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(3))
+	mock.ExpectExec(`INSERT INTO MovieActors \(movie_id, actor_id\) VALUES \(\$1, \$2\) ON CONFLICT DO NOTHING`).
+		WithArgs(dto.MovieID, dto.ActorID).
+		WillReturnError(&pq.Error{Code: "23503", Message: "insert or update on table violates foreign key constraint"})
+	mock.ExpectRollback()
+
+	err = storage.AddActor(dto)
+	if !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("expected ErrValidation, got: %v", err)
 	}
 
 	if err := mock.ExpectationsWereMet(); err != nil {
@@ -344,25 +578,26 @@ func TestMovieGet(t *testing.T) {
 	}
 
 	// Postgres returned error
-	mock.ExpectQuery("SELECT m.movie_title, m.description, m.release_date, m.rating FROM Movies m JOIN MovieActors ma ON m.movie_id = ma.movie_id JOIN Actors a ON ma.actor_id = a.actor_id WHERE LOWER(a.actor_name) LIKE '%' || \\$1 || '%' LIMIT 1").
-		WithArgs(strings.ToLower(dto2.ActorName)).
+	mock.ExpectBegin()
+	mock.ExpectQuery(lock).
+		WithArgs(dto.MovieID).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(3))
+	mock.ExpectExec(`INSERT INTO MovieActors \(movie_id, actor_id\) VALUES \(\$1, \$2\) ON CONFLICT DO NOTHING`).
+		WithArgs(dto.MovieID, dto.ActorID).
 		WillReturnError(domain.ErrTest)
+	mock.ExpectRollback()
 
-	movie, err = storage.Get(dto2)
+	err = storage.AddActor(dto)
 	if err == nil {
 		t.Error("expected error, got nil")
 	}
 
-	if movie != nil {
-		t.Errorf("expected nil, got: %v", movie)
-	}
-
-	if err = mock.ExpectationsWereMet(); err != nil {
+	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 	}
 }
 
-func TestMovieGetOrderedList(t *testing.T) {
+func TestMovieRemoveActor(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("can't create mock: %s", err)
@@ -370,67 +605,697 @@ func TestMovieGetOrderedList(t *testing.T) {
 	defer db.Close()
 
 	storage := NewMovieStorage(db)
-
-	dto := &domain.GetOrderedMovie{
-		Order: "rating",
-	}
-
-	expectTime := time.Now()
-	expectedMovies := []domain.MovieWithoudID{
-		{Title: "Movie 1", Description: "Description 1", ReleaseDate: expectTime, Rating: 5},
-		{Title: "Movie 2", Description: "Description 2", ReleaseDate: expectTime, Rating: 4},
+	dto := &domain.MovieActor{
+		MovieID: 1,
+		ActorID: 2,
+		Version: 3,
 	}
+	lock := `SELECT version FROM Movies WHERE movie_id = \$1 FOR UPDATE`
 
 	// OK
-	mock.ExpectQuery("SELECT movie_title, description, release_date, rating FROM Movies ORDER BY rating DESC").
-		WillReturnRows(sqlmock.NewRows([]string{"movie_title", "description", "release_date", "rating"}).
-			AddRow("Movie 1", "Description 1", expectTime, 5).
-			AddRow("Movie 2", "Description 2", expectTime, 4))
+	mock.ExpectBegin()
+	mock.ExpectQuery(lock).
+		WithArgs(dto.MovieID).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(3))
+	mock.ExpectExec(`DELETE FROM MovieActors WHERE movie_id = \$1 AND actor_id = \$2`).
+		WithArgs(dto.MovieID, dto.ActorID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE Movies SET version = version \+ 1 WHERE movie_id = \$1`).
+		WithArgs(dto.MovieID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
 
-	movies, err := storage.GetOrderedList(dto)
+	err = storage.RemoveActor(dto)
 	if err != nil {
 		t.Errorf("unexpected error: %s", err)
 	}
 
-	if !reflect.DeepEqual(expectedMovies, movies) {
-		t.Errorf("expected: %v, got: %v", expectedMovies, movies)
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// Stale version
+	mock.ExpectBegin()
+	mock.ExpectQuery(lock).
+		WithArgs(dto.MovieID).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(4))
+	mock.ExpectRollback()
+
+	var conflict *domain.VersionConflictError
+	err = storage.RemoveActor(dto)
+	if !errors.As(err, &conflict) || conflict.Version != 4 {
+		t.Errorf("expected VersionConflictError, got: %v", err)
 	}
 
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 	}
 
-	// Postgres returned error
-	mock.ExpectQuery("SELECT movie_title, description, release_date, rating FROM Movies ORDER BY rating DESC").
-		WillReturnError(domain.ErrTest)
+	// Movie doesn't exist
+	mock.ExpectBegin()
+	mock.ExpectQuery(lock).
+		WithArgs(dto.MovieID).
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
 
-	movies, err = storage.GetOrderedList(dto)
+	err = storage.RemoveActor(dto)
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got: %v", err)
+	}
 
-	if err == nil {
-		t.Error("expected error, got nil")
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
 	}
 
-	if movies != nil {
-		t.Errorf("expected nil, got: %v", movies)
+	// Not in the cast
+	mock.ExpectBegin()
+	mock.ExpectQuery(lock).
+		WithArgs(dto.MovieID).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(3))
+	mock.ExpectExec(`DELETE FROM MovieActors WHERE movie_id = \$1 AND actor_id = \$2`).
+		WithArgs(dto.MovieID, dto.ActorID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err = storage.RemoveActor(dto)
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got: %v", err)
 	}
 
-	if err = mock.ExpectationsWereMet(); err != nil {
+	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 	}
 
-	// Rows scan error
-	mock.ExpectQuery("SELECT movie_title, description, release_date, rating FROM Movies ORDER BY rating DESC").
-		WillReturnRows(sqlmock.NewRows([]string{"movie_title", "description"}).
-			AddRow("Movie 1", "Description 1"))
-
-	movies, err = storage.GetOrderedList(dto)
+	// Postgres returned error
+	mock.ExpectBegin()
+	mock.ExpectQuery(lock).
+		WithArgs(dto.MovieID).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(3))
+	mock.ExpectExec(`DELETE FROM MovieActors WHERE movie_id = \$1 AND actor_id = \$2`).
+		WithArgs(dto.MovieID, dto.ActorID).
+		WillReturnError(domain.ErrTest)
+	mock.ExpectRollback()
 
+	err = storage.RemoveActor(dto)
 	if err == nil {
 		t.Error("expected error, got nil")
 	}
 
-	if movies != nil {
-		t.Errorf("expected nil, got: %v", movies)
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestMovieDelete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("can't create mock: %s", err)
+		return
+	}
+	defer db.Close()
+
+	storage := NewMovieStorage(db)
+	deleteMovie := domain.DeleteMovie{
+		ID: 1,
+	}
+
+	// OK
+	mock.ExpectExec(`DELETE FROM Movies WHERE movie_id = \$1`).
+		WithArgs(deleteMovie.ID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err = storage.Delete(&deleteMovie)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+		return
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+		return
+	}
+
+	// Postgres returned error
+	mock.ExpectExec(`DELETE FROM Movies WHERE movie_id = \$1`).
+		WithArgs(deleteMovie.ID).
+		WillReturnError(domain.ErrTest)
+
+	err = storage.Delete(&deleteMovie)
+
+	if err == nil {
+		t.Error("expected error, got nil")
+		return
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+		return
+	}
+
+	// Not found
+	mock.ExpectExec(`DELETE FROM Movies WHERE movie_id = \$1`).
+		WithArgs(deleteMovie.ID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT version FROM Movies WHERE movie_id = \$1`).
+		WithArgs(deleteMovie.ID).
+		WillReturnError(sql.ErrNoRows)
+
+	err = storage.Delete(&deleteMovie)
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got: %v", err)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// Stale version
+	deleteMovie.Version = 2
+	mock.ExpectExec(`DELETE FROM Movies WHERE movie_id = \$1 AND version = \$2`).
+		WithArgs(deleteMovie.ID, deleteMovie.Version).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT version FROM Movies WHERE movie_id = \$1`).
+		WithArgs(deleteMovie.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(3))
+
+	err = storage.Delete(&deleteMovie)
+	var conflict *domain.VersionConflictError
+	if !errors.As(err, &conflict) {
+		t.Errorf("expected VersionConflictError, got: %v", err)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+func TestMovieGet(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("can't create mock: %s", err)
+	}
+	defer db.Close()
+
+	storage := NewMovieStorage(db)
+
+	titleRank := "CASE WHEN LOWER(m.movie_title) = $%d THEN 0 WHEN LOWER(m.movie_title) LIKE $%d || '%%' THEN 1 ELSE 2 END"
+	actorJoin := "JOIN (SELECT ma.movie_id, MIN(CASE WHEN LOWER(a.actor_name) = $1 THEN 0 WHEN LOWER(a.actor_name) LIKE $2 || '%' THEN 1 ELSE 2 END) AS actor_rank " +
+		"FROM MovieActors ma JOIN Actors a ON ma.actor_id = a.actor_id WHERE LOWER(a.actor_name) LIKE '%' || $2 || '%' GROUP BY ma.movie_id) ar ON ar.movie_id = m.movie_id"
+	columns := []string{"movie_id", "movie_title", "description", "release_date", "rating"}
+
+	dto1 := &domain.GetMovie{
+		Title: "Test_Movie",
+		Limit: 20,
+	}
+
+	expectTime := time.Now()
+	expectedPage := &domain.MoviePage{
+		Movies: []domain.Movie{
+			{ID: 1, Title: "Test_Movie", Description: "Test Description", ReleaseDate: expectTime, Rating: 5},
+		},
+		Total: 1,
+		Limit: 20,
+	}
+
+	// OK. Search by title
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM Movies m WHERE LOWER(m.movie_title) LIKE '%' || $2 || '%'")).
+		WithArgs("test_movie", `test\_movie`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT m.movie_id, m.movie_title, m.description, m.release_date, m.rating FROM Movies m WHERE LOWER(m.movie_title) LIKE '%' || $2 || '%' "+
+		"ORDER BY "+fmt.Sprintf(titleRank, 1, 2)+", m.rating DESC, m.movie_id LIMIT $3 OFFSET $4")).
+		WithArgs("test_movie", `test\_movie`, 20, 0).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(1, "Test_Movie", "Test Description", expectTime, 5))
+
+	page, err := storage.Get(dto1)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	if !reflect.DeepEqual(expectedPage, page) {
+		t.Errorf("expected: %v, got: %v", expectedPage, page)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// OK. Search by title and actor
+	dto2 := &domain.GetMovie{
+		Title:     "Movie",
+		ActorName: "Actor",
+		Limit:     10,
+		Offset:    10,
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM Movies m "+actorJoin+" WHERE LOWER(m.movie_title) LIKE '%' || $4 || '%'")).
+		WithArgs("actor", "actor", "movie", "movie").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(11))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT m.movie_id, m.movie_title, m.description, m.release_date, m.rating FROM Movies m "+actorJoin+" WHERE LOWER(m.movie_title) LIKE '%' || $4 || '%' "+
+		"ORDER BY ar.actor_rank + "+fmt.Sprintf(titleRank, 3, 4)+", m.rating DESC, m.movie_id LIMIT $5 OFFSET $6")).
+		WithArgs("actor", "actor", "movie", "movie", 10, 10).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(1, "Test_Movie", "Test Description", expectTime, 5))
+
+	page, err = storage.Get(dto2)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	if page == nil || page.Total != 11 || len(page.Movies) != 1 {
+		t.Errorf("unexpected page: %v", page)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// Empty search
+	page, err = storage.Get(&domain.GetMovie{Limit: 20})
+	if !errors.Is(err, domain.ErrRequest) {
+		t.Errorf("expected: %v, got: %v", domain.ErrRequest, err)
+	}
+
+	if page != nil {
+		t.Errorf("expected nil, got: %v", page)
+	}
+
+	// Postgres returned error
+	dto3 := &domain.GetMovie{
+		ActorName: "Actor",
+		Limit:     20,
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM Movies m "+actorJoin)).
+		WithArgs("actor", "actor").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT m.movie_id, m.movie_title, m.description, m.release_date, m.rating FROM Movies m "+actorJoin+" ORDER BY ar.actor_rank, m.rating DESC, m.movie_id LIMIT $3 OFFSET $4")).
+		WithArgs("actor", "actor", 20, 0).
+		WillReturnError(domain.ErrTest)
+
+	page, err = storage.Get(dto3)
+	if err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	if page != nil {
+		t.Errorf("expected nil, got: %v", page)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// Postgres count returned error
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM Movies m "+actorJoin)).
+		WithArgs("actor", "actor").
+		WillReturnError(domain.ErrTest)
+
+	page, err = storage.Get(dto3)
+	if err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	if page != nil {
+		t.Errorf("expected nil, got: %v", page)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestMovieGetOrderedList(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("can't create mock: %s", err)
+	}
+	defer db.Close()
+
+	storage := NewMovieStorage(db)
+
+	dto := &domain.GetOrderedMovie{
+		Sort: []domain.SortField{
+			{Field: "rating", Desc: true},
+			{Field: "title"},
+		},
+		Page: domain.Page{Limit: 2},
+	}
+
+	expectTime := time.Now()
+	expectedMovies := []domain.Movie{
+		{ID: 1, Title: "Movie 1", Description: "Description 1", ReleaseDate: expectTime, Rating: 5},
+		{ID: 2, Title: "Movie 2", Description: "Description 2", ReleaseDate: expectTime, Rating: 4},
+	}
+	columns := []string{"movie_id", "movie_title", "description", "release_date", "rating"}
+
+	// OK. First page
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT movie_id, movie_title, description, release_date, rating FROM Movies WHERE TRUE ORDER BY COALESCE(rating, 0) DESC, movie_title ASC, movie_id ASC LIMIT $1")).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(1, "Movie 1", "Description 1", expectTime, 5).
+			AddRow(2, "Movie 2", "Description 2", expectTime, 4).
+			AddRow(3, "Movie 3", "Description 3", expectTime, 4))
+
+	list, err := storage.GetOrderedList(dto)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if !reflect.DeepEqual(expectedMovies, list.Movies) {
+		t.Errorf("expected: %v, got: %v", expectedMovies, list.Movies)
+	}
+
+	if list.Next == "" || list.Prev != "" {
+		t.Errorf("expected only next cursor, got: %v", list.Cursors)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// OK. Last page after the cursor, an imported movie lacks its description and release date
+	dto.Page.After = list.Next
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT movie_id, movie_title, description, release_date, rating FROM Movies "+
+		"WHERE ((COALESCE(rating, 0) < $1) OR (COALESCE(rating, 0) = $1 AND movie_title > $2) OR (COALESCE(rating, 0) = $1 AND movie_title = $2 AND movie_id > $3)) "+
+		"ORDER BY COALESCE(rating, 0) DESC, movie_title ASC, movie_id ASC LIMIT $4")).
+		WithArgs(4, "Movie 2", 2, 3).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(3, "Movie 3", nil, nil, 4))
+
+	list, err = storage.GetOrderedList(dto)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(list.Movies) != 1 || list.Next != "" || list.Prev == "" {
+		t.Errorf("unexpected list: %v", list)
+	}
+
+	if list.Movies[0].Description != "" || !list.Movies[0].ReleaseDate.IsZero() {
+		t.Errorf("expected zero description and release date, got: %v", list.Movies[0])
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// OK. Previous page before the cursor
+	dto.Page.After, dto.Page.Before = "", list.Prev
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT movie_id, movie_title, description, release_date, rating FROM Movies "+
+		"WHERE ((COALESCE(rating, 0) > $1) OR (COALESCE(rating, 0) = $1 AND movie_title < $2) OR (COALESCE(rating, 0) = $1 AND movie_title = $2 AND movie_id < $3)) "+
+		"ORDER BY COALESCE(rating, 0) ASC, movie_title DESC, movie_id DESC LIMIT $4")).
+		WithArgs(4, "Movie 3", 3, 3).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(2, "Movie 2", "Description 2", expectTime, 4).
+			AddRow(1, "Movie 1", "Description 1", expectTime, 5))
+
+	list, err = storage.GetOrderedList(dto)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if !reflect.DeepEqual(expectedMovies, list.Movies) {
+		t.Errorf("expected: %v, got: %v", expectedMovies, list.Movies)
+	}
+
+	if list.Next == "" || list.Prev != "" {
+		t.Errorf("expected only next cursor, got: %v", list.Cursors)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// Cursor of another sort order
+	dto.Page.Before = ""
+	list, err = storage.GetOrderedList(&domain.GetOrderedMovie{
+		Sort: []domain.SortField{{Field: "title"}},
+		Page: domain.Page{Limit: 2, After: list.Next},
+	})
+
+	if !errors.Is(err, domain.ErrRequest) {
+		t.Errorf("expected: %v, got: %v", domain.ErrRequest, err)
+	}
+
+	if list != nil {
+		t.Errorf("expected nil, got: %v", list)
+	}
+
+	// Postgres returned error
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT movie_id, movie_title, description, release_date, rating FROM Movies WHERE TRUE ORDER BY COALESCE(rating, 0) DESC, movie_title ASC, movie_id ASC LIMIT $1")).
+		WillReturnError(domain.ErrTest)
+
+	list, err = storage.GetOrderedList(dto)
+
+	if err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	if list != nil {
+		t.Errorf("expected nil, got: %v", list)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// Rows iteration error
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT movie_id, movie_title, description, release_date, rating FROM Movies WHERE TRUE ORDER BY COALESCE(rating, 0) DESC, movie_title ASC, movie_id ASC LIMIT $1")).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(1, "Movie 1", "Description 1", expectTime, 5).
+			AddRow(2, "Movie 2", "Description 2", expectTime, 4).
+			RowError(1, domain.ErrTest))
+
+	if list, err = storage.GetOrderedList(dto); !errors.Is(err, domain.ErrTest) {
+		t.Errorf("expected ErrTest, got: %v", err)
+	}
+
+	if list != nil {
+		t.Errorf("expected nil, got: %v", list)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// Rows scan error
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT movie_id, movie_title, description, release_date, rating FROM Movies WHERE TRUE ORDER BY COALESCE(rating, 0) DESC, movie_title ASC, movie_id ASC LIMIT $1")).
+		WillReturnRows(sqlmock.NewRows([]string{"movie_title", "description"}).
+			AddRow("Movie 1", "Description 1"))
+
+	list, err = storage.GetOrderedList(dto)
+
+	if err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	if list != nil {
+		t.Errorf("expected nil, got: %v", list)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// OK. Release dates in the cursor are compared as dates
+	byDate := []domain.SortField{{Field: "release_date", Desc: true}}
+	k, err := getMovieKeyset(byDate, domain.Page{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	after, err := k.encode([]interface{}{time.Date(1995, 12, 15, 0, 0, 0, 0, time.UTC), 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT movie_id, movie_title, description, release_date, rating FROM Movies "+
+		"WHERE (COALESCE(release_date, '0001-01-01'), movie_id) < ($1, $2) "+
+		"ORDER BY COALESCE(release_date, '0001-01-01') DESC, movie_id DESC LIMIT $3")).
+		WithArgs("1995-12-15", 2, 3).
+		WillReturnRows(sqlmock.NewRows(columns))
+
+	if _, err = storage.GetOrderedList(&domain.GetOrderedMovie{Sort: byDate, Page: domain.Page{Limit: 2, After: after}}); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// Forged cursor values of the wrong type
+	for _, values := range [][]interface{}{{"ten", 2}, {time.Now(), "2"}, {time.Now(), 2.5}} {
+		forged, err := k.encode(values)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		_, err = storage.GetOrderedList(&domain.GetOrderedMovie{Sort: byDate, Page: domain.Page{Limit: 2, After: forged}})
+		if !errors.Is(err, domain.ErrRequest) {
+			t.Errorf("%v: expected: %v, got: %v", values, domain.ErrRequest, err)
+		}
+	}
+
+	// Unknown sort field
+	list, err = storage.GetOrderedList(&domain.GetOrderedMovie{
+		Sort: []domain.SortField{{Field: "rating; DROP TABLE Movies"}},
+	})
+
+	if !errors.Is(err, domain.ErrRequest) {
+		t.Errorf("expected: %v, got: %v", domain.ErrRequest, err)
+	}
+
+	if list != nil {
+		t.Errorf("expected nil, got: %v", list)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestMovieGetActors(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("can't create mock: %s", err)
+	}
+	defer db.Close()
+
+	storage := NewMovieStorage(db)
+
+	expectTime := time.Now()
+	dto := &domain.GetMovieActors{
+		ID: 1,
+	}
+	expectedActors := []domain.Actor{
+		{ID: 1, Name: "Actor 1", Gender: "Male", DateBirth: expectTime},
+		{ID: 2, Name: "Actor 2", Gender: "Female", DateBirth: expectTime},
+	}
+
+	// OK
+	mock.ExpectQuery(`SELECT actor_id, actor_name, gender, date_of_birth FROM Actors JOIN MovieActors USING\(actor_id\) WHERE movie_id=\$1 ORDER BY actor_name`).
+		WithArgs(dto.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"actor_id", "actor_name", "gender", "date_of_birth"}).
+			AddRow(1, "Actor 1", "Male", expectTime).
+			AddRow(2, "Actor 2", "Female", expectTime))
+
+	actors, err := storage.GetActors(dto)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	if !reflect.DeepEqual(expectedActors, actors) {
+		t.Errorf("expected: %v, got: %v", expectedActors, actors)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// Rows scan error
+	mock.ExpectQuery(`SELECT actor_id, actor_name, gender, date_of_birth FROM Actors JOIN MovieActors USING\(actor_id\) WHERE movie_id=\$1 ORDER BY actor_name`).
+		WithArgs(dto.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"actor_id", "actor_name"}).
+			AddRow(1, "Actor 1"))
+
+	actors, err = storage.GetActors(dto)
+
+	if err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	if actors != nil {
+		t.Errorf("expected nil, got: %v", actors)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestMovieGetByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("can't create mock: %s", err)
+	}
+	defer db.Close()
+
+	storage := NewMovieStorage(db)
+
+	expectTime := time.Now()
+	dto := &domain.GetMovieByID{
+		ID: 1,
+	}
+	expectedMovie := &domain.MovieWithActors{
+		Movie: domain.Movie{
+			ID: 1, Title: "Movie 1", Description: "Description 1", ReleaseDate: expectTime, Rating: 5, Version: 2,
+		},
+		Actors: []domain.Actor{
+			{ID: 1, Name: "Actor 1", Gender: "Male", DateBirth: expectTime},
+		},
+	}
+
+	// OK
+	mock.ExpectQuery(`SELECT movie_id, movie_title, description, release_date, rating, version FROM Movies WHERE movie_id=\$1`).
+		WithArgs(dto.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"movie_id", "movie_title", "description", "release_date", "rating", "version"}).
+			AddRow(1, "Movie 1", "Description 1", expectTime, 5, 2))
+	mock.ExpectQuery(`SELECT actor_id, actor_name, gender, date_of_birth FROM Actors JOIN MovieActors USING\(actor_id\) WHERE movie_id=\$1 ORDER BY actor_name`).
+		WithArgs(dto.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"actor_id", "actor_name", "gender", "date_of_birth"}).
+			AddRow(1, "Actor 1", "Male", expectTime))
+
+	movie, err := storage.GetByID(dto)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	if !reflect.DeepEqual(expectedMovie, movie) {
+		t.Errorf("expected: %v, got: %v", expectedMovie, movie)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// Movie not found
+	mock.ExpectQuery(`SELECT movie_id, movie_title, description, release_date, rating, version FROM Movies WHERE movie_id=\$1`).
+		WithArgs(dto.ID).
+		WillReturnError(sql.ErrNoRows)
+
+	movie, err = storage.GetByID(dto)
+
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected: %v, got: %v", domain.ErrNotFound, err)
+	}
+
+	if movie != nil {
+		t.Errorf("expected nil, got: %v", movie)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// Cast query returned error
+	mock.ExpectQuery(`SELECT movie_id, movie_title, description, release_date, rating, version FROM Movies WHERE movie_id=\$1`).
+		WithArgs(dto.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"movie_id", "movie_title", "description", "release_date", "rating", "version"}).
+			AddRow(1, "Movie 1", "Description 1", expectTime, 5, 2))
+	mock.ExpectQuery(`SELECT actor_id, actor_name, gender, date_of_birth FROM Actors JOIN MovieActors USING\(actor_id\) WHERE movie_id=\$1 ORDER BY actor_name`).
+		WithArgs(dto.ID).
+		WillReturnError(domain.ErrTest)
+
+	movie, err = storage.GetByID(dto)
+
+	if err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	if movie != nil {
+		t.Errorf("expected nil, got: %v", movie)
 	}
 
 	if err = mock.ExpectationsWereMet(); err != nil {
@@ -0,0 +1,30 @@
+package postgresqldb
+
+import (
+	"fmt"
+	"strings"
+)
+
+// patchSet collects the columns of a partial UPDATE, only those the
+// client actually supplied.
+type patchSet struct {
+	sets   []string
+	params []interface{}
+}
+
+func (p *patchSet) add(column string, value interface{}) {
+	p.params = append(p.params, value)
+	p.sets = append(p.sets, fmt.Sprintf("%s = $%d", column, len(p.params)))
+}
+
+// query builds the UPDATE of the row with the given key and version. The
+// version is bumped even by an empty patch, so that a missing or stale row
+// is reported either way.
+func (p *patchSet) query(table, key string, id, version int64) (string, []interface{}) {
+	sets := append(p.sets, "version = version + 1")
+	params := append(p.params, id)
+
+	cond, condParams := versionCond(version, len(params)+1)
+
+	return fmt.Sprintf("UPDATE %s SET %s WHERE %s = $%d%s", table, strings.Join(sets, ", "), key, len(params), cond), append(params, condParams...)
+}
@@ -0,0 +1,49 @@
+package postgresqldb
+
+import (
+	"database/sql"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+)
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanMovie scans movie_id, movie_title, description, release_date and rating,
+// then the rest. An imported movie may lack the last three, their NULLs are
+// read as zero values.
+func scanMovie(row scanner, movie *domain.Movie, rest ...interface{}) error {
+	var (
+		description sql.NullString
+		releaseDate sql.NullTime
+		rating      sql.NullInt64
+	)
+
+	err := row.Scan(append([]interface{}{&movie.ID, &movie.Title, &description, &releaseDate, &rating}, rest...)...)
+	if err != nil {
+		return err
+	}
+
+	movie.Description, movie.ReleaseDate, movie.Rating = description.String, releaseDate.Time, uint8(rating.Int64)
+
+	return nil
+}
+
+// scanActor scans actor_id, actor_name, gender and date_of_birth, then the
+// rest, reading NULLs as zero values like scanMovie.
+func scanActor(row scanner, actor *domain.Actor, rest ...interface{}) error {
+	var (
+		gender    sql.NullString
+		dateBirth sql.NullTime
+	)
+
+	err := row.Scan(append([]interface{}{&actor.ID, &actor.Name, &gender, &dateBirth}, rest...)...)
+	if err != nil {
+		return err
+	}
+
+	actor.Gender, actor.DateBirth = gender.String, dateBirth.Time
+
+	return nil
+}
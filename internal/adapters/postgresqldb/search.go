@@ -0,0 +1,82 @@
+package postgresqldb
+
+import (
+	"fmt"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+)
+
+// searchColumns maps a search language to the tsvector column built with
+// its configuration.
+var searchColumns = map[string]string{
+	"english": "search_english",
+	"russian": "search_russian",
+	"simple":  "search_simple",
+}
+
+// escapeHTML is the SQL escaping the HTML special characters of a text
+// expression, so that the <mark> tags ts_headline adds are the only markup
+// in a headline.
+func escapeHTML(expr string) string {
+	for _, r := range [][2]string{{"&", "&amp;"}, {"<", "&lt;"}, {">", "&gt;"}, {`"`, "&quot;"}, {"''", "&#39;"}} {
+		expr = fmt.Sprintf("replace(%s, '%s', '%s')", expr, r[0], r[1])
+	}
+	return expr
+}
+
+const (
+	titleHeadline   = "HighlightAll=true, StartSel=<mark>, StopSel=</mark>"
+	snippetHeadline = "StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10"
+)
+
+// Search finds the movies matching a websearch_to_tsquery query, the ones
+// matching it the closest first. Only the movies of the page get their
+// headlines, as ts_headline works on the whole text rather than the index.
+// The text is HTML-escaped before it is highlighted.
+func (s *movieStorage) Search(dto *domain.SearchMovies) (*domain.SearchPage, error) {
+	column, ok := searchColumns[dto.Language]
+	if !ok {
+		return nil, domain.NewFieldError(domain.ErrRequest, "lang", "unknown search language")
+	}
+
+	page := domain.SearchPage{
+		Movies: make([]domain.MovieHit, 0, dto.Limit),
+		Limit:  dto.Limit,
+		Offset: dto.Offset,
+	}
+
+	err := s.db.QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM Movies WHERE %s @@ websearch_to_tsquery($1, $2)", column),
+		dto.Language, dto.Query).Scan(&page.Total)
+	if err != nil {
+		return nil, dbError(err)
+	}
+
+	query := fmt.Sprintf("SELECT h.movie_id, h.movie_title, h.description, h.release_date, h.rating, h.rank, "+
+		"ts_headline($1, %[4]s, h.q, '%[2]s'), ts_headline($1, %[5]s, h.q, '%[3]s') "+
+		"FROM (SELECT m.movie_id, m.movie_title, m.description, m.release_date, m.rating, ts_rank_cd(m.%[1]s, q) AS rank, q "+
+		"FROM Movies m, websearch_to_tsquery($1, $2) q WHERE m.%[1]s @@ q ORDER BY rank DESC, m.movie_id LIMIT $3 OFFSET $4) h "+
+		"ORDER BY h.rank DESC, h.movie_id", column, titleHeadline, snippetHeadline,
+		escapeHTML("h.movie_title"), escapeHTML("COALESCE(h.description, '')"))
+
+	rows, err := s.db.Query(query, dto.Language, dto.Query, dto.Limit, dto.Offset)
+	if err != nil {
+		return nil, dbError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		hit := domain.MovieHit{}
+
+		if err = scanMovie(rows, &hit.Movie, &hit.Rank, &hit.TitleHighlight, &hit.Snippet); err != nil {
+			return nil, dbError(err)
+		}
+
+		page.Movies = append(page.Movies, hit)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, dbError(err)
+	}
+
+	return &page, nil
+}
@@ -0,0 +1,84 @@
+package postgresqldb
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestMovieSearch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("can't create mock: %s", err)
+	}
+	defer db.Close()
+
+	storage := NewMovieStorage(db)
+
+	releaseDate := time.Date(1995, 12, 15, 0, 0, 0, 0, time.UTC)
+	dto := &domain.SearchMovies{Query: `"bank robbers" -comedy`, Language: "english", Limit: 2, Offset: 0}
+
+	// OK
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM Movies WHERE search_english @@ websearch_to_tsquery\(\$1, \$2\)`).
+		WithArgs("english", dto.Query).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(`SELECT h.movie_id, .*ts_headline\(\$1, (replace\(){5}h\.movie_title, '&', '&amp;'\), '<', '&lt;'\).*, h\.q, .*`+
+		`ts_headline\(\$1, (replace\(){5}COALESCE\(h\.description, ''\), '&', '&amp;'\).*ts_rank_cd\(m.search_english, q\) AS rank, q `+
+		`FROM Movies m, websearch_to_tsquery\(\$1, \$2\) q WHERE m.search_english @@ q ORDER BY rank DESC, m.movie_id LIMIT \$3 OFFSET \$4\) h`).
+		WithArgs("english", dto.Query, 2, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"movie_id", "movie_title", "description", "release_date", "rating", "rank", "title", "snippet"}).
+			AddRow(1, "Heat", "A group of bank robbers", releaseDate, 8, 0.4, "Heat", "A group of <mark>bank</mark> <mark>robbers</mark>"))
+
+	page, err := storage.Search(dto)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expectedPage := &domain.SearchPage{
+		Movies: []domain.MovieHit{{
+			Movie:          domain.Movie{ID: 1, Title: "Heat", Description: "A group of bank robbers", ReleaseDate: releaseDate, Rating: 8},
+			Rank:           0.4,
+			TitleHighlight: "Heat",
+			Snippet:        "A group of <mark>bank</mark> <mark>robbers</mark>",
+		}},
+		Total: 1,
+		Limit: 2,
+	}
+
+	if !reflect.DeepEqual(page, expectedPage) {
+		t.Errorf("expected: %+v, got: %+v", expectedPage, page)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// Unknown language
+	if _, err = storage.Search(&domain.SearchMovies{Query: "heat", Language: "klingon", Limit: 2}); !errors.Is(err, domain.ErrRequest) {
+		t.Errorf("expected ErrRequest, got: %v", err)
+	}
+
+	// Postgres returned error
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM Movies WHERE search_russian @@`).
+		WithArgs("russian", "жара").
+		WillReturnError(domain.ErrTest)
+
+	if _, err = storage.Search(&domain.SearchMovies{Query: "жара", Language: "russian", Limit: 2}); err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestEscapeHTML(t *testing.T) {
+	expected := `replace(replace(replace(replace(replace(h.movie_title, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;'), '''', '&#39;')`
+	if expr := escapeHTML("h.movie_title"); expr != expected {
+		t.Errorf("expected: %s, got: %s", expected, expr)
+	}
+}
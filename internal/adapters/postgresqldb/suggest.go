@@ -0,0 +1,93 @@
+package postgresqldb
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+)
+
+// suggestTables maps a suggestion type to the table and columns it is read from.
+var suggestTables = map[string]struct{ table, id, text string }{
+	"movie": {"Movies", "movie_id", "movie_title"},
+	"actor": {"Actors", "actor_id", "actor_name"},
+}
+
+type suggestStorage struct {
+	db *sql.DB
+}
+
+func NewSuggestStorage(db *sql.DB) *suggestStorage {
+	return &suggestStorage{
+		db: db,
+	}
+}
+
+// Suggest returns the titles and names completing a prefix: exact matches
+// first, then the ones starting with it, then the ones with a later word
+// starting with it, the shortest first within each. Every type is limited
+// on its own before they are merged, so that each query stays on its index.
+// Prefixes shorter than domain.MinWordSuggestLength can't use the trigram
+// index and only complete the start of a title or a name.
+func (s *suggestStorage) Suggest(dto *domain.Suggest) (*domain.SuggestList, error) {
+	prefix := strings.ToLower(dto.Prefix)
+	params := []interface{}{prefix, escapeLike(prefix) + "%"}
+
+	words := utf8.RuneCountInString(prefix) >= domain.MinWordSuggestLength
+	if words {
+		params = append(params, "% "+escapeLike(prefix)+"%")
+	}
+	params = append(params, dto.Limit)
+	limit := fmt.Sprintf("$%d", len(params))
+
+	branches := make([]string, 0, len(dto.Types))
+	for _, kind := range dto.Types {
+		t, ok := suggestTables[kind]
+		if !ok {
+			return nil, domain.NewFieldError(domain.ErrRequest, "types", "unknown suggestion type "+kind)
+		}
+
+		where := fmt.Sprintf("LOWER(%s) LIKE $2", t.text)
+		if words {
+			where += fmt.Sprintf(" OR LOWER(%s) LIKE $3", t.text)
+		}
+
+		branches = append(branches, fmt.Sprintf("(SELECT '%[1]s' AS type, %[3]s AS id, %[4]s AS text, "+
+			"CASE WHEN LOWER(%[4]s) = $1 THEN 0 WHEN LOWER(%[4]s) LIKE $2 THEN 1 ELSE 2 END AS rank "+
+			"FROM %[2]s WHERE %[5]s "+
+			"ORDER BY rank, LENGTH(%[4]s), %[4]s, %[3]s LIMIT %[6]s)", kind, t.table, t.id, t.text, where, limit))
+	}
+
+	list := domain.SuggestList{
+		Suggestions: make([]domain.Suggestion, 0, dto.Limit),
+	}
+
+	if len(branches) == 0 {
+		return &list, nil
+	}
+
+	rows, err := s.db.Query("SELECT type, id, text FROM ("+strings.Join(branches, " UNION ALL ")+") s "+
+		"ORDER BY rank, LENGTH(text), text, type, id LIMIT "+limit, params...)
+	if err != nil {
+		return nil, dbError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		suggestion := domain.Suggestion{}
+
+		if err = rows.Scan(&suggestion.Type, &suggestion.ID, &suggestion.Text); err != nil {
+			return nil, dbError(err)
+		}
+
+		list.Suggestions = append(list.Suggestions, suggestion)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, dbError(err)
+	}
+
+	return &list, nil
+}
@@ -0,0 +1,121 @@
+package postgresqldb
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestSuggest(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("can't create mock: %s", err)
+	}
+	defer db.Close()
+
+	storage := NewSuggestStorage(db)
+
+	const (
+		movieBranch = "(SELECT 'movie' AS type, movie_id AS id, movie_title AS text, " +
+			"CASE WHEN LOWER(movie_title) = $1 THEN 0 WHEN LOWER(movie_title) LIKE $2 THEN 1 ELSE 2 END AS rank " +
+			"FROM Movies WHERE LOWER(movie_title) LIKE $2 OR LOWER(movie_title) LIKE $3 " +
+			"ORDER BY rank, LENGTH(movie_title), movie_title, movie_id LIMIT $4)"
+		actorBranch = "(SELECT 'actor' AS type, actor_id AS id, actor_name AS text, " +
+			"CASE WHEN LOWER(actor_name) = $1 THEN 0 WHEN LOWER(actor_name) LIKE $2 THEN 1 ELSE 2 END AS rank " +
+			"FROM Actors WHERE LOWER(actor_name) LIKE $2 OR LOWER(actor_name) LIKE $3 " +
+			"ORDER BY rank, LENGTH(actor_name), actor_name, actor_id LIMIT $4)"
+		order = " s ORDER BY rank, LENGTH(text), text, type, id LIMIT $4"
+		// Prefixes too short for the trigram index only match the start.
+		shortMovieBranch = "(SELECT 'movie' AS type, movie_id AS id, movie_title AS text, " +
+			"CASE WHEN LOWER(movie_title) = $1 THEN 0 WHEN LOWER(movie_title) LIKE $2 THEN 1 ELSE 2 END AS rank " +
+			"FROM Movies WHERE LOWER(movie_title) LIKE $2 " +
+			"ORDER BY rank, LENGTH(movie_title), movie_title, movie_id LIMIT $3)"
+		shortOrder = " s ORDER BY rank, LENGTH(text), text, type, id LIMIT $3"
+	)
+	columns := []string{"type", "id", "text"}
+
+	// OK. Movies and actors mixed
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT type, id, text FROM ("+movieBranch+" UNION ALL "+actorBranch+")"+order)).
+		WithArgs("cap", "cap%", "% cap%", 5).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow("movie", 3, "Cape Fear").
+			AddRow("actor", 7, "Capucine").
+			AddRow("movie", 1, "The Captain"))
+
+	list, err := storage.Suggest(&domain.Suggest{Prefix: "Cap", Types: domain.SuggestTypes, Limit: 5})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	expected := &domain.SuggestList{Suggestions: []domain.Suggestion{
+		{Type: "movie", ID: 3, Text: "Cape Fear"},
+		{Type: "actor", ID: 7, Text: "Capucine"},
+		{Type: "movie", ID: 1, Text: "The Captain"},
+	}}
+	if !reflect.DeepEqual(expected, list) {
+		t.Errorf("expected: %v, got: %v", expected, list)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// OK. Two characters, start of the title only
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT type, id, text FROM ("+shortMovieBranch+")"+shortOrder)).
+		WithArgs("ca", "ca%", 10).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow("movie", 3, "Cape Fear"))
+
+	list, err = storage.Suggest(&domain.Suggest{Prefix: "Ca", Types: []string{"movie"}, Limit: 10})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	expected = &domain.SuggestList{Suggestions: []domain.Suggestion{{Type: "movie", ID: 3, Text: "Cape Fear"}}}
+	if !reflect.DeepEqual(expected, list) {
+		t.Errorf("expected: %v, got: %v", expected, list)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// OK. Actors only, LIKE wildcards escaped
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT type, id, text FROM ("+actorBranch+")"+order)).
+		WithArgs("50%", `50\%%`, `% 50\%%`, 10).
+		WillReturnRows(sqlmock.NewRows(columns))
+
+	list, err = storage.Suggest(&domain.Suggest{Prefix: "50%", Types: []string{"actor"}, Limit: 10})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	if list == nil || len(list.Suggestions) != 0 {
+		t.Errorf("expected no suggestions, got: %v", list)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// Unknown type
+	_, err = storage.Suggest(&domain.Suggest{Prefix: "ca", Types: []string{"genre"}, Limit: 10})
+	if !errors.Is(err, domain.ErrRequest) {
+		t.Errorf("expected: %v, got: %v", domain.ErrRequest, err)
+	}
+
+	// Postgres returned error
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT type, id, text FROM (" + movieBranch + ")")).
+		WillReturnError(domain.ErrTest)
+
+	if _, err = storage.Suggest(&domain.Suggest{Prefix: "cap", Types: []string{"movie"}, Limit: 10}); err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -2,6 +2,8 @@ package postgresqldb
 
 import (
 	"database/sql"
+	"errors"
+	"fmt"
 
 	"github.com/akrovv/filmlibrary/internal/domain"
 	"github.com/akrovv/filmlibrary/pkg/hasher"
@@ -9,41 +11,154 @@ import (
 
 type userStorage struct {
 	db     *sql.DB
-	hasher hasher.Hasher
+	hasher hasher.PasswordHasher
+	// dummy is verified against when there is no such user, so that the
+	// time a login takes doesn't tell whether a username is taken.
+	dummy string
 }
 
-func NewUserStorage(db *sql.DB, hasher hasher.Hasher) *userStorage {
+func NewUserStorage(db *sql.DB, hasher hasher.PasswordHasher) *userStorage {
+	dummy, _ := hasher.Hash("")
+
 	return &userStorage{
 		db:     db,
 		hasher: hasher,
+		dummy:  dummy,
 	}
 }
 
 func (s *userStorage) Register(user *domain.CRUser) error {
-	hashedPassword, err := s.hasher.GetHash(user.Password)
+	hashedPassword, err := s.hasher.Hash(user.Password)
 	if err != nil {
 		return err
 	}
 
 	_, err = s.db.Exec("INSERT INTO Users (username, password) VALUES ($1, $2)", user.Username, hashedPassword)
 	if err != nil {
-		return err
+		return dbError(err)
 	}
 
 	return nil
 }
 
+// Login verifies the password against the stored hash. A hash of an outdated
+// algorithm or parameters is replaced on the way, unless the password was
+// changed meanwhile. A failed replacement doesn't fail the login, the next
+// one tries again.
 func (s *userStorage) Login(user *domain.CRUser) (*domain.User, error) {
-	hashedPassword, err := s.hasher.GetHash(user.Password)
+	var (
+		curUser        = domain.User{}
+		hashedPassword string
+	)
+
+	err := s.db.QueryRow("SELECT username, password, is_admin FROM Users WHERE username=$1", user.Username).
+		Scan(&curUser.Username, &hashedPassword, &curUser.IsAdmin)
+	if errors.Is(err, sql.ErrNoRows) {
+		_, _, _ = s.hasher.Verify(user.Password, s.dummy)
+		return nil, fmt.Errorf("%w: wrong username or password", domain.ErrUnauthorized)
+	}
 	if err != nil {
-		return nil, err
+		return nil, dbError(err)
 	}
 
-	curUser := domain.User{}
-	if err = s.db.QueryRow("SELECT username, is_admin FROM Users WHERE username=$1 AND password=$2", user.Username,
-		hashedPassword).Scan(&curUser.Username, &curUser.IsAdmin); err != nil {
+	ok, rehash, err := s.hasher.Verify(user.Password, hashedPassword)
+	if err != nil {
 		return nil, err
 	}
+	if !ok {
+		return nil, fmt.Errorf("%w: wrong username or password", domain.ErrUnauthorized)
+	}
+
+	if rehash {
+		if newHash, err := s.hasher.Hash(user.Password); err == nil {
+			_, _ = s.db.Exec("UPDATE Users SET password = $3 WHERE username = $1 AND password = $2",
+				curUser.Username, hashedPassword, newHash)
+		}
+	}
 
 	return &curUser, nil
 }
+
+func (s *userStorage) Get(dto *domain.GetUser) (*domain.User, error) {
+	user := domain.User{}
+
+	err := s.db.QueryRow("SELECT username, is_admin FROM Users WHERE username = $1", dto.Username).
+		Scan(&user.Username, &user.IsAdmin)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: no such user", domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, dbError(err)
+	}
+
+	return &user, nil
+}
+
+func (s *userStorage) SetPassword(user *domain.CRUser) error {
+	hashedPassword, err := s.hasher.Hash(user.Password)
+	if err != nil {
+		return err
+	}
+
+	result, err := s.db.Exec("UPDATE Users SET password = $2 WHERE username = $1", user.Username, hashedPassword)
+	if err != nil {
+		return dbError(err)
+	}
+
+	return userAffected(result)
+}
+
+func (s *userStorage) SetAdmin(dto *domain.SetAdmin) error {
+	result, err := s.db.Exec("UPDATE Users SET is_admin = $2 WHERE username = $1", dto.Username, dto.IsAdmin)
+	if err != nil {
+		return dbError(err)
+	}
+
+	return userAffected(result)
+}
+
+func (s *userStorage) Delete(dto *domain.DeleteUser) error {
+	result, err := s.db.Exec("DELETE FROM Users WHERE username = $1", dto.Username)
+	if err != nil {
+		return dbError(err)
+	}
+
+	return userAffected(result)
+}
+
+func (s *userStorage) List() ([]domain.User, error) {
+	rows, err := s.db.Query("SELECT username, is_admin FROM Users ORDER BY username")
+	if err != nil {
+		return nil, dbError(err)
+	}
+	defer rows.Close()
+
+	users := make([]domain.User, 0)
+	for rows.Next() {
+		user := domain.User{}
+		if err = rows.Scan(&user.Username, &user.IsAdmin); err != nil {
+			return nil, dbError(err)
+		}
+
+		users = append(users, user)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, dbError(err)
+	}
+
+	return users, nil
+}
+
+// userAffected reports a write to a user that doesn't exist as not found.
+func userAffected(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("%w: no such user", domain.ErrNotFound)
+	}
+
+	return nil
+}
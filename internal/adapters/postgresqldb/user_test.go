@@ -1,14 +1,46 @@
 package postgresqldb
 
 import (
+	"database/sql/driver"
+	"errors"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/akrovv/filmlibrary/internal/domain"
 	"github.com/akrovv/filmlibrary/pkg/hasher"
+	"github.com/lib/pq"
 	"gopkg.in/DATA-DOG/go-sqlmock.v1"
 )
 
+// testArgon2id is cheap enough for the tests, and unlike the parameters of
+// newTestHasher so that its hashes ask for a rehash.
+var testArgon2id = hasher.Argon2idParams{Memory: 1024, Time: 1, Threads: 1, SaltLength: 16, KeyLength: 32}
+
+func newTestHasher() hasher.PasswordHasher {
+	params := testArgon2id
+	params.Time = 2
+
+	return hasher.NewUpgradingHasher(hasher.NewArgon2id(params), hasher.NewBcrypt(4), hasher.NewMD5Verifier([]byte("secret")))
+}
+
+// passwordHash matches an argument hashing the password with the current
+// parameters of the hasher.
+type passwordHash struct {
+	hasher   hasher.PasswordVerifier
+	password string
+}
+
+func (a passwordHash) Match(value driver.Value) bool {
+	encoded, ok := value.(string)
+	if !ok {
+		return false
+	}
+
+	ok, rehash, err := a.hasher.Verify(a.password, encoded)
+	return ok && !rehash && err == nil
+}
+
 func TestUserCreate(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -21,18 +53,12 @@ func TestUserCreate(t *testing.T) {
 		Password: "user",
 	}
 
-	badHasher := hasher.NewBadHasher()
-	hasher := hasher.NewHasher([]byte("secret"))
-	hashedPassword, err := hasher.GetHash(user.Password)
-	if err != nil {
-		t.Fatalf("unexpected error: %s", err)
-	}
-
-	storage := NewUserStorage(db, hasher)
+	userHasher := newTestHasher()
+	storage := NewUserStorage(db, userHasher)
 
-	// OK
+	// OK, hashed with a salt of its own
 	mock.ExpectExec(`INSERT INTO Users \(username, password\) VALUES \(\$1, \$2\)`).
-		WithArgs(user.Username, hashedPassword).
+		WithArgs(user.Username, passwordHash{userHasher, user.Password}).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	err = storage.Register(user)
@@ -46,7 +72,7 @@ func TestUserCreate(t *testing.T) {
 
 	// Postgres returned error
 	mock.ExpectExec(`INSERT INTO Users \(username, password\) VALUES \(\$1, \$2\)`).
-		WithArgs(user.Username, hashedPassword).
+		WithArgs(user.Username, passwordHash{userHasher, user.Password}).
 		WillReturnError(domain.ErrTest)
 
 	err = storage.Register(user)
@@ -58,8 +84,26 @@ func TestUserCreate(t *testing.T) {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 	}
 
-	// GetHash returned error
-	storage = NewUserStorage(db, badHasher)
+	// Username already taken
+	mock.ExpectExec(`INSERT INTO Users \(username, password\) VALUES \(\$1, \$2\)`).
+		WithArgs(user.Username, passwordHash{userHasher, user.Password}).
+		WillReturnError(&pq.Error{Code: "23505", Message: "duplicate key value violates unique constraint"})
+
+	err = storage.Register(user)
+	if !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("expected ErrConflict, got: %v", err)
+	}
+
+	if strings.Contains(err.Error(), "constraint") {
+		t.Errorf("expected the Postgres message to be hidden, got: %v", err)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// Hash returned error
+	storage = NewUserStorage(db, hasher.NewBadPasswordHasher())
 
 	err = storage.Register(user)
 	if err == nil {
@@ -87,20 +131,32 @@ func TestUserLogin(t *testing.T) {
 		Password: "user",
 	}
 
-	badHasher := hasher.NewBadHasher()
-	hasher := hasher.NewHasher([]byte("secret"))
-	hashedPassword, err := hasher.GetHash("user")
+	userHasher := newTestHasher()
+	storage := NewUserStorage(db, userHasher)
+
+	current, err := userHasher.Hash("user")
 	if err != nil {
 		t.Fatalf("unexpected error: %s", err)
 	}
 
-	storage := NewUserStorage(db, hasher)
+	outdated, err := hasher.NewArgon2id(testArgon2id).Hash("user")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	bcrypted, err := hasher.NewBcrypt(4).Hash("user")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	const selectUser = "SELECT username, password, is_admin FROM Users WHERE username=\\$1"
+	const rehash = "UPDATE Users SET password = \\$3 WHERE username = \\$1 AND password = \\$2"
+	columns := []string{"username", "password", "is_admin"}
 
 	// OK
-	mock.ExpectQuery("SELECT username, is_admin FROM Users WHERE username=\\$1 AND password=\\$2").
-		WithArgs(expectedUser.Username, hashedPassword).
-		WillReturnRows(sqlmock.NewRows([]string{"username", "is_admin"}).
-			AddRow(expectedUser.Username, false))
+	mock.ExpectQuery(selectUser).
+		WithArgs(expectedUser.Username).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(expectedUser.Username, current, false))
 
 	user, err := storage.Login(userTest)
 	if err != nil {
@@ -115,9 +171,56 @@ func TestUserLogin(t *testing.T) {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 	}
 
+	// OK. Outdated parameters, bcrypt and the MD5 of the former hasher are rehashed
+	for name, stored := range map[string]string{
+		"argon2id": outdated,
+		"bcrypt":   bcrypted,
+		"md5":      "73656372657421232f297a57a5a743894a0e4a801fc3",
+	} {
+		password := userTest.Password
+		if name == "md5" {
+			password = "admin"
+		}
+
+		mock.ExpectQuery(selectUser).
+			WithArgs(expectedUser.Username).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(expectedUser.Username, stored, false))
+		mock.ExpectExec(rehash).
+			WithArgs(expectedUser.Username, stored, passwordHash{userHasher, password}).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		user, err = storage.Login(&domain.CRUser{Username: "user", Password: password})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", name, err)
+		}
+
+		if !reflect.DeepEqual(user, expectedUser) {
+			t.Errorf("%s: expected: %v, got: %v", name, expectedUser, user)
+		}
+
+		if err = mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("%s: there were unfulfilled expectations: %s", name, err)
+		}
+	}
+
+	// OK. A failed rehash doesn't fail the login
+	mock.ExpectQuery(selectUser).
+		WithArgs(expectedUser.Username).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(expectedUser.Username, outdated, false))
+	mock.ExpectExec(rehash).
+		WillReturnError(domain.ErrTest)
+
+	if _, err = storage.Login(userTest); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
 	// Postgres returned error
-	mock.ExpectQuery("SELECT username, is_admin FROM Users WHERE username=\\$1 AND password=\\$2").
-		WithArgs(expectedUser.Username, hashedPassword).
+	mock.ExpectQuery(selectUser).
+		WithArgs(expectedUser.Username).
 		WillReturnError(domain.ErrTest)
 
 	user, err = storage.Login(userTest)
@@ -133,8 +236,44 @@ func TestUserLogin(t *testing.T) {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 	}
 
-	// GetHash returned error
-	storage = NewUserStorage(db, badHasher)
+	// Wrong password or no such user
+	for name, rows := range map[string]*sqlmock.Rows{
+		"wrong password": sqlmock.NewRows(columns).AddRow(expectedUser.Username, current, false),
+		"no such user":   sqlmock.NewRows(columns),
+	} {
+		mock.ExpectQuery(selectUser).
+			WithArgs(expectedUser.Username).
+			WillReturnRows(rows)
+
+		user, err = storage.Login(&domain.CRUser{Username: "user", Password: "wrong"})
+		if !errors.Is(err, domain.ErrUnauthorized) {
+			t.Errorf("%s: expected ErrUnauthorized, got: %v", name, err)
+		}
+
+		if user != nil {
+			t.Errorf("%s: expected nil, got: %v", name, user)
+		}
+
+		if err = mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("%s: there were unfulfilled expectations: %s", name, err)
+		}
+	}
+
+	// Unknown hash format
+	mock.ExpectQuery(selectUser).
+		WithArgs(expectedUser.Username).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(expectedUser.Username, "plain", false))
+
+	if _, err = storage.Login(userTest); !errors.Is(err, hasher.ErrUnknownHash) {
+		t.Errorf("expected ErrUnknownHash, got: %v", err)
+	}
+
+	// Verify returned error
+	storage = NewUserStorage(db, hasher.NewBadPasswordHasher())
+
+	mock.ExpectQuery(selectUser).
+		WithArgs(expectedUser.Username).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(expectedUser.Username, current, false))
 
 	user, err = storage.Login(userTest)
 	if err == nil {
@@ -149,3 +288,227 @@ func TestUserLogin(t *testing.T) {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 	}
 }
+
+func TestUserGet(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("can't create mock: %s", err)
+	}
+	defer db.Close()
+
+	storage := NewUserStorage(db, newTestHasher())
+	dto := &domain.GetUser{Username: "admin"}
+
+	// OK
+	mock.ExpectQuery(`SELECT username, is_admin FROM Users WHERE username = \$1`).
+		WithArgs(dto.Username).
+		WillReturnRows(sqlmock.NewRows([]string{"username", "is_admin"}).AddRow("admin", true))
+
+	user, err := storage.Get(dto)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if expected := (&domain.User{Username: "admin", IsAdmin: true}); !reflect.DeepEqual(user, expected) {
+		t.Errorf("expected: %v, got: %v", expected, user)
+	}
+
+	// No such user
+	mock.ExpectQuery(`SELECT username, is_admin FROM Users WHERE username = \$1`).
+		WithArgs(dto.Username).
+		WillReturnRows(sqlmock.NewRows([]string{"username", "is_admin"}))
+
+	if _, err = storage.Get(dto); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got: %v", err)
+	}
+
+	// Postgres returned error
+	mock.ExpectQuery(`SELECT username, is_admin FROM Users WHERE username = \$1`).
+		WithArgs(dto.Username).
+		WillReturnError(domain.ErrTest)
+
+	if _, err = storage.Get(dto); err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestUserSetPassword(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("can't create mock: %s", err)
+	}
+	defer db.Close()
+
+	user := &domain.CRUser{
+		Username: "user",
+		Password: "new",
+	}
+
+	userHasher := newTestHasher()
+	storage := NewUserStorage(db, userHasher)
+
+	// OK
+	mock.ExpectExec(`UPDATE Users SET password = \$2 WHERE username = \$1`).
+		WithArgs(user.Username, passwordHash{userHasher, user.Password}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err = storage.SetPassword(user); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// No such user
+	mock.ExpectExec(`UPDATE Users SET password = \$2 WHERE username = \$1`).
+		WithArgs(user.Username, passwordHash{userHasher, user.Password}).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	if err = storage.SetPassword(user); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got: %v", err)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestUserSetAdmin(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("can't create mock: %s", err)
+	}
+	defer db.Close()
+
+	storage := NewUserStorage(db, newTestHasher())
+	dto := &domain.SetAdmin{
+		Username: "user",
+		IsAdmin:  true,
+	}
+
+	// OK
+	mock.ExpectExec(`UPDATE Users SET is_admin = \$2 WHERE username = \$1`).
+		WithArgs(dto.Username, dto.IsAdmin).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err = storage.SetAdmin(dto); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// No such user
+	mock.ExpectExec(`UPDATE Users SET is_admin = \$2 WHERE username = \$1`).
+		WithArgs(dto.Username, dto.IsAdmin).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	if err = storage.SetAdmin(dto); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got: %v", err)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// Postgres returned error
+	mock.ExpectExec(`UPDATE Users SET is_admin = \$2 WHERE username = \$1`).
+		WithArgs(dto.Username, dto.IsAdmin).
+		WillReturnError(domain.ErrTest)
+
+	if err = storage.SetAdmin(dto); err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestUserDelete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("can't create mock: %s", err)
+	}
+	defer db.Close()
+
+	storage := NewUserStorage(db, newTestHasher())
+	dto := &domain.DeleteUser{Username: "user"}
+
+	// OK
+	mock.ExpectExec(`DELETE FROM Users WHERE username = \$1`).
+		WithArgs(dto.Username).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err = storage.Delete(dto); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// No such user
+	mock.ExpectExec(`DELETE FROM Users WHERE username = \$1`).
+		WithArgs(dto.Username).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	if err = storage.Delete(dto); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got: %v", err)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestUserList(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("can't create mock: %s", err)
+	}
+	defer db.Close()
+
+	storage := NewUserStorage(db, newTestHasher())
+	expectedUsers := []domain.User{
+		{Username: "admin", IsAdmin: true},
+		{Username: "user"},
+	}
+
+	// OK
+	mock.ExpectQuery(`SELECT username, is_admin FROM Users ORDER BY username`).
+		WillReturnRows(sqlmock.NewRows([]string{"username", "is_admin"}).
+			AddRow("admin", true).
+			AddRow("user", false))
+
+	users, err := storage.List()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if !reflect.DeepEqual(users, expectedUsers) {
+		t.Errorf("expected: %v, got: %v", expectedUsers, users)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// Postgres returned error
+	mock.ExpectQuery(`SELECT username, is_admin FROM Users ORDER BY username`).
+		WillReturnError(domain.ErrTest)
+
+	if _, err = storage.List(); err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,69 @@
+package postgresqldb
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+)
+
+// rowQueryer is satisfied by both *sql.DB and *sql.Tx.
+type rowQueryer interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// versionCond returns the condition that makes a write apply only to the
+// given version of a row, the zero version matches any.
+func versionCond(version int64, n int) (string, []interface{}) {
+	if version == 0 {
+		return "", nil
+	}
+
+	return fmt.Sprintf(" AND version = $%d", n), []interface{}{version}
+}
+
+// checkVersion tells why a conditional write touched no rows: either the row
+// doesn't exist or its version has moved on.
+func checkVersion(q rowQueryer, table, key string, id int64, resource string) error {
+	var version int64
+
+	err := q.QueryRow(fmt.Sprintf("SELECT version FROM %s WHERE %s = $1", table, key), id).Scan(&version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrNotFound
+	}
+	if err != nil {
+		return dbError(err)
+	}
+
+	return &domain.VersionConflictError{
+		Resource: resource,
+		ID:       id,
+		Version:  version,
+	}
+}
+
+// lockVersion locks a row for the rest of the transaction and checks it is at
+// the given version, the zero version matches any. It is for writes whose
+// effect on the row is only known after they are made.
+func lockVersion(tx *sql.Tx, table, key string, id, version int64, resource string) error {
+	var current int64
+
+	err := tx.QueryRow(fmt.Sprintf("SELECT version FROM %s WHERE %s = $1 FOR UPDATE", table, key), id).Scan(&current)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrNotFound
+	}
+	if err != nil {
+		return dbError(err)
+	}
+
+	if version != 0 && current != version {
+		return &domain.VersionConflictError{
+			Resource: resource,
+			ID:       id,
+			Version:  current,
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package redisdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+	"github.com/redis/go-redis/v9"
+)
+
+const DefaultRefreshTokenTTL = time.Hour * 24 * 30
+
+type refreshRecord struct {
+	Username  string    `json:"username"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type refreshTokenStorage struct {
+	ctx   context.Context
+	db    *redis.Client
+	ttl   time.Duration
+	token func() (string, error)
+	now   func() time.Time
+}
+
+// NewRefreshTokenStorage returns a storage of refresh tokens valid for ttl,
+// DefaultRefreshTokenTTL when it is zero. Like sessions, they are keyed by
+// their digest and indexed per user.
+func NewRefreshTokenStorage(ctx context.Context, db *redis.Client, ttl time.Duration) *refreshTokenStorage {
+	if ttl <= 0 {
+		ttl = DefaultRefreshTokenTTL
+	}
+
+	return &refreshTokenStorage{
+		ctx:   ctx,
+		db:    db,
+		ttl:   ttl,
+		token: newToken,
+		now:   time.Now,
+	}
+}
+
+func refreshTokenKey(digest string) string {
+	return "refresh_token:" + digest
+}
+
+func userRefreshTokensKey(username string) string {
+	return "user_refresh_tokens:" + username
+}
+
+func (s *refreshTokenStorage) Create(user *domain.User) (string, error) {
+	token, err := s.token()
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(refreshRecord{
+		Username:  user.Username,
+		CreatedAt: s.now().UTC(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	digest := tokenDigest(token)
+	_, err = s.db.TxPipelined(s.ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(s.ctx, refreshTokenKey(digest), data, s.ttl)
+		pipe.SAdd(s.ctx, userRefreshTokensKey(user.Username), digest)
+		pipe.Expire(s.ctx, userRefreshTokensKey(user.Username), s.ttl)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// Use spends a refresh token and returns the name of the user it was issued
+// to, whose privileges are to be read from the Users table. A token used
+// once doesn't work again, even when two requests race for it.
+func (s *refreshTokenStorage) Use(dto *domain.UseRefreshToken) (*domain.User, error) {
+	digest := tokenDigest(dto.Token)
+
+	value, err := s.db.GetDel(s.ctx, refreshTokenKey(digest)).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, fmt.Errorf("%w: refresh token expired or doesn't exist", domain.ErrUnauthorized)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	record := refreshRecord{}
+	if err = json.Unmarshal([]byte(value), &record); err != nil {
+		return nil, err
+	}
+
+	if err = s.db.SRem(s.ctx, userRefreshTokensKey(record.Username), digest).Err(); err != nil {
+		return nil, err
+	}
+
+	return &domain.User{
+		Username: record.Username,
+	}, nil
+}
+
+// Revoke spends every refresh token of a user.
+func (s *refreshTokenStorage) Revoke(dto *domain.RevokeUser) error {
+	key := userRefreshTokensKey(dto.Username)
+
+	digests, err := s.db.SMembers(s.ctx, key).Result()
+	if err != nil {
+		return err
+	}
+
+	if len(digests) == 0 {
+		return nil
+	}
+
+	keys := make([]string, len(digests))
+	members := make([]interface{}, len(digests))
+	for i, digest := range digests {
+		keys[i] = refreshTokenKey(digest)
+		members[i] = digest
+	}
+
+	_, err = s.db.TxPipelined(s.ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(s.ctx, keys...)
+		pipe.SRem(s.ctx, key, members...)
+		return nil
+	})
+
+	return err
+}
@@ -0,0 +1,175 @@
+package redisdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+	"github.com/go-redis/redismock/v9"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestRefreshTokenStorage(client *redis.Client) *refreshTokenStorage {
+	storage := NewRefreshTokenStorage(context.Background(), client, time.Hour)
+	storage.token = func() (string, error) {
+		return "refresh", nil
+	}
+	storage.now = func() time.Time {
+		return sessionCreatedAt
+	}
+
+	return storage
+}
+
+func refreshData(t *testing.T, record refreshRecord) string {
+	data, err := json.Marshal(record)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	return string(data)
+}
+
+func TestCreateRefreshToken(t *testing.T) {
+	client, mock := redismock.NewClientMock()
+	defer client.Close()
+
+	storage := newTestRefreshTokenStorage(client)
+
+	digest := tokenDigest("refresh")
+	data := refreshData(t, refreshRecord{Username: "user", CreatedAt: sessionCreatedAt})
+
+	// OK
+	mock.ExpectTxPipeline()
+	mock.ExpectSet("refresh_token:"+digest, []byte(data), time.Hour).SetVal("OK")
+	mock.ExpectSAdd("user_refresh_tokens:user", digest).SetVal(1)
+	mock.ExpectExpire("user_refresh_tokens:user", time.Hour).SetVal(true)
+	mock.ExpectTxPipelineExec()
+
+	token, err := storage.Create(&domain.User{Username: "user", IsAdmin: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if token != "refresh" {
+		t.Errorf("expected: refresh, got: %s", token)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// Redis returned error
+	mock.ExpectTxPipeline()
+	mock.ExpectSet("refresh_token:"+digest, []byte(data), time.Hour).SetErr(domain.ErrTest)
+
+	if _, err = storage.Create(&domain.User{Username: "user", IsAdmin: true}); err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// Default TTL
+	if ttl := NewRefreshTokenStorage(context.Background(), client, 0).ttl; ttl != DefaultRefreshTokenTTL {
+		t.Errorf("expected default TTL, got: %s", ttl)
+	}
+}
+
+func TestUseRefreshToken(t *testing.T) {
+	client, mock := redismock.NewClientMock()
+	defer client.Close()
+
+	storage := newTestRefreshTokenStorage(client)
+
+	digest := tokenDigest("refresh")
+	useToken := domain.UseRefreshToken{Token: "refresh"}
+
+	// OK. The token is spent
+	mock.ExpectGetDel("refresh_token:" + digest).SetVal(refreshData(t, refreshRecord{Username: "user"}))
+	mock.ExpectSRem("user_refresh_tokens:user", digest).SetVal(1)
+
+	user, err := storage.Use(&useToken)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := &domain.User{Username: "user"}
+	if !reflect.DeepEqual(user, expected) {
+		t.Errorf("expected: %v, got: %v", expected, user)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// Token spent or expired
+	mock.ExpectGetDel("refresh_token:" + digest).RedisNil()
+
+	if _, err = storage.Use(&useToken); !errors.Is(err, domain.ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized, got: %v", err)
+	}
+
+	// Redis returned error
+	mock.ExpectGetDel("refresh_token:" + digest).SetErr(domain.ErrTest)
+
+	if _, err = storage.Use(&useToken); !errors.Is(err, domain.ErrTest) {
+		t.Errorf("expected ErrTest, got: %v", err)
+	}
+
+	// Redis returned incorrect json
+	mock.ExpectGetDel("refresh_token:" + digest).SetVal("incorrect json")
+
+	if _, err = storage.Use(&useToken); err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestRevokeRefreshTokens(t *testing.T) {
+	client, mock := redismock.NewClientMock()
+	defer client.Close()
+
+	storage := newTestRefreshTokenStorage(client)
+
+	// OK. Every token of the user is spent
+	mock.ExpectSMembers("user_refresh_tokens:user").SetVal([]string{"abc", "def"})
+	mock.ExpectTxPipeline()
+	mock.ExpectDel("refresh_token:abc", "refresh_token:def").SetVal(2)
+	mock.ExpectSRem("user_refresh_tokens:user", "abc", "def").SetVal(2)
+	mock.ExpectTxPipelineExec()
+
+	if err := storage.Revoke(&domain.RevokeUser{Username: "user"}); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// OK. No tokens
+	mock.ExpectSMembers("user_refresh_tokens:user").SetVal([]string{})
+
+	if err := storage.Revoke(&domain.RevokeUser{Username: "user"}); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	// Redis returned error
+	mock.ExpectSMembers("user_refresh_tokens:user").SetErr(domain.ErrTest)
+
+	if err := storage.Revoke(&domain.RevokeUser{Username: "user"}); err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -3,59 +3,463 @@ package redisdb
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
 	"time"
 
 	"github.com/akrovv/filmlibrary/internal/domain"
-	"github.com/akrovv/filmlibrary/pkg/hasher"
 	"github.com/redis/go-redis/v9"
 )
 
+const (
+	DefaultSessionIdleTimeout = time.Minute * 30
+	DefaultSessionMaxLifetime = time.Hour * 8
+
+	// sessionRotationGrace is how long the token of a rotated session keeps
+	// working, so that the requests already sent with it get through.
+	sessionRotationGrace = time.Second * 30
+)
+
+// sessionRecord is a session as kept in Redis. Rotate is set when the
+// privileges of the user change, so that the next request gets a new token.
+type sessionRecord struct {
+	domain.Session
+	Rotate bool `json:"rotate,omitempty"`
+}
+
 type sessionStorage struct {
-	ctx    context.Context
-	db     *redis.Client
-	hasher hasher.Hasher
+	ctx         context.Context
+	db          *redis.Client
+	idleTimeout time.Duration
+	maxLifetime time.Duration
+	token       func() (string, error)
+	now         func() time.Time
 }
 
-func NewSessionStorage(ctx context.Context, db *redis.Client, hasher hasher.Hasher) *sessionStorage {
+// NewSessionStorage returns a storage of sessions keyed by the digest of
+// their token, so that the tokens themselves are never written to Redis.
+// The IDs of the sessions of every user are kept in a set of their own.
+// A session expires after idleTimeout without use and maxLifetime after it
+// was created whatever the use, the defaults stand in for the zero values.
+func NewSessionStorage(ctx context.Context, db *redis.Client, idleTimeout, maxLifetime time.Duration) *sessionStorage {
+	if maxLifetime <= 0 {
+		maxLifetime = DefaultSessionMaxLifetime
+	}
+
+	if idleTimeout <= 0 {
+		idleTimeout = DefaultSessionIdleTimeout
+	}
+
+	if idleTimeout > maxLifetime {
+		idleTimeout = maxLifetime
+	}
+
 	return &sessionStorage{
-		ctx:    ctx,
-		db:     db,
-		hasher: hasher,
+		ctx:         ctx,
+		db:          db,
+		idleTimeout: idleTimeout,
+		maxLifetime: maxLifetime,
+		token:       newToken,
+		now:         time.Now,
+	}
+}
+
+// sessionID derives the public ID of a session from its token.
+func sessionID(token string) string {
+	return tokenDigest(token)
+}
+
+func sessionKey(id string) string {
+	return "session:" + id
+}
+
+// sessionAliasKey keeps the new ID of a session rotated from id.
+func sessionAliasKey(id string) string {
+	return "session_alias:" + id
+}
+
+func userSessionsKey(username string) string {
+	return "user_sessions:" + username
+}
+
+// expiresAt returns when a session used at now expires, which is never
+// later than its maximum lifetime.
+func (s *sessionStorage) expiresAt(session *domain.Session, now time.Time) time.Time {
+	expires := now.Add(s.idleTimeout)
+	if deadline := session.CreatedAt.Add(s.maxLifetime); deadline.Before(expires) {
+		return deadline
+	}
+
+	return expires
+}
+
+// save writes a session issued for token and adds it to the set of its user.
+// The SET mode XX only writes a session that is still there, the command is
+// returned to find out whether it was.
+func (s *sessionStorage) save(pipe redis.Pipeliner, token string, record *sessionRecord, now time.Time, mode string) (*domain.ActiveSession, *redis.StatusCmd, error) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	expires := s.expiresAt(&record.Session, now)
+
+	set := pipe.SetArgs(s.ctx, sessionKey(record.ID), data, redis.SetArgs{Mode: mode, TTL: expires.Sub(now)})
+	pipe.SAdd(s.ctx, userSessionsKey(record.Username), record.ID)
+	pipe.Expire(s.ctx, userSessionsKey(record.Username), s.maxLifetime)
+
+	return &domain.ActiveSession{
+		User: &domain.User{
+			Username: record.Username,
+			IsAdmin:  record.IsAdmin,
+		},
+		Token:     token,
+		ExpiresAt: expires,
+	}, set, nil
+}
+
+// Create starts a session and returns its token.
+func (s *sessionStorage) Create(dto *domain.CreateSession) (*domain.ActiveSession, error) {
+	token, err := s.token()
+	if err != nil {
+		return nil, err
 	}
+
+	now := s.now().UTC()
+	record := sessionRecord{Session: domain.Session{
+		ID:         sessionID(token),
+		Username:   dto.Username,
+		IsAdmin:    dto.IsAdmin,
+		CreatedAt:  now,
+		LastSeenAt: now,
+		IP:         dto.IP,
+		UserAgent:  dto.UserAgent,
+	}}
+
+	var active *domain.ActiveSession
+	_, err = s.db.TxPipelined(s.ctx, func(pipe redis.Pipeliner) error {
+		active, _, err = s.save(pipe, token, &record, now, "")
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return active, nil
 }
 
-func (s *sessionStorage) Create(dto *domain.CreateSession) (string, error) {
-	hashedUsername, err := s.hasher.GetHash(dto.Username)
+func (s *sessionStorage) get(db redis.Cmdable, id string) (*sessionRecord, error) {
+	value, err := db.Get(s.ctx, sessionKey(id)).Result()
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+
+	record := sessionRecord{}
+	if err = json.Unmarshal([]byte(value), &record); err != nil {
+		return nil, err
 	}
 
-	data, err := json.Marshal(dto)
+	return &record, nil
+}
+
+// Get resolves the session of a token and renews it, so that it stays alive
+// for the idle timeout more. A session marked for rotation is moved to a new
+// token and the old one stops working after sessionRotationGrace.
+func (s *sessionStorage) Get(dto *domain.GetSession) (*domain.ActiveSession, error) {
+	id := sessionID(dto.Token)
+
+	record, err := s.get(s.db, id)
+	if errors.Is(err, redis.Nil) {
+		return s.alias(id)
+	}
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	status := s.db.Set(s.ctx, hashedUsername, data, time.Hour*8)
+	now := s.now().UTC()
+	if !now.Before(record.CreatedAt.Add(s.maxLifetime)) {
+		if err = s.remove(id, record.Username); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: session expired or doesn't exist", domain.ErrUnauthorized)
+	}
 
-	if status.Err() != nil {
-		return "", status.Err()
+	if record.Rotate {
+		return s.rotate(id, now)
 	}
 
-	return hashedUsername, nil
+	record.LastSeenAt = now
+
+	// A session revoked since it was read must stay revoked, so it is
+	// written back with SET XX.
+	var (
+		active *domain.ActiveSession
+		set    *redis.StatusCmd
+	)
+	_, err = s.db.TxPipelined(s.ctx, func(pipe redis.Pipeliner) error {
+		active, set, err = s.save(pipe, dto.Token, record, now, "XX")
+		return err
+	})
+	if set != nil && errors.Is(set.Err(), redis.Nil) {
+		return nil, fmt.Errorf("%w: session expired or doesn't exist", domain.ErrUnauthorized)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return active, nil
 }
 
-func (s *sessionStorage) Get(dto *domain.GetSession) (*domain.User, error) {
-	user := domain.User{}
+// rotate moves the session of id to a new token and keeps id as an alias of
+// it for sessionRotationGrace, so that the requests of a page loading in
+// parallel aren't logged out. The old key is watched: of the requests
+// rotating the session at once the first wins and the others resolve the
+// alias it left.
+func (s *sessionStorage) rotate(id string, now time.Time) (*domain.ActiveSession, error) {
+	token, err := s.token()
+	if err != nil {
+		return nil, err
+	}
+
+	var active *domain.ActiveSession
+	err = s.db.Watch(s.ctx, func(tx *redis.Tx) error {
+		record, err := s.get(tx, id)
+		if errors.Is(err, redis.Nil) {
+			return redis.TxFailedErr
+		}
+		if err != nil {
+			return err
+		}
+
+		record.ID = sessionID(token)
+		record.Rotate = false
+		record.LastSeenAt = now
 
-	value, err := s.db.Get(s.ctx, dto.Username).Result()
+		_, err = tx.TxPipelined(s.ctx, func(pipe redis.Pipeliner) error {
+			pipe.Rename(s.ctx, sessionKey(id), sessionKey(record.ID))
+			pipe.SRem(s.ctx, userSessionsKey(record.Username), id)
+			pipe.Set(s.ctx, sessionAliasKey(id), record.ID, sessionRotationGrace)
+
+			active, _, err = s.save(pipe, token, record, now, "XX")
+			return err
+		})
+		return err
+	}, sessionKey(id))
+	if errors.Is(err, redis.TxFailedErr) {
+		return s.alias(id)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	err = json.Unmarshal([]byte(value), &user)
+	return active, nil
+}
+
+// alias resolves the ID of a session rotated less than sessionRotationGrace
+// ago. The session isn't renewed and no token is returned, the request that
+// rotated it handed out the new one.
+func (s *sessionStorage) alias(id string) (*domain.ActiveSession, error) {
+	newID, err := s.db.Get(s.ctx, sessionAliasKey(id)).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, fmt.Errorf("%w: session expired or doesn't exist", domain.ErrUnauthorized)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	return &user, nil
+	record, err := s.get(s.db, newID)
+	if errors.Is(err, redis.Nil) {
+		return nil, fmt.Errorf("%w: session expired or doesn't exist", domain.ErrUnauthorized)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.ActiveSession{
+		User: &domain.User{
+			Username: record.Username,
+			IsAdmin:  record.IsAdmin,
+		},
+		ExpiresAt: s.expiresAt(&record.Session, record.LastSeenAt),
+	}, nil
+}
+
+// List returns the sessions of a user, the latest first. The IDs of the
+// sessions expired meanwhile are dropped from the set of the user.
+func (s *sessionStorage) List(dto *domain.ListSessions) (*domain.SessionList, error) {
+	key := userSessionsKey(dto.Username)
+
+	ids, records, err := s.records(dto.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	current := ""
+	if dto.Token != "" {
+		current = sessionID(dto.Token)
+	}
+
+	list := domain.SessionList{Sessions: []domain.Session{}}
+	expired := make([]interface{}, 0)
+	for i, record := range records {
+		if record == nil {
+			expired = append(expired, ids[i])
+			continue
+		}
+
+		record.Current = record.ID == current
+		list.Sessions = append(list.Sessions, record.Session)
+	}
+
+	if len(expired) > 0 {
+		if err = s.db.SRem(s.ctx, key, expired...).Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.SliceStable(list.Sessions, func(i, j int) bool {
+		return list.Sessions[i].CreatedAt.After(list.Sessions[j].CreatedAt)
+	})
+
+	return &list, nil
+}
+
+// records returns the IDs of the sessions of a user and the sessions
+// themselves, nil for the ones that expired.
+func (s *sessionStorage) records(username string) ([]string, []*sessionRecord, error) {
+	ids, err := s.db.SMembers(s.ctx, userSessionsKey(username)).Result()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	records := make([]*sessionRecord, len(ids))
+	if len(ids) == 0 {
+		return ids, records, nil
+	}
+
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = sessionKey(id)
+	}
+
+	values, err := s.db.MGet(s.ctx, keys...).Result()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i, value := range values {
+		data, ok := value.(string)
+		if !ok {
+			continue
+		}
+
+		record := sessionRecord{}
+		if err = json.Unmarshal([]byte(data), &record); err != nil {
+			return nil, nil, err
+		}
+
+		records[i] = &record
+	}
+
+	return ids, records, nil
+}
+
+// SetAdmin changes the privileges of every session of a user and marks them
+// for rotation.
+func (s *sessionStorage) SetAdmin(dto *domain.SetAdmin) error {
+	ids, records, err := s.records(dto.Username)
+	if err != nil {
+		return err
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	_, err = s.db.TxPipelined(s.ctx, func(pipe redis.Pipeliner) error {
+		for i, record := range records {
+			if record == nil {
+				pipe.SRem(s.ctx, userSessionsKey(dto.Username), ids[i])
+				continue
+			}
+
+			record.IsAdmin = dto.IsAdmin
+			record.Rotate = true
+
+			data, err := json.Marshal(record)
+			if err != nil {
+				return err
+			}
+
+			// XX leaves alone a session revoked since it was read.
+			pipe.SetArgs(s.ctx, sessionKey(ids[i]), data, redis.SetArgs{Mode: "XX", KeepTTL: true})
+		}
+
+		return nil
+	})
+
+	return err
+}
+
+// Revoke ends every session of a user.
+func (s *sessionStorage) Revoke(dto *domain.RevokeUser) error {
+	key := userSessionsKey(dto.Username)
+
+	ids, err := s.db.SMembers(s.ctx, key).Result()
+	if err != nil {
+		return err
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	keys := make([]string, len(ids))
+	members := make([]interface{}, len(ids))
+	for i, id := range ids {
+		keys[i] = sessionKey(id)
+		members[i] = id
+	}
+
+	_, err = s.db.TxPipelined(s.ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(s.ctx, keys...)
+		pipe.SRem(s.ctx, key, members...)
+		return nil
+	})
+
+	return err
+}
+
+// Delete revokes a session. A session of someone other than dto.Username is
+// reported as not found, the same as a missing one.
+func (s *sessionStorage) Delete(dto *domain.DeleteSession) error {
+	id := dto.ID
+	if dto.Token != "" {
+		id = sessionID(dto.Token)
+	}
+
+	record, err := s.get(s.db, id)
+	if errors.Is(err, redis.Nil) {
+		return fmt.Errorf("%w: session %s", domain.ErrNotFound, id)
+	}
+	if err != nil {
+		return err
+	}
+
+	if dto.Username != "" && record.Username != dto.Username {
+		return fmt.Errorf("%w: session %s", domain.ErrNotFound, id)
+	}
+
+	return s.remove(id, record.Username)
+}
+
+func (s *sessionStorage) remove(id, username string) error {
+	_, err := s.db.TxPipelined(s.ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(s.ctx, sessionKey(id))
+		pipe.SRem(s.ctx, userSessionsKey(username), id)
+		return nil
+	})
+
+	return err
 }
@@ -3,49 +3,106 @@ package redisdb
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"reflect"
 	"testing"
 	"time"
 
 	"github.com/akrovv/filmlibrary/internal/domain"
-	"github.com/akrovv/filmlibrary/pkg/hasher"
 	"github.com/go-redis/redismock/v9"
+	"github.com/redis/go-redis/v9"
 )
 
-func TestCreate(t *testing.T) {
-	ctx := context.Background()
-	client, mock := redismock.NewClientMock()
-	defer client.Close()
+var sessionCreatedAt = time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
 
-	badHasher := hasher.NewBadHasher()
-	hasher := hasher.NewHasher([]byte("session"))
-	storage := NewSessionStorage(ctx, client, hasher)
+func newTestSessionStorage(client *redis.Client) *sessionStorage {
+	storage := NewSessionStorage(context.Background(), client, time.Minute*30, time.Hour*8)
+	storage.token = func() (string, error) {
+		return "token", nil
+	}
+	storage.now = func() time.Time {
+		return sessionCreatedAt
+	}
 
-	hashedNickname, err := hasher.GetHash("user")
+	return storage
+}
+
+func sessionData(t *testing.T, session domain.Session) string {
+	data, err := json.Marshal(session)
 	if err != nil {
 		t.Fatalf("unexpected error: %s", err)
 	}
 
-	createSession := domain.CreateSession{
-		Username: "user",
-		IsAdmin:  false,
+	return string(data)
+}
+
+func TestNewToken(t *testing.T) {
+	first, err := newToken()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
 	}
 
-	data, err := json.Marshal(createSession)
+	second, err := newToken()
 	if err != nil {
 		t.Fatalf("unexpected error: %s", err)
 	}
 
-	// OK
-	mock.ExpectSet(hashedNickname, data, time.Hour*8).SetVal(hashedNickname)
-	sessionID, err := storage.Create(&createSession)
+	if len(first) != 43 {
+		t.Errorf("expected 43 characters, got: %d", len(first))
+	}
 
+	if first == second {
+		t.Error("expected different tokens")
+	}
+
+	if sessionID(first) == first {
+		t.Error("expected session ID to differ from the token")
+	}
+}
+
+func TestCreate(t *testing.T) {
+	client, mock := redismock.NewClientMock()
+	defer client.Close()
+
+	storage := newTestSessionStorage(client)
+
+	createSession := domain.CreateSession{
+		Username:  "user",
+		IsAdmin:   false,
+		IP:        "192.0.2.1",
+		UserAgent: "curl/8.0",
+	}
+
+	id := sessionID("token")
+	data := sessionData(t, domain.Session{
+		ID:         id,
+		Username:   "user",
+		CreatedAt:  sessionCreatedAt,
+		LastSeenAt: sessionCreatedAt,
+		IP:         "192.0.2.1",
+		UserAgent:  "curl/8.0",
+	})
+
+	expected := &domain.ActiveSession{
+		User:      &domain.User{Username: "user"},
+		Token:     "token",
+		ExpiresAt: sessionCreatedAt.Add(time.Minute * 30),
+	}
+
+	// OK. The session lives for the idle timeout
+	mock.ExpectTxPipeline()
+	mock.ExpectSet("session:"+id, []byte(data), time.Minute*30).SetVal("OK")
+	mock.ExpectSAdd("user_sessions:user", id).SetVal(1)
+	mock.ExpectExpire("user_sessions:user", time.Hour*8).SetVal(true)
+	mock.ExpectTxPipelineExec()
+
+	session, err := storage.Create(&createSession)
 	if err != nil {
 		t.Fatalf("unexpected error: %s", err)
 	}
 
-	if !reflect.DeepEqual(sessionID, hashedNickname) {
-		t.Errorf("expected: %v, got: %v", hashedNickname, sessionID)
+	if !reflect.DeepEqual(session, expected) {
+		t.Errorf("expected: %+v, got: %+v", expected, session)
 	}
 
 	if err = mock.ExpectationsWereMet(); err != nil {
@@ -53,25 +110,28 @@ func TestCreate(t *testing.T) {
 	}
 
 	// Redis returned error
-	mock.ExpectSet(hashedNickname, data, time.Hour*8).SetErr(domain.ErrTest)
-	sessionID, err = storage.Create(&createSession)
+	mock.ExpectTxPipeline()
+	mock.ExpectSet("session:"+id, []byte(data), time.Minute*30).SetErr(domain.ErrTest)
 
+	session, err = storage.Create(&createSession)
 	if err == nil {
 		t.Error("expected error, got nil")
 	}
 
-	if sessionID != "" {
-		t.Errorf("expected empty string, got: %s", sessionID)
+	if session != nil {
+		t.Errorf("expected nil, got: %+v", session)
 	}
 
 	if err = mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 	}
 
-	// GetHash returned error
-	storage = NewSessionStorage(ctx, client, badHasher)
-	_, err = storage.Create(&createSession)
+	// Token generation returned error
+	storage.token = func() (string, error) {
+		return "", domain.ErrTest
+	}
 
+	_, err = storage.Create(&createSession)
 	if err == nil {
 		t.Error("expected error, got nil")
 	}
@@ -81,38 +141,248 @@ func TestCreate(t *testing.T) {
 	}
 }
 
+func TestNewSessionStorageDefaults(t *testing.T) {
+	storage := NewSessionStorage(context.Background(), nil, 0, 0)
+	if storage.idleTimeout != DefaultSessionIdleTimeout || storage.maxLifetime != DefaultSessionMaxLifetime {
+		t.Errorf("expected default timeouts, got: %s, %s", storage.idleTimeout, storage.maxLifetime)
+	}
+
+	storage = NewSessionStorage(context.Background(), nil, time.Hour*2, time.Hour)
+	if storage.idleTimeout != time.Hour {
+		t.Errorf("expected idle timeout capped by max lifetime, got: %s", storage.idleTimeout)
+	}
+}
+
 func TestGet(t *testing.T) {
-	ctx := context.Background()
 	client, mock := redismock.NewClientMock()
 	defer client.Close()
 
-	hasher := hasher.NewHasher([]byte("session"))
-	storage := NewSessionStorage(ctx, client, hasher)
+	storage := newTestSessionStorage(client)
+
+	id := sessionID("token")
+	session := domain.Session{
+		ID:         id,
+		Username:   "user",
+		IsAdmin:    true,
+		CreatedAt:  sessionCreatedAt.Add(-time.Hour),
+		LastSeenAt: sessionCreatedAt.Add(-time.Minute),
+	}
+	renewed := session
+	renewed.LastSeenAt = sessionCreatedAt
+
+	getSession := domain.GetSession{
+		Token: "token",
+	}
+
+	// OK. The session is renewed for the idle timeout
+	mock.ExpectGet("session:" + id).SetVal(sessionData(t, session))
+	mock.ExpectTxPipeline()
+	mock.ExpectSetArgs("session:"+id, []byte(sessionData(t, renewed)), redis.SetArgs{Mode: "XX", TTL: time.Minute * 30}).SetVal("OK")
+	mock.ExpectSAdd("user_sessions:user", id).SetVal(0)
+	mock.ExpectExpire("user_sessions:user", time.Hour*8).SetVal(true)
+	mock.ExpectTxPipelineExec()
 
-	hashedNickname, err := hasher.GetHash("user")
+	active, err := storage.Get(&getSession)
 	if err != nil {
 		t.Fatalf("unexpected error: %s", err)
 	}
 
-	expectedUser := &domain.User{
-		Username: "user",
-		IsAdmin:  false,
+	expected := &domain.ActiveSession{
+		User:      &domain.User{Username: "user", IsAdmin: true},
+		Token:     "token",
+		ExpiresAt: sessionCreatedAt.Add(time.Minute * 30),
+	}
+	if !reflect.DeepEqual(active, expected) {
+		t.Errorf("expected: %+v, got: %+v", expected, active)
 	}
 
-	getSession := domain.GetSession{
-		Username: hashedNickname,
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
 	}
 
-	// OK
-	mock.ExpectGet(hashedNickname).SetVal(`{"username": "user", "is_admin": "false"}`)
-	user, err := storage.Get(&getSession)
+	// OK. Renewal stops at the max lifetime
+	old := session
+	old.CreatedAt = sessionCreatedAt.Add(-time.Hour*8 + time.Minute*10)
+	oldRenewed := old
+	oldRenewed.LastSeenAt = sessionCreatedAt
+
+	mock.ExpectGet("session:" + id).SetVal(sessionData(t, old))
+	mock.ExpectTxPipeline()
+	mock.ExpectSetArgs("session:"+id, []byte(sessionData(t, oldRenewed)), redis.SetArgs{Mode: "XX", TTL: time.Minute * 10}).SetVal("OK")
+	mock.ExpectSAdd("user_sessions:user", id).SetVal(0)
+	mock.ExpectExpire("user_sessions:user", time.Hour*8).SetVal(true)
+	mock.ExpectTxPipelineExec()
 
+	active, err = storage.Get(&getSession)
 	if err != nil {
 		t.Fatalf("unexpected error: %s", err)
 	}
 
-	if !reflect.DeepEqual(user, expectedUser) {
-		t.Errorf("expected: %v, got: %v", expectedUser, user)
+	if !active.ExpiresAt.Equal(sessionCreatedAt.Add(time.Minute * 10)) {
+		t.Errorf("expected expiry at the max lifetime, got: %s", active.ExpiresAt)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// Session outlived the max lifetime
+	old.CreatedAt = sessionCreatedAt.Add(-time.Hour * 8)
+
+	mock.ExpectGet("session:" + id).SetVal(sessionData(t, old))
+	mock.ExpectTxPipeline()
+	mock.ExpectDel("session:" + id).SetVal(1)
+	mock.ExpectSRem("user_sessions:user", id).SetVal(1)
+	mock.ExpectTxPipelineExec()
+
+	active, err = storage.Get(&getSession)
+	if !errors.Is(err, domain.ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized, got: %v", err)
+	}
+
+	if active != nil {
+		t.Errorf("expected nil, got: %+v", active)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// OK. A session marked for rotation moves to a new token
+	marked, err := json.Marshal(sessionRecord{Session: session, Rotate: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	storage.token = func() (string, error) {
+		return "rotated", nil
+	}
+	rotatedID := sessionID("rotated")
+	rotated := renewed
+	rotated.ID = rotatedID
+
+	mock.ExpectGet("session:" + id).SetVal(string(marked))
+	mock.ExpectWatch("session:" + id)
+	mock.ExpectGet("session:" + id).SetVal(string(marked))
+	mock.ExpectTxPipeline()
+	mock.ExpectRename("session:"+id, "session:"+rotatedID).SetVal("OK")
+	mock.ExpectSRem("user_sessions:user", id).SetVal(1)
+	mock.ExpectSet("session_alias:"+id, rotatedID, time.Second*30).SetVal("OK")
+	mock.ExpectSetArgs("session:"+rotatedID, []byte(sessionData(t, rotated)), redis.SetArgs{Mode: "XX", TTL: time.Minute * 30}).SetVal("OK")
+	mock.ExpectSAdd("user_sessions:user", rotatedID).SetVal(1)
+	mock.ExpectExpire("user_sessions:user", time.Hour*8).SetVal(true)
+	mock.ExpectTxPipelineExec()
+
+	active, err = storage.Get(&getSession)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if active.Token != "rotated" {
+		t.Errorf("expected: rotated, got: %s", active.Token)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// OK. A request that lost the race to rotate resolves the alias and
+	// leaves the cookie as it is
+	aliased := &domain.ActiveSession{
+		User:      &domain.User{Username: "user", IsAdmin: true},
+		ExpiresAt: sessionCreatedAt.Add(time.Minute * 30),
+	}
+
+	mock.ExpectGet("session:" + id).SetVal(string(marked))
+	mock.ExpectWatch("session:" + id)
+	mock.ExpectGet("session:" + id).RedisNil()
+	mock.ExpectGet("session_alias:" + id).SetVal(rotatedID)
+	mock.ExpectGet("session:" + rotatedID).SetVal(sessionData(t, rotated))
+
+	active, err = storage.Get(&getSession)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if !reflect.DeepEqual(active, aliased) {
+		t.Errorf("expected: %+v, got: %+v", aliased, active)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// OK. So does one whose transaction the winner aborted
+	mock.ExpectGet("session:" + id).SetVal(string(marked))
+	mock.ExpectWatch("session:" + id)
+	mock.ExpectGet("session:" + id).SetVal(string(marked))
+	mock.ExpectTxPipeline()
+	mock.ExpectRename("session:"+id, "session:"+rotatedID).SetVal("OK")
+	mock.ExpectSRem("user_sessions:user", id).SetVal(1)
+	mock.ExpectSet("session_alias:"+id, rotatedID, time.Second*30).SetVal("OK")
+	mock.ExpectSetArgs("session:"+rotatedID, []byte(sessionData(t, rotated)), redis.SetArgs{Mode: "XX", TTL: time.Minute * 30}).SetVal("OK")
+	mock.ExpectSAdd("user_sessions:user", rotatedID).SetVal(1)
+	mock.ExpectExpire("user_sessions:user", time.Hour*8).SetVal(true)
+	mock.ExpectTxPipelineExec().SetErr(redis.TxFailedErr)
+	mock.ExpectGet("session_alias:" + id).SetVal(rotatedID)
+	mock.ExpectGet("session:" + rotatedID).SetVal(sessionData(t, rotated))
+
+	active, err = storage.Get(&getSession)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if !reflect.DeepEqual(active, aliased) {
+		t.Errorf("expected: %+v, got: %+v", aliased, active)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// OK. The old token keeps working within the grace period
+	mock.ExpectGet("session:" + id).RedisNil()
+	mock.ExpectGet("session_alias:" + id).SetVal(rotatedID)
+	mock.ExpectGet("session:" + rotatedID).SetVal(sessionData(t, rotated))
+
+	active, err = storage.Get(&getSession)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if !reflect.DeepEqual(active, aliased) {
+		t.Errorf("expected: %+v, got: %+v", aliased, active)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// Rotated session revoked meanwhile
+	mock.ExpectGet("session:" + id).RedisNil()
+	mock.ExpectGet("session_alias:" + id).SetVal(rotatedID)
+	mock.ExpectGet("session:" + rotatedID).RedisNil()
+
+	if _, err = storage.Get(&getSession); !errors.Is(err, domain.ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized, got: %v", err)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// Session revoked meanwhile isn't written back
+	storage.token = func() (string, error) {
+		return "token", nil
+	}
+
+	mock.ExpectGet("session:" + id).SetVal(sessionData(t, session))
+	mock.ExpectTxPipeline()
+	mock.ExpectSetArgs("session:"+id, []byte(sessionData(t, renewed)), redis.SetArgs{Mode: "XX", TTL: time.Minute * 30}).RedisNil()
+
+	active, err = storage.Get(&getSession)
+	if !errors.Is(err, domain.ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized, got: %v", err)
 	}
 
 	if err = mock.ExpectationsWereMet(); err != nil {
@@ -120,15 +390,32 @@ func TestGet(t *testing.T) {
 	}
 
 	// Redis returned error
-	mock.ExpectGet(hashedNickname).SetErr(domain.ErrTest)
-	user, err = storage.Get(&getSession)
+	mock.ExpectGet("session:" + id).SetErr(domain.ErrTest)
+	active, err = storage.Get(&getSession)
 
 	if err == nil {
 		t.Error("expected error, got nil")
 	}
 
-	if user != nil {
-		t.Errorf("expected nil, got: %v", user)
+	if active != nil {
+		t.Errorf("expected nil, got: %v", active)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// Session doesn't exist
+	mock.ExpectGet("session:" + id).RedisNil()
+	mock.ExpectGet("session_alias:" + id).RedisNil()
+	active, err = storage.Get(&getSession)
+
+	if !errors.Is(err, domain.ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized, got: %v", err)
+	}
+
+	if active != nil {
+		t.Errorf("expected nil, got: %v", active)
 	}
 
 	if err = mock.ExpectationsWereMet(); err != nil {
@@ -136,15 +423,269 @@ func TestGet(t *testing.T) {
 	}
 
 	// Redis returned incorrect json
-	mock.ExpectGet(hashedNickname).SetVal(`incorrect json`)
-	user, err = storage.Get(&getSession)
+	mock.ExpectGet("session:" + id).SetVal(`incorrect json`)
+	active, err = storage.Get(&getSession)
 
 	if err == nil {
 		t.Error("expected error, got nil")
 	}
 
-	if user != nil {
-		t.Errorf("expected nil, got: %v", user)
+	if active != nil {
+		t.Errorf("expected nil, got: %v", active)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestSetAdmin(t *testing.T) {
+	client, mock := redismock.NewClientMock()
+	defer client.Close()
+
+	storage := newTestSessionStorage(client)
+
+	session := domain.Session{ID: "abc", Username: "user", CreatedAt: sessionCreatedAt}
+	promoted := session
+	promoted.IsAdmin = true
+
+	marked, err := json.Marshal(sessionRecord{Session: promoted, Rotate: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// OK. Sessions are marked for rotation, expired ones dropped
+	mock.ExpectSMembers("user_sessions:user").SetVal([]string{"abc", "expired"})
+	mock.ExpectMGet("session:abc", "session:expired").SetVal([]interface{}{sessionData(t, session), nil})
+	mock.ExpectTxPipeline()
+	mock.ExpectSetArgs("session:abc", marked, redis.SetArgs{Mode: "XX", KeepTTL: true}).SetVal("OK")
+	mock.ExpectSRem("user_sessions:user", "expired").SetVal(1)
+	mock.ExpectTxPipelineExec()
+
+	if err = storage.SetAdmin(&domain.SetAdmin{Username: "user", IsAdmin: true}); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// OK. No sessions
+	mock.ExpectSMembers("user_sessions:user").SetVal([]string{})
+
+	if err = storage.SetAdmin(&domain.SetAdmin{Username: "user", IsAdmin: true}); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	// Redis returned error
+	mock.ExpectSMembers("user_sessions:user").SetErr(domain.ErrTest)
+
+	if err = storage.SetAdmin(&domain.SetAdmin{Username: "user", IsAdmin: true}); err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestRevokeSessions(t *testing.T) {
+	client, mock := redismock.NewClientMock()
+	defer client.Close()
+
+	storage := newTestSessionStorage(client)
+
+	// OK. Every session of the user ends
+	mock.ExpectSMembers("user_sessions:user").SetVal([]string{"abc", "def"})
+	mock.ExpectTxPipeline()
+	mock.ExpectDel("session:abc", "session:def").SetVal(2)
+	mock.ExpectSRem("user_sessions:user", "abc", "def").SetVal(2)
+	mock.ExpectTxPipelineExec()
+
+	if err := storage.Revoke(&domain.RevokeUser{Username: "user"}); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// OK. No sessions
+	mock.ExpectSMembers("user_sessions:user").SetVal([]string{})
+
+	if err := storage.Revoke(&domain.RevokeUser{Username: "user"}); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	// Redis returned error
+	mock.ExpectSMembers("user_sessions:user").SetErr(domain.ErrTest)
+
+	if err := storage.Revoke(&domain.RevokeUser{Username: "user"}); err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	mock.ExpectSMembers("user_sessions:user").SetVal([]string{"abc"})
+	mock.ExpectTxPipeline()
+	mock.ExpectDel("session:abc").SetErr(domain.ErrTest)
+
+	if err := storage.Revoke(&domain.RevokeUser{Username: "user"}); err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestListSessions(t *testing.T) {
+	client, mock := redismock.NewClientMock()
+	defer client.Close()
+
+	storage := newTestSessionStorage(client)
+
+	current := domain.Session{
+		ID:        sessionID("token"),
+		Username:  "user",
+		CreatedAt: sessionCreatedAt.Add(-time.Hour),
+		IP:        "192.0.2.1",
+		UserAgent: "curl/8.0",
+	}
+	latest := domain.Session{
+		ID:        "latest",
+		Username:  "user",
+		CreatedAt: sessionCreatedAt,
+		IP:        "192.0.2.2",
+		UserAgent: "Mozilla/5.0",
+	}
+
+	listSessions := domain.ListSessions{
+		Username: "user",
+		Token:    "token",
+	}
+
+	// OK. Expired sessions are dropped from the index
+	mock.ExpectSMembers("user_sessions:user").SetVal([]string{current.ID, "expired", "latest"})
+	mock.ExpectMGet("session:"+current.ID, "session:expired", "session:latest").
+		SetVal([]interface{}{sessionData(t, current), nil, sessionData(t, latest)})
+	mock.ExpectSRem("user_sessions:user", "expired").SetVal(1)
+
+	list, err := storage.List(&listSessions)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	current.Current = true
+	expected := &domain.SessionList{Sessions: []domain.Session{latest, current}}
+	if !reflect.DeepEqual(list, expected) {
+		t.Errorf("expected: %+v, got: %+v", expected, list)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// OK. No sessions
+	mock.ExpectSMembers("user_sessions:user").SetVal([]string{})
+
+	list, err = storage.List(&listSessions)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(list.Sessions) != 0 {
+		t.Errorf("expected no sessions, got: %+v", list.Sessions)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// Redis returned error
+	mock.ExpectSMembers("user_sessions:user").SetErr(domain.ErrTest)
+
+	_, err = storage.List(&listSessions)
+	if err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	mock.ExpectSMembers("user_sessions:user").SetVal([]string{"latest"})
+	mock.ExpectMGet("session:latest").SetErr(domain.ErrTest)
+
+	_, err = storage.List(&listSessions)
+	if err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestDeleteSession(t *testing.T) {
+	client, mock := redismock.NewClientMock()
+	defer client.Close()
+
+	storage := newTestSessionStorage(client)
+
+	id := sessionID("token")
+	data := sessionData(t, domain.Session{ID: id, Username: "user"})
+
+	// OK. Logout by token
+	mock.ExpectGet("session:" + id).SetVal(data)
+	mock.ExpectTxPipeline()
+	mock.ExpectDel("session:" + id).SetVal(1)
+	mock.ExpectSRem("user_sessions:user", id).SetVal(1)
+	mock.ExpectTxPipelineExec()
+
+	if err := storage.Delete(&domain.DeleteSession{Token: "token"}); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// OK. Revoke by ID
+	mock.ExpectGet("session:" + id).SetVal(data)
+	mock.ExpectTxPipeline()
+	mock.ExpectDel("session:" + id).SetVal(1)
+	mock.ExpectSRem("user_sessions:user", id).SetVal(1)
+	mock.ExpectTxPipelineExec()
+
+	if err := storage.Delete(&domain.DeleteSession{ID: id, Username: "user"}); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// Session of another user
+	mock.ExpectGet("session:" + id).SetVal(data)
+
+	err := storage.Delete(&domain.DeleteSession{ID: id, Username: "other"})
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got: %v", err)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	// Session doesn't exist
+	mock.ExpectGet("session:" + id).RedisNil()
+
+	err = storage.Delete(&domain.DeleteSession{ID: id})
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got: %v", err)
+	}
+
+	// Redis returned error
+	mock.ExpectGet("session:" + id).SetErr(domain.ErrTest)
+
+	err = storage.Delete(&domain.DeleteSession{ID: id})
+	if !errors.Is(err, domain.ErrTest) {
+		t.Errorf("expected ErrTest, got: %v", err)
 	}
 
 	if err = mock.ExpectationsWereMet(); err != nil {
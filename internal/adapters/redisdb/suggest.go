@@ -0,0 +1,60 @@
+package redisdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+	"github.com/redis/go-redis/v9"
+)
+
+type suggestCache struct {
+	ctx context.Context
+	db  *redis.Client
+	ttl time.Duration
+}
+
+// NewSuggestCache returns a cache of suggestions kept for ttl. A title or a
+// name changed meanwhile shows up once the entries of its prefixes expire.
+func NewSuggestCache(ctx context.Context, db *redis.Client, ttl time.Duration) *suggestCache {
+	return &suggestCache{
+		ctx: ctx,
+		db:  db,
+		ttl: ttl,
+	}
+}
+
+func suggestKey(dto *domain.Suggest) string {
+	return fmt.Sprintf("suggest:%s:%d:%s", strings.Join(dto.Types, ","), dto.Limit, strings.ToLower(dto.Prefix))
+}
+
+// Get returns the cached suggestions, or nil when there are none.
+func (c *suggestCache) Get(dto *domain.Suggest) (*domain.SuggestList, error) {
+	value, err := c.db.Get(c.ctx, suggestKey(dto)).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	list := domain.SuggestList{}
+	if err = json.Unmarshal([]byte(value), &list); err != nil {
+		return nil, err
+	}
+
+	return &list, nil
+}
+
+func (c *suggestCache) Set(dto *domain.Suggest, list *domain.SuggestList) error {
+	data, err := json.Marshal(list)
+	if err != nil {
+		return err
+	}
+
+	return c.db.Set(c.ctx, suggestKey(dto), data, c.ttl).Err()
+}
@@ -0,0 +1,63 @@
+package redisdb
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+	"github.com/go-redis/redismock/v9"
+)
+
+func TestSuggestCache(t *testing.T) {
+	ctx := context.Background()
+	client, mock := redismock.NewClientMock()
+	defer client.Close()
+
+	cache := NewSuggestCache(ctx, client, 30*time.Second)
+
+	dto := &domain.Suggest{Prefix: "Ca", Types: []string{"movie", "actor"}, Limit: 10}
+	key := "suggest:movie,actor:10:ca"
+	list := &domain.SuggestList{Suggestions: []domain.Suggestion{{Type: "movie", ID: 3, Text: "Cars"}}}
+
+	data, err := json.Marshal(list)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// OK. Set
+	mock.ExpectSet(key, data, 30*time.Second).SetVal("OK")
+	if err = cache.Set(dto, list); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	// OK. Hit
+	mock.ExpectGet(key).SetVal(string(data))
+	got, err := cache.Get(dto)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	if !reflect.DeepEqual(list, got) {
+		t.Errorf("expected: %v, got: %v", list, got)
+	}
+
+	// OK. Miss
+	mock.ExpectGet(key).RedisNil()
+	got, err = cache.Get(dto)
+	if err != nil || got != nil {
+		t.Errorf("expected a miss, got: %v, %v", got, err)
+	}
+
+	// Redis returned error
+	mock.ExpectGet(key).SetErr(domain.ErrTest)
+	if _, err = cache.Get(dto); err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -1,6 +1,9 @@
 package config
 
 import (
+	"reflect"
+	"time"
+
 	"github.com/spf13/viper"
 )
 
@@ -17,12 +20,65 @@ type config struct {
 
 	RedisHost string `mapstructure:"R_HOST"`
 	RedisPort string `mapstructure:"R_PORT"`
+
+	// PasswordHash is the algorithm of new password hashes, argon2id or
+	// bcrypt, argon2id when it is left out.
+	PasswordHash string `mapstructure:"PASSWORD_HASH"`
+
+	// SessionIdleTimeout ends a session unused for this long, 30m when it is
+	// left out, and SessionMaxLifetime ends it this long after the login
+	// whatever the use, 8h when it is left out.
+	SessionIdleTimeout time.Duration `mapstructure:"SESSION_IDLE_TIMEOUT"`
+	SessionMaxLifetime time.Duration `mapstructure:"SESSION_MAX_LIFETIME"`
+
+	// The attributes of the session cookie. CookieSameSite is lax, strict or
+	// none, the latter only with CookieSecure. CookieHTTPOnly is on unless
+	// it is turned off explicitly.
+	CookieDomain   string `mapstructure:"COOKIE_DOMAIN"`
+	CookiePath     string `mapstructure:"COOKIE_PATH"`
+	CookieSecure   bool   `mapstructure:"COOKIE_SECURE"`
+	CookieHTTPOnly bool   `mapstructure:"COOKIE_HTTP_ONLY"`
+	CookieSameSite string `mapstructure:"COOKIE_SAME_SITE"`
+
+	// JWTKeys are the keys of the access tokens, comma-separated kid:alg:base64
+	// with alg HS256 and a secret of at least 32 bytes or EdDSA and an Ed25519
+	// seed. The first one signs, all of them verify. Being a secret, it comes
+	// from the environment rather than the committed .env.
+	JWTKeys string `mapstructure:"JWT_KEYS"`
+	// AccessTokenTTL is 15m and RefreshTokenTTL 720h when they are left out.
+	// An access token keeps the privileges it was issued with until it
+	// expires, so a demoted or deleted user stays an admin for up to
+	// AccessTokenTTL on bearer requests.
+	AccessTokenTTL  time.Duration `mapstructure:"ACCESS_TOKEN_TTL"`
+	RefreshTokenTTL time.Duration `mapstructure:"REFRESH_TOKEN_TTL"`
+
+	// SearchSimilarity is the default threshold of fuzzy movie searches.
+	SearchSimilarity float64 `mapstructure:"SEARCH_SIMILARITY"`
+	// SuggestCacheTTL keeps suggestions in Redis for this long, such as 30s.
+	// They aren't cached when it is left out.
+	SuggestCacheTTL time.Duration `mapstructure:"SUGGEST_CACHE_TTL"`
+
+	// ImportMaxSize is the largest file POST /import accepts in MB, 64 when
+	// it is left out. The file is held in memory while it is imported.
+	ImportMaxSize int64 `mapstructure:"IMPORT_MAX_SIZE"`
 }
 
+// NewConfig reads the config file, where a variable of the environment
+// overrides the setting of the same name.
 func NewConfig(path, filename string) (*config, error) {
 	cfg := config{}
 	viper.AddConfigPath(path)
 	viper.SetConfigFile(filename)
+	viper.SetDefault("COOKIE_HTTP_ONLY", true)
+
+	// Unmarshal only sees the keys viper knows of, so those missing from
+	// the file are bound to the environment one by one.
+	fields := reflect.TypeOf(cfg)
+	for i := 0; i < fields.NumField(); i++ {
+		if err := viper.BindEnv(fields.Field(i).Tag.Get("mapstructure")); err != nil {
+			return nil, err
+		}
+	}
 
 	if err := viper.ReadInConfig(); err != nil {
 		return nil, err
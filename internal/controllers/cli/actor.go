@@ -0,0 +1,192 @@
+package cli
+
+import (
+	"flag"
+	"strconv"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+)
+
+const actorUsage = `usage: filmctl actor list [-limit 20] [-after cursor | -before cursor]
+       filmctl actor show <id>
+       filmctl actor add -name <name> -gender Male|Female [-birth YYYY-MM-DD]
+       filmctl actor edit <id> [-name <name>] [-gender Male|Female] [-birth YYYY-MM-DD]
+       filmctl actor delete <id>`
+
+func (c *cli) actor(args []string) error {
+	if len(args) == 0 {
+		return usageError(actorUsage)
+	}
+
+	fs := newFlagSet("actor " + args[0])
+	switch args[0] {
+	case "list":
+		page := pageFlags(fs)
+		if _, err := parseArgs(fs, args[1:], 0, actorUsage); err != nil {
+			return err
+		}
+		if err := checkPage(*page); err != nil {
+			return err
+		}
+		return c.actorList(*page)
+	case "show":
+		rest, err := parseArgs(fs, args[1:], 1, actorUsage)
+		if err != nil {
+			return err
+		}
+		return c.actorShow(rest[0])
+	case "add", "edit":
+		fs.String("name", "", "name")
+		fs.String("gender", "", "gender, Male or Female")
+		fs.String("birth", "", "date of birth")
+
+		positional := 0
+		if args[0] == "edit" {
+			positional = 1
+		}
+
+		rest, err := parseArgs(fs, args[1:], positional, actorUsage)
+		if err != nil {
+			return err
+		}
+
+		if args[0] == "add" {
+			return c.actorAdd(fs)
+		}
+		return c.actorEdit(rest[0], fs)
+	case "delete":
+		rest, err := parseArgs(fs, args[1:], 1, actorUsage)
+		if err != nil {
+			return err
+		}
+		return c.actorDelete(rest[0])
+	}
+
+	return usageError(actorUsage)
+}
+
+func (c *cli) actorList(page domain.Page) error {
+	list, err := c.actors.GetList(&domain.GetActorList{Page: page})
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]string, 0, len(list.Actors))
+	for _, actor := range list.Actors {
+		rows = append(rows, []string{
+			strconv.FormatInt(actor.ID, 10),
+			actor.Name,
+			actor.Gender,
+			formatDate(actor.DateBirth),
+			strconv.Itoa(len(actor.Movies)),
+		})
+	}
+
+	if err = c.print(list, []string{"ID", "NAME", "GENDER", "DATE OF BIRTH", "MOVIES"}, rows); err != nil {
+		return err
+	}
+
+	return c.printCursors(list.Cursors)
+}
+
+func (c *cli) actorShow(value string) error {
+	id, err := parseID(value)
+	if err != nil {
+		return err
+	}
+
+	actor, err := c.actors.Get(&domain.GetActor{ID: id})
+	if err != nil {
+		return err
+	}
+
+	return c.print(actor, []string{"ID", "NAME", "GENDER", "DATE OF BIRTH"}, [][]string{{
+		strconv.FormatInt(actor.ID, 10),
+		actor.Name,
+		actor.Gender,
+		formatDate(actor.DateBirth),
+	}})
+}
+
+func (c *cli) actorAdd(fs *flag.FlagSet) error {
+	patch, err := actorPatch(fs)
+	if err != nil {
+		return err
+	}
+
+	dto := domain.CreateActor{}
+	if patch.Name != nil {
+		dto.Name = *patch.Name
+	}
+	if patch.Gender != nil {
+		dto.Gender = *patch.Gender
+	}
+	if patch.DateBirth != nil {
+		dto.DateBirth = *patch.DateBirth
+	}
+
+	if err = c.actors.Create(&dto); err != nil {
+		return err
+	}
+
+	return c.done("added actor %q", dto.Name)
+}
+
+// actorEdit changes only the fields given on the command line, whatever the
+// current version of the actor is.
+func (c *cli) actorEdit(value string, fs *flag.FlagSet) error {
+	id, err := parseID(value)
+	if err != nil {
+		return err
+	}
+
+	patch, err := actorPatch(fs)
+	if err != nil {
+		return err
+	}
+	patch.ID = id
+
+	if err = c.actors.Patch(patch); err != nil {
+		return err
+	}
+
+	return c.done("edited actor %d", id)
+}
+
+func (c *cli) actorDelete(value string) error {
+	id, err := parseID(value)
+	if err != nil {
+		return err
+	}
+
+	if err = c.actors.Delete(&domain.DeleteActor{ID: id}); err != nil {
+		return err
+	}
+
+	return c.done("deleted actor %d", id)
+}
+
+// actorPatch collects the actor fields set on the command line.
+func actorPatch(fs *flag.FlagSet) (*domain.PatchActor, error) {
+	patch := domain.PatchActor{}
+
+	if isSet(fs, "name") {
+		name := fs.Lookup("name").Value.String()
+		patch.Name = &name
+	}
+
+	if isSet(fs, "gender") {
+		gender := fs.Lookup("gender").Value.String()
+		patch.Gender = &gender
+	}
+
+	if isSet(fs, "birth") {
+		date, err := parseDate(fs.Lookup("birth").Value.String())
+		if err != nil {
+			return nil, err
+		}
+		patch.DateBirth = &date
+	}
+
+	return &patch, nil
+}
@@ -0,0 +1,276 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+)
+
+// ErrUsage is returned for a command line that doesn't make sense, the
+// message tells how the command is used.
+var ErrUsage = errors.New("usage")
+
+const dateLayout = "2006-01-02"
+
+const usage = `usage: filmctl [-o table|json] <command> [arguments]
+
+commands:
+  user     list | create | promote | demote | passwd | delete
+  movie    list | show | add | edit | delete
+  actor    list | show | add | edit | delete
+  import   [-format csv|json|ndjson] [-dry-run] [-batch 100] <file>
+  export   [-format ndjson|json|csv] <file>
+  imdb     [-types movie] [-from year] [-to year] <dir>
+  migrate  up | down [steps] | status`
+
+type cli struct {
+	in       *bufio.Reader
+	out      io.Writer
+	format   string
+	users    UserAdminService
+	movies   MovieService
+	actors   ActorService
+	imports  ImportService
+	exports  ExportService
+	imdb     IMDbService
+	migrator Migrator
+}
+
+func NewCLI(in io.Reader, out io.Writer, users UserAdminService, movies MovieService, actors ActorService,
+	imports ImportService, exports ExportService, imdb IMDbService, migrator Migrator) *cli {
+	return &cli{
+		in:       bufio.NewReader(in),
+		out:      out,
+		format:   "table",
+		users:    users,
+		movies:   movies,
+		actors:   actors,
+		imports:  imports,
+		exports:  exports,
+		imdb:     imdb,
+		migrator: migrator,
+	}
+}
+
+// Run runs the command given by args, without the program name.
+func (c *cli) Run(args []string) error {
+	fs := newFlagSet("filmctl")
+	format := fs.String("o", "table", "output format, table or json")
+	if err := fs.Parse(args); err != nil {
+		return usageError(usage)
+	}
+
+	if *format != "table" && *format != "json" {
+		return usageError(usage)
+	}
+	c.format = *format
+
+	args = fs.Args()
+	if len(args) == 0 {
+		return usageError(usage)
+	}
+
+	switch args[0] {
+	case "user":
+		return c.user(args[1:])
+	case "movie":
+		return c.movie(args[1:])
+	case "actor":
+		return c.actor(args[1:])
+	case "import":
+		return c.importFile(args[1:])
+	case "export":
+		return c.exportFile(args[1:])
+	case "imdb":
+		return c.importIMDb(args[1:])
+	case "migrate":
+		return c.migrate(args[1:])
+	}
+
+	return usageError(usage)
+}
+
+func usageError(text string) error {
+	return fmt.Errorf("%w: %s", ErrUsage, strings.TrimPrefix(text, "usage: "))
+}
+
+// newFlagSet returns a flag set that leaves reporting errors to the caller.
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return fs
+}
+
+// parseArgs parses the flags of a command, which may follow its positional
+// arguments, and checks the number of the latter.
+func parseArgs(fs *flag.FlagSet, args []string, positional int, text string) ([]string, error) {
+	var rest []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, usageError(text)
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		rest = append(rest, args[0])
+		args = args[1:]
+	}
+
+	if len(rest) != positional {
+		return nil, usageError(text)
+	}
+
+	return rest, nil
+}
+
+// isSet reports whether the flag was given on the command line.
+func isSet(fs *flag.FlagSet, name string) bool {
+	set := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+
+	return set
+}
+
+// pageFlags defines the flags selecting a page of a listing.
+func pageFlags(fs *flag.FlagSet) *domain.Page {
+	page := domain.Page{}
+	fs.IntVar(&page.Limit, "limit", 20, "page size")
+	fs.StringVar(&page.After, "after", "", "cursor of the page to show the page after")
+	fs.StringVar(&page.Before, "before", "", "cursor of the page to show the page before")
+
+	return &page
+}
+
+func checkPage(page domain.Page) error {
+	if page.Limit <= 0 {
+		return fmt.Errorf("invalid limit %d", page.Limit)
+	}
+
+	if page.After != "" && page.Before != "" {
+		return errors.New("-after can't be combined with -before")
+	}
+
+	return nil
+}
+
+// printCursors tells the cursors of the neighbouring pages, in the table
+// format only as JSON output carries them already.
+func (c *cli) printCursors(cursors domain.Cursors) error {
+	if c.format == "json" || cursors.Prev == "" && cursors.Next == "" {
+		return nil
+	}
+
+	fmt.Fprintln(c.out)
+	if cursors.Prev != "" {
+		fmt.Fprintf(c.out, "previous page: -before %s\n", cursors.Prev)
+	}
+	if cursors.Next != "" {
+		fmt.Fprintf(c.out, "next page: -after %s\n", cursors.Next)
+	}
+
+	return nil
+}
+
+func parseID(value string) (int64, error) {
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid ID %q", value)
+	}
+
+	return id, nil
+}
+
+func parseIDs(value string) ([]int64, error) {
+	ids := make([]int64, 0)
+	if value == "" {
+		return ids, nil
+	}
+
+	for _, part := range strings.Split(value, ",") {
+		id, err := parseID(strings.TrimSpace(part))
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+func parseDate(value string) (time.Time, error) {
+	date, err := time.Parse(dateLayout, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q, expected YYYY-MM-DD", value)
+	}
+
+	return date, nil
+}
+
+func formatDate(date time.Time) string {
+	if date.IsZero() {
+		return ""
+	}
+
+	return date.Format(dateLayout)
+}
+
+// print writes value as JSON or, in the table format, the rows under headers.
+func (c *cli) print(value interface{}, headers []string, rows [][]string) error {
+	if c.format == "json" {
+		out, err := json.MarshalIndent(value, "", "\t")
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintln(c.out, string(out))
+		return err
+	}
+
+	w := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(headers, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+
+	return w.Flush()
+}
+
+type message struct {
+	Message string `json:"message"`
+}
+
+// done reports a successful change.
+func (c *cli) done(format string, args ...interface{}) error {
+	text := fmt.Sprintf(format, args...)
+	if c.format == "json" {
+		return c.print(message{Message: text}, nil, nil)
+	}
+
+	_, err := fmt.Fprintln(c.out, text)
+	return err
+}
+
+// readPassword reads a password from the first line of the input, so that
+// it doesn't end up in the shell history.
+func (c *cli) readPassword() (string, error) {
+	line, err := c.in.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+
+	return strings.TrimRight(line, "\r\n"), nil
+}
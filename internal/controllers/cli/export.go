@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"io"
+	"os"
+
+	"github.com/akrovv/filmlibrary/pkg/catalog"
+)
+
+const exportUsage = `usage: filmctl export [-format ndjson|json|csv] <file>
+       (the format is taken from the file extension, NDJSON by default, - writes to stdout)`
+
+// exportFile writes the whole catalogue to a file, which is removed if the
+// export fails halfway.
+func (c *cli) exportFile(args []string) (err error) {
+	fs := newFlagSet("export")
+	format := fs.String("format", "", "file format")
+
+	rest, err := parseArgs(fs, args, 1, exportUsage)
+	if err != nil {
+		return err
+	}
+
+	name := rest[0]
+	if *format == "" {
+		*format = catalog.FormatOf("", name)
+	}
+	if *format == "" {
+		*format = catalog.NDJSON
+	}
+
+	var out io.Writer = c.out
+	if name != "-" {
+		var file *os.File
+		if file, err = os.Create(name); err != nil {
+			return err
+		}
+		defer func() {
+			if closeErr := file.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				_ = os.Remove(name)
+			}
+		}()
+		out = file
+	}
+
+	encoder, err := catalog.NewEncoder(out, *format)
+	if err != nil {
+		return err
+	}
+
+	if err = c.exports.Export(encoder); err != nil {
+		return err
+	}
+
+	return encoder.Close()
+}
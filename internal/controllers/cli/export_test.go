@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+	"github.com/akrovv/filmlibrary/internal/service/mocks"
+	"github.com/golang/mock/gomock"
+)
+
+func TestExport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	es := mocks.NewMockExportService(ctrl)
+	out := &bytes.Buffer{}
+	c := NewCLI(strings.NewReader(""), out, nil, nil, nil, nil, es, nil, nil)
+
+	export := func(w domain.ExportWriter) error {
+		if err := w.WriteManifest(&domain.ExportManifest{SchemaVersion: 4, Movies: 1}); err != nil {
+			return err
+		}
+		return w.WriteMovie(&domain.ExportMovie{ID: 1, Title: "Heat", Rating: 8})
+	}
+
+	// OK, NDJSON to stdout
+	es.EXPECT().Export(gomock.Any()).DoAndReturn(export)
+
+	if err := c.Run([]string{"export", "-"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 || lines[1] != `{"type":"movie","movie_id":1,"movie_title":"Heat","rating":8,"actors":[]}` {
+		t.Errorf("unexpected output: %q", out.String())
+	}
+
+	// CSV by the file extension
+	name := filepath.Join(t.TempDir(), "catalogue.csv")
+	es.EXPECT().Export(gomock.Any()).DoAndReturn(export)
+
+	if err := c.Run([]string{"export", name}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	data, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatalf("can't read export: %s", err)
+	}
+
+	if !strings.HasSuffix(string(data), "\n1,,Heat,,,8,,,,,\n") {
+		t.Errorf("unexpected file: %q", data)
+	}
+
+	// Failed export leaves no file behind
+	name = filepath.Join(t.TempDir(), "catalogue.json")
+	es.EXPECT().Export(gomock.Any()).Return(domain.ErrTest)
+
+	if err = c.Run([]string{"export", name}); !errors.Is(err, domain.ErrTest) {
+		t.Errorf("expected ErrTest, got: %v", err)
+	}
+
+	if _, err = os.Stat(name); !os.IsNotExist(err) {
+		t.Errorf("expected the file removed, got: %v", err)
+	}
+
+	// No file
+	if err = c.Run([]string{"export"}); !errors.Is(err, ErrUsage) {
+		t.Errorf("expected ErrUsage, got: %v", err)
+	}
+}
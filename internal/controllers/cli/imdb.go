@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/akrovv/filmlibrary/pkg/imdb"
+)
+
+const imdbUsage = `usage: filmctl imdb [-types movie,tvMovie] [-from year] [-to year] <dir>
+       (dir holds title.basics.tsv, title.principals.tsv and name.basics.tsv, gzipped or not)`
+
+// importIMDb loads the titles of an IMDb dataset that pass the filter with
+// their actors.
+func (c *cli) importIMDb(args []string) error {
+	fs := newFlagSet("imdb")
+	types := fs.String("types", "movie", "comma separated title types, empty for all")
+	from := fs.Int("from", 0, "earliest start year")
+	to := fs.Int("to", 0, "latest start year")
+
+	rest, err := parseArgs(fs, args, 1, imdbUsage)
+	if err != nil {
+		return err
+	}
+
+	if *from < 0 || *to < 0 || *to != 0 && *to < *from {
+		return fmt.Errorf("invalid years %d to %d", *from, *to)
+	}
+
+	filter := imdb.Filter{FromYear: *from, ToYear: *to}
+	for _, t := range strings.Split(*types, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			filter.Types = append(filter.Types, t)
+		}
+	}
+
+	dataset := imdb.NewDataset(rest[0], filter)
+	if err = dataset.Check(); err != nil {
+		return err
+	}
+
+	report, err := c.imdb.ImportIMDb(dataset)
+	if err != nil {
+		return err
+	}
+
+	itoa := func(n int64) string {
+		return strconv.FormatInt(n, 10)
+	}
+
+	return c.print(report, []string{"", "LOADED", "CREATED", "UPDATED"}, [][]string{
+		{"movies", itoa(report.Titles), itoa(report.MoviesCreated), itoa(report.MoviesUpdated)},
+		{"actors", itoa(report.Names), itoa(report.ActorsCreated), itoa(report.ActorsUpdated)},
+		{"cast", itoa(report.Principals), itoa(report.LinksCreated), ""},
+	})
+}
@@ -0,0 +1,141 @@
+package cli
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+	"github.com/akrovv/filmlibrary/internal/service/mocks"
+	"github.com/golang/mock/gomock"
+)
+
+const (
+	testTitles = "tconst\ttitleType\tprimaryTitle\toriginalTitle\tisAdult\tstartYear\tendYear\truntimeMinutes\tgenres\n" +
+		"tt0113277\tmovie\tHeat\tHeat\t0\t1995\t\\N\t170\tAction,Crime,Drama\n" +
+		"tt0122690\tmovie\tRonin\tRonin\t0\t1998\t\\N\t122\tAction,Crime,Thriller\n" +
+		"tt0106179\ttvSeries\tThe X Files\tThe X Files\t0\t1993\t2018\t44\tCrime,Drama,Mystery\n" +
+		"tt9999999\tmovie\tUntitled\tUntitled\t0\t\\N\t\\N\t\\N\t\\N\n"
+	testPrincipals = "tconst\tordering\tnconst\tcategory\tjob\tcharacters\n" +
+		"tt0113277\t1\tnm0000199\tactor\t\\N\t[\"Vincent Hanna\"]\n" +
+		"tt0113277\t2\tnm0000134\tactor\t\\N\t[\"Neil McCauley\"]\n" +
+		"tt0113277\t3\tnm0000520\tdirector\t\\N\t\\N\n" +
+		"tt0122690\t1\tnm0000134\tactor\t\\N\t[\"Sam\"]\n" +
+		"tt0122690\t2\tnm0000366\tactress\t\\N\t[\"Deirdre\"]\n" +
+		"tt0106179\t1\tnm0000096\tactress\t\\N\t[\"Dana Scully\"]\n"
+	testNames = "nconst\tprimaryName\tbirthYear\tdeathYear\tprimaryProfession\tknownForTitles\n" +
+		"nm0000096\tGillian Anderson\t1968\t\\N\tactress\ttt0106179\n" +
+		"nm0000134\tRobert De Niro\t1943\t\\N\tactor,producer\ttt0113277\n" +
+		"nm0000199\tAl Pacino\t1940\t\\N\tactor\ttt0113277\n" +
+		"nm0000366\tNatascha McElhone\t\\N\t\\N\tactress\ttt0122690\n" +
+		"nm0000520\tMichael Mann\t1943\t\\N\tdirector\ttt0113277\n"
+)
+
+func TestIMDb(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "title.basics.tsv"), []byte(testTitles), 0o600); err != nil {
+		t.Fatalf("can't write titles: %s", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "title.principals.tsv"), []byte(testPrincipals), 0o600); err != nil {
+		t.Fatalf("can't write principals: %s", err)
+	}
+
+	// Names are gzipped, as the dataset is published
+	names := &bytes.Buffer{}
+	gz := gzip.NewWriter(names)
+	gz.Write([]byte(testNames))
+	gz.Close()
+	if err := os.WriteFile(filepath.Join(dir, "name.basics.tsv.gz"), names.Bytes(), 0o600); err != nil {
+		t.Fatalf("can't write names: %s", err)
+	}
+
+	is := mocks.NewMockIMDbService(ctrl)
+	out := &bytes.Buffer{}
+	c := NewCLI(strings.NewReader(""), out, nil, nil, nil, nil, nil, is, nil)
+
+	// OK, movies from 1995 on with their actors only
+	var (
+		titles     []domain.IMDbTitle
+		principals []domain.IMDbPrincipal
+		people     []domain.IMDbName
+	)
+
+	is.EXPECT().ImportIMDb(gomock.Any()).DoAndReturn(func(source domain.IMDbSource) (*domain.IMDbReport, error) {
+		err := source.Titles(func(title *domain.IMDbTitle) error {
+			titles = append(titles, *title)
+			return nil
+		})
+		if err == nil {
+			err = source.Principals(func(principal *domain.IMDbPrincipal) error {
+				principals = append(principals, *principal)
+				return nil
+			})
+		}
+		if err == nil {
+			err = source.Names(func(name *domain.IMDbName) error {
+				people = append(people, *name)
+				return nil
+			})
+		}
+
+		return &domain.IMDbReport{Titles: 2, Principals: 4, Names: 3, MoviesCreated: 2, ActorsCreated: 3, LinksCreated: 4}, err
+	})
+
+	if err := c.Run([]string{"imdb", "-from", "1995", dir}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expectedTitles := []domain.IMDbTitle{
+		{TConst: "tt0113277", Title: "Heat", Year: 1995},
+		{TConst: "tt0122690", Title: "Ronin", Year: 1998},
+	}
+	if !reflect.DeepEqual(titles, expectedTitles) {
+		t.Errorf("expected: %+v, got: %+v", expectedTitles, titles)
+	}
+
+	expectedPrincipals := []domain.IMDbPrincipal{
+		{TConst: "tt0113277", NConst: "nm0000199", Gender: "Male"},
+		{TConst: "tt0113277", NConst: "nm0000134", Gender: "Male"},
+		{TConst: "tt0122690", NConst: "nm0000134", Gender: "Male"},
+		{TConst: "tt0122690", NConst: "nm0000366", Gender: "Female"},
+	}
+	if !reflect.DeepEqual(principals, expectedPrincipals) {
+		t.Errorf("expected: %+v, got: %+v", expectedPrincipals, principals)
+	}
+
+	expectedNames := []domain.IMDbName{
+		{NConst: "nm0000134", Name: "Robert De Niro", BirthYear: 1943},
+		{NConst: "nm0000199", Name: "Al Pacino", BirthYear: 1940},
+		{NConst: "nm0000366", Name: "Natascha McElhone"},
+	}
+	if !reflect.DeepEqual(people, expectedNames) {
+		t.Errorf("expected: %+v, got: %+v", expectedNames, people)
+	}
+
+	if !strings.Contains(out.String(), "movies  2") {
+		t.Errorf("unexpected output: %q", out.String())
+	}
+
+	// A file missing
+	if err := c.Run([]string{"imdb", t.TempDir()}); err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	// Invalid years
+	if err := c.Run([]string{"imdb", "-from", "2000", "-to", "1990", dir}); err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	// No directory
+	if err := c.Run([]string{"imdb"}); !errors.Is(err, ErrUsage) {
+		t.Errorf("expected ErrUsage, got: %v", err)
+	}
+}
@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+	"github.com/akrovv/filmlibrary/pkg/catalog"
+)
+
+const importUsage = `usage: filmctl import [-format csv|json|ndjson] [-dry-run] [-batch 100] <file>
+       (the format is taken from the file extension by default, - reads stdin)`
+
+// importFile imports movies from a file and reports every row. It fails if
+// any row did, so that a script notices.
+func (c *cli) importFile(args []string) error {
+	fs := newFlagSet("import")
+	format := fs.String("format", "", "file format")
+	dryRun := fs.Bool("dry-run", false, "roll everything back and only report")
+	batch := fs.Int("batch", 100, "rows per transaction")
+
+	rest, err := parseArgs(fs, args, 1, importUsage)
+	if err != nil {
+		return err
+	}
+
+	if *batch <= 0 {
+		return fmt.Errorf("invalid batch size %d", *batch)
+	}
+
+	name := rest[0]
+	if *format == "" {
+		*format = catalog.FormatOf("", name)
+	}
+
+	var in io.Reader = c.in
+	if name != "-" {
+		file, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		in = file
+	}
+
+	rows, err := catalog.DecodeMovies(in, *format)
+	if err != nil {
+		return err
+	}
+
+	report, err := c.imports.ImportMovies(&domain.ImportMovies{
+		Rows:      rows,
+		BatchSize: *batch,
+		DryRun:    *dryRun,
+	})
+	if err != nil {
+		return err
+	}
+
+	rowsTable := make([][]string, 0, len(report.Rows))
+	for _, row := range report.Rows {
+		title := row.Title
+		if title == "" {
+			title = row.Name
+		}
+		rowsTable = append(rowsTable, []string{strconv.Itoa(row.Row), row.Status, row.ExternalID, title, row.Error})
+	}
+
+	if err = c.print(report, []string{"ROW", "STATUS", "EXTERNAL ID", "TITLE", "ERROR"}, rowsTable); err != nil {
+		return err
+	}
+
+	if c.format == "table" {
+		summary := fmt.Sprintf("\ncreated %d, updated %d, skipped %d, failed %d", report.Created, report.Updated, report.Skipped, report.Failed)
+		if report.DryRun {
+			summary += " (dry run, nothing was saved)"
+		}
+		if _, err = fmt.Fprintln(c.out, summary); err != nil {
+			return err
+		}
+	}
+
+	if report.Failed > 0 {
+		return fmt.Errorf("%d of %d rows failed", report.Failed, len(report.Rows))
+	}
+
+	return nil
+}
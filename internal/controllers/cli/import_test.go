@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+	"github.com/akrovv/filmlibrary/internal/service/mocks"
+	"github.com/golang/mock/gomock"
+)
+
+func TestImport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	is := mocks.NewMockImportService(ctrl)
+	in := `{"external_id": "tt0113277", "movie_title": "Heat", "release_date": "1995-12-15", "rating": 8}` + "\n"
+	out := &bytes.Buffer{}
+	c := NewCLI(strings.NewReader(in), out, nil, nil, nil, is, nil, nil, nil)
+
+	expected := domain.ImportMovies{
+		Rows: []domain.ImportRow{{
+			Row: 1,
+			Movie: domain.ImportMovie{
+				ExternalID:  "tt0113277",
+				Title:       "Heat",
+				ReleaseDate: time.Date(1995, 12, 15, 0, 0, 0, 0, time.UTC),
+				Rating:      8,
+				Actors:      []domain.ImportActor{},
+			},
+		}},
+		BatchSize: 50,
+		DryRun:    true,
+	}
+
+	// OK, stdin read as NDJSON
+	is.EXPECT().ImportMovies(&expected).Return(&domain.ImportReport{
+		DryRun:  true,
+		Created: 1,
+		Rows:    []domain.ImportResult{{Row: 1, ExternalID: "tt0113277", Title: "Heat", Status: domain.ImportCreated}},
+	}, nil)
+
+	err := c.Run([]string{"import", "-format", "ndjson", "-dry-run", "-batch", "50", "-"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if !strings.Contains(out.String(), "created 1, updated 0, skipped 0, failed 0 (dry run, nothing was saved)") {
+		t.Errorf("unexpected output: %q", out.String())
+	}
+
+	// A failed row fails the command
+	c = NewCLI(strings.NewReader(in), &bytes.Buffer{}, nil, nil, nil, is, nil, nil, nil)
+	expected.BatchSize, expected.DryRun = 100, false
+	is.EXPECT().ImportMovies(&expected).Return(&domain.ImportReport{
+		Failed: 1,
+		Rows:   []domain.ImportResult{{Row: 1, Title: "Heat", Status: domain.ImportFailed, Error: "conflict"}},
+	}, nil)
+
+	err = c.Run([]string{"import", "-format", "ndjson", "-"})
+	if err == nil || err.Error() != "1 of 1 rows failed" {
+		t.Errorf("expected rows failed error, got: %v", err)
+	}
+
+	// Unknown format
+	err = c.Run([]string{"import", "-"})
+	if !errors.Is(err, domain.ErrRequest) {
+		t.Errorf("expected ErrRequest, got: %v", err)
+	}
+
+	// No file
+	err = c.Run([]string{"import"})
+	if !errors.Is(err, ErrUsage) {
+		t.Errorf("expected ErrUsage, got: %v", err)
+	}
+}
@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"github.com/akrovv/filmlibrary/internal/adapters/postgresqldb"
+	"github.com/akrovv/filmlibrary/internal/domain"
+)
+
+type UserAdminService interface {
+	Register(user *domain.CRUser) error
+	SetPassword(user *domain.CRUser) error
+	SetAdmin(dto *domain.SetAdmin) error
+	Delete(dto *domain.DeleteUser) error
+	List() ([]domain.User, error)
+}
+
+type ActorService interface {
+	Create(dto *domain.CreateActor) error
+	Patch(dto *domain.PatchActor) error
+	Delete(dto *domain.DeleteActor) error
+	GetList(dto *domain.GetActorList) (*domain.ActorList, error)
+	Get(dto *domain.GetActor) (*domain.Actor, error)
+}
+
+type MovieService interface {
+	Create(dto *domain.CreateMovie) error
+	Patch(dto *domain.PatchMovie) error
+	Delete(dto *domain.DeleteMovie) error
+	GetOrderedList(dto *domain.GetOrderedMovie) (*domain.MovieList, error)
+	GetByID(dto *domain.GetMovieByID) (*domain.MovieWithActors, error)
+}
+
+type ImportService interface {
+	ImportMovies(dto *domain.ImportMovies) (*domain.ImportReport, error)
+}
+
+type ExportService interface {
+	Export(w domain.ExportWriter) error
+}
+
+type IMDbService interface {
+	ImportIMDb(source domain.IMDbSource) (*domain.IMDbReport, error)
+}
+
+type Migrator interface {
+	Up() ([]postgresqldb.Migration, error)
+	Down(steps int) ([]postgresqldb.Migration, error)
+	Status() ([]postgresqldb.MigrationStatus, error)
+}
@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/akrovv/filmlibrary/internal/adapters/postgresqldb"
+)
+
+const migrateUsage = "usage: migrate up | down [steps] | status"
+
+type migrationView struct {
+	Version   int64      `json:"version"`
+	Name      string     `json:"name"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+}
+
+// Migrate runs a migrate command on its own, for the server binary.
+func Migrate(out io.Writer, m Migrator, args []string) error {
+	c := cli{out: out, format: "table", migrator: m}
+	return c.migrate(args)
+}
+
+// migrate applies the pending migrations, rolls back the given number of
+// them (one by default) or lists them all.
+func (c *cli) migrate(args []string) error {
+	if len(args) == 0 {
+		return usageError(migrateUsage)
+	}
+
+	switch args[0] {
+	case "up":
+		if len(args) != 1 {
+			return usageError(migrateUsage)
+		}
+
+		done, err := c.migrator.Up()
+		if printErr := c.printMigrations("applied", done); printErr != nil && err == nil {
+			err = printErr
+		}
+		return err
+	case "down":
+		steps := 1
+		if len(args) > 2 {
+			return usageError(migrateUsage)
+		}
+		if len(args) == 2 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n <= 0 {
+				return fmt.Errorf("invalid number of steps %q", args[1])
+			}
+			steps = n
+		}
+
+		done, err := c.migrator.Down(steps)
+		if printErr := c.printMigrations("rolled back", done); printErr != nil && err == nil {
+			err = printErr
+		}
+		return err
+	case "status":
+		if len(args) != 1 {
+			return usageError(migrateUsage)
+		}
+
+		statuses, err := c.migrator.Status()
+		if err != nil {
+			return err
+		}
+
+		views := make([]migrationView, 0, len(statuses))
+		rows := make([][]string, 0, len(statuses))
+		for _, status := range statuses {
+			applied := "pending"
+			if status.AppliedAt != nil {
+				applied = status.AppliedAt.Format(time.RFC3339)
+			}
+
+			views = append(views, migrationView{Version: status.Version, Name: status.Name, AppliedAt: status.AppliedAt})
+			rows = append(rows, []string{fmt.Sprintf("%04d", status.Version), status.Name, applied})
+		}
+
+		return c.print(views, []string{"VERSION", "NAME", "APPLIED"}, rows)
+	}
+
+	return usageError(migrateUsage)
+}
+
+func (c *cli) printMigrations(action string, migrations []postgresqldb.Migration) error {
+	views := make([]migrationView, 0, len(migrations))
+	for _, migration := range migrations {
+		views = append(views, migrationView{Version: migration.Version, Name: migration.Name})
+	}
+
+	if c.format == "json" {
+		return c.print(views, nil, nil)
+	}
+
+	if len(migrations) == 0 {
+		_, err := fmt.Fprintf(c.out, "nothing %s\n", action)
+		return err
+	}
+
+	for _, migration := range migrations {
+		if _, err := fmt.Fprintf(c.out, "%s %04d_%s\n", action, migration.Version, migration.Name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
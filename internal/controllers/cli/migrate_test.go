@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/akrovv/filmlibrary/internal/adapters/postgresqldb"
+	"github.com/akrovv/filmlibrary/internal/domain"
+	"github.com/akrovv/filmlibrary/internal/service/mocks"
+	"github.com/golang/mock/gomock"
+)
+
+func TestMigrate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockMigrator(ctrl)
+	out := &bytes.Buffer{}
+
+	// Up
+	m.EXPECT().Up().Return([]postgresqldb.Migration{{Version: 2, Name: "row_versions"}}, nil)
+
+	if err := Migrate(out, m, []string{"up"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if out.String() != "applied 0002_row_versions\n" {
+		t.Errorf("unexpected output: %q", out.String())
+	}
+
+	// Down, the applied part is reported on error too
+	out.Reset()
+	m.EXPECT().Down(2).Return([]postgresqldb.Migration{{Version: 3, Name: "third"}}, domain.ErrTest)
+
+	if err := Migrate(out, m, []string{"down", "2"}); !errors.Is(err, domain.ErrTest) {
+		t.Errorf("expected ErrTest, got: %v", err)
+	}
+
+	if out.String() != "rolled back 0003_third\n" {
+		t.Errorf("unexpected output: %q", out.String())
+	}
+
+	// Invalid number of steps
+	if err := Migrate(out, m, []string{"down", "-1"}); err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	// Status
+	out.Reset()
+	appliedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	m.EXPECT().Status().Return([]postgresqldb.MigrationStatus{
+		{Migration: postgresqldb.Migration{Version: 1, Name: "init"}, AppliedAt: &appliedAt},
+		{Migration: postgresqldb.Migration{Version: 2, Name: "row_versions"}},
+	}, nil)
+
+	if err := Migrate(out, m, []string{"status"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := "VERSION  NAME          APPLIED\n" +
+		"0001     init          2024-03-01T12:00:00Z\n" +
+		"0002     row_versions  pending\n"
+	if out.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+
+	// Unknown command
+	if err := Migrate(out, m, []string{"redo"}); !errors.Is(err, ErrUsage) {
+		t.Errorf("expected ErrUsage, got: %v", err)
+	}
+}
@@ -0,0 +1,229 @@
+package cli
+
+import (
+	"flag"
+	"strconv"
+	"strings"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+)
+
+const movieUsage = `usage: filmctl movie list [-sort -rating,title] [-limit 20] [-after cursor | -before cursor]
+       filmctl movie show <id>
+       filmctl movie add -title <title> [-description <text>] [-release-date YYYY-MM-DD] -rating <1-10> [-actors 1,2]
+       filmctl movie edit <id> [-title <title>] [-description <text>] [-release-date YYYY-MM-DD] [-rating <1-10>] [-actors 1,2]
+       filmctl movie delete <id>`
+
+func (c *cli) movie(args []string) error {
+	if len(args) == 0 {
+		return usageError(movieUsage)
+	}
+
+	fs := newFlagSet("movie " + args[0])
+	switch args[0] {
+	case "list":
+		sort := fs.String("sort", "-rating", "sort fields")
+		page := pageFlags(fs)
+		if _, err := parseArgs(fs, args[1:], 0, movieUsage); err != nil {
+			return err
+		}
+		if err := checkPage(*page); err != nil {
+			return err
+		}
+		return c.movieList(*sort, *page)
+	case "show":
+		rest, err := parseArgs(fs, args[1:], 1, movieUsage)
+		if err != nil {
+			return err
+		}
+		return c.movieShow(rest[0])
+	case "add", "edit":
+		fs.String("title", "", "title")
+		fs.String("description", "", "description")
+		fs.String("release-date", "", "release date")
+		fs.Uint("rating", 0, "rating from 1 to 10")
+		fs.String("actors", "", "comma separated actor IDs")
+
+		positional := 0
+		if args[0] == "edit" {
+			positional = 1
+		}
+
+		rest, err := parseArgs(fs, args[1:], positional, movieUsage)
+		if err != nil {
+			return err
+		}
+
+		if args[0] == "add" {
+			return c.movieAdd(fs)
+		}
+		return c.movieEdit(rest[0], fs)
+	case "delete":
+		rest, err := parseArgs(fs, args[1:], 1, movieUsage)
+		if err != nil {
+			return err
+		}
+		return c.movieDelete(rest[0])
+	}
+
+	return usageError(movieUsage)
+}
+
+func (c *cli) movieList(spec string, page domain.Page) error {
+	sort, err := domain.ParseSort(spec, domain.MovieSortFields)
+	if err != nil {
+		return err
+	}
+
+	list, err := c.movies.GetOrderedList(&domain.GetOrderedMovie{Sort: sort, Page: page})
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]string, 0, len(list.Movies))
+	for _, movie := range list.Movies {
+		rows = append(rows, []string{
+			strconv.FormatInt(movie.ID, 10),
+			movie.Title,
+			formatDate(movie.ReleaseDate),
+			strconv.Itoa(int(movie.Rating)),
+		})
+	}
+
+	if err = c.print(list, []string{"ID", "TITLE", "RELEASE DATE", "RATING"}, rows); err != nil {
+		return err
+	}
+
+	return c.printCursors(list.Cursors)
+}
+
+func (c *cli) movieShow(value string) error {
+	id, err := parseID(value)
+	if err != nil {
+		return err
+	}
+
+	movie, err := c.movies.GetByID(&domain.GetMovieByID{ID: id})
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(movie.Actors))
+	for _, actor := range movie.Actors {
+		names = append(names, actor.Name)
+	}
+
+	return c.print(movie, []string{"ID", "TITLE", "RELEASE DATE", "RATING", "ACTORS"}, [][]string{{
+		strconv.FormatInt(movie.ID, 10),
+		movie.Title,
+		formatDate(movie.ReleaseDate),
+		strconv.Itoa(int(movie.Rating)),
+		strings.Join(names, ", "),
+	}})
+}
+
+func (c *cli) movieAdd(fs *flag.FlagSet) error {
+	patch, err := moviePatch(fs)
+	if err != nil {
+		return err
+	}
+
+	dto := domain.CreateMovie{Actors: patch.Actors}
+	if patch.Title != nil {
+		dto.Title = *patch.Title
+	}
+	if patch.Description != nil {
+		dto.Description = *patch.Description
+	}
+	if patch.ReleaseDate != nil {
+		dto.ReleaseDate = *patch.ReleaseDate
+	}
+	if patch.Rating != nil {
+		dto.Rating = *patch.Rating
+	}
+	if dto.Actors == nil {
+		dto.Actors = []int64{}
+	}
+
+	if err = c.movies.Create(&dto); err != nil {
+		return err
+	}
+
+	return c.done("added movie %q", dto.Title)
+}
+
+// movieEdit changes only the fields given on the command line, whatever the
+// current version of the movie is.
+func (c *cli) movieEdit(value string, fs *flag.FlagSet) error {
+	id, err := parseID(value)
+	if err != nil {
+		return err
+	}
+
+	patch, err := moviePatch(fs)
+	if err != nil {
+		return err
+	}
+	patch.ID = id
+
+	if err = c.movies.Patch(patch); err != nil {
+		return err
+	}
+
+	return c.done("edited movie %d", id)
+}
+
+func (c *cli) movieDelete(value string) error {
+	id, err := parseID(value)
+	if err != nil {
+		return err
+	}
+
+	if err = c.movies.Delete(&domain.DeleteMovie{ID: id}); err != nil {
+		return err
+	}
+
+	return c.done("deleted movie %d", id)
+}
+
+// moviePatch collects the movie fields set on the command line.
+func moviePatch(fs *flag.FlagSet) (*domain.PatchMovie, error) {
+	patch := domain.PatchMovie{}
+
+	if isSet(fs, "title") {
+		title := fs.Lookup("title").Value.String()
+		patch.Title = &title
+	}
+
+	if isSet(fs, "description") {
+		description := fs.Lookup("description").Value.String()
+		patch.Description = &description
+	}
+
+	if isSet(fs, "release-date") {
+		date, err := parseDate(fs.Lookup("release-date").Value.String())
+		if err != nil {
+			return nil, err
+		}
+		patch.ReleaseDate = &date
+	}
+
+	if isSet(fs, "rating") {
+		rating, err := strconv.ParseUint(fs.Lookup("rating").Value.String(), 10, 8)
+		if err != nil {
+			return nil, err
+		}
+		value := uint8(rating)
+		patch.Rating = &value
+	}
+
+	if isSet(fs, "actors") {
+		actors, err := parseIDs(fs.Lookup("actors").Value.String())
+		if err != nil {
+			return nil, err
+		}
+		patch.Actors = actors
+	}
+
+	return &patch, nil
+}
@@ -0,0 +1,164 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+	"github.com/akrovv/filmlibrary/internal/service/mocks"
+	"github.com/golang/mock/gomock"
+)
+
+func TestMovieAdd(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ms := mocks.NewMockMovieService(ctrl)
+	out := &bytes.Buffer{}
+	c := NewCLI(strings.NewReader(""), out, nil, ms, nil, nil, nil, nil, nil)
+
+	expected := domain.CreateMovie{
+		Title:       "Title",
+		Description: "Description",
+		ReleaseDate: time.Date(2007, 2, 2, 0, 0, 0, 0, time.UTC),
+		Rating:      5,
+		Actors:      []int64{1, 2},
+	}
+
+	// OK
+	ms.EXPECT().Create(&expected).Return(nil)
+
+	err := c.Run([]string{"movie", "add", "-title", "Title", "-description", "Description",
+		"-release-date", "2007-02-02", "-rating", "5", "-actors", "1,2"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if out.String() != "added movie \"Title\"\n" {
+		t.Errorf("unexpected output: %q", out.String())
+	}
+
+	// Invalid date
+	err = c.Run([]string{"movie", "add", "-title", "Title", "-release-date", "02.02.2007"})
+	if err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	// Invalid actors
+	err = c.Run([]string{"movie", "add", "-title", "Title", "-actors", "1,x"})
+	if err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	// Unknown flag
+	err = c.Run([]string{"movie", "add", "-budget", "100"})
+	if !errors.Is(err, ErrUsage) {
+		t.Errorf("expected ErrUsage, got: %v", err)
+	}
+}
+
+func TestMovieEdit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ms := mocks.NewMockMovieService(ctrl)
+	c := NewCLI(strings.NewReader(""), &bytes.Buffer{}, nil, ms, nil, nil, nil, nil, nil)
+
+	rating := uint8(9)
+
+	// Only the given fields are patched
+	ms.EXPECT().Patch(&domain.PatchMovie{ID: 3, Rating: &rating}).Return(nil)
+
+	if err := c.Run([]string{"movie", "edit", "3", "-rating", "9"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// Flags before the ID, cast cleared
+	ms.EXPECT().Patch(&domain.PatchMovie{ID: 3, Actors: []int64{}}).Return(nil)
+
+	if err := c.Run([]string{"movie", "edit", "-actors", "", "3"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// Invalid ID
+	if err := c.Run([]string{"movie", "edit", "x", "-rating", "9"}); err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	// Patch returned error
+	ms.EXPECT().Patch(&domain.PatchMovie{ID: 3, Rating: &rating}).Return(domain.ErrNotFound)
+
+	if err := c.Run([]string{"movie", "edit", "3", "-rating", "9"}); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got: %v", err)
+	}
+}
+
+func TestMovieList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ms := mocks.NewMockMovieService(ctrl)
+	out := &bytes.Buffer{}
+	c := NewCLI(strings.NewReader(""), out, nil, ms, nil, nil, nil, nil, nil)
+
+	dto := domain.GetOrderedMovie{
+		Sort: []domain.SortField{{Field: "title"}},
+		Page: domain.Page{Limit: 2, After: "abc"},
+	}
+
+	list := domain.MovieList{
+		Movies: []domain.Movie{
+			{ID: 1, Title: "Alien", ReleaseDate: time.Date(1979, 5, 25, 0, 0, 0, 0, time.UTC), Rating: 8},
+			{ID: 2, Title: "Brazil", Rating: 7},
+		},
+		Cursors: domain.Cursors{Next: "def"},
+	}
+
+	// OK
+	ms.EXPECT().GetOrderedList(&dto).Return(&list, nil)
+
+	if err := c.Run([]string{"movie", "list", "-sort", "title", "-limit", "2", "-after", "abc"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := "ID  TITLE   RELEASE DATE  RATING\n" +
+		"1   Alien   1979-05-25    8\n" +
+		"2   Brazil                7\n" +
+		"\nnext page: -after def\n"
+	if out.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+
+	// Unknown sort field
+	if err := c.Run([]string{"movie", "list", "-sort", "budget"}); !errors.Is(err, domain.ErrRequest) {
+		t.Errorf("expected ErrRequest, got: %v", err)
+	}
+
+	// Both cursors
+	if err := c.Run([]string{"movie", "list", "-after", "a", "-before", "b"}); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
+
+func TestMovieDelete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ms := mocks.NewMockMovieService(ctrl)
+	c := NewCLI(strings.NewReader(""), &bytes.Buffer{}, nil, ms, nil, nil, nil, nil, nil)
+
+	// OK, any version
+	ms.EXPECT().Delete(&domain.DeleteMovie{ID: 4}).Return(nil)
+
+	if err := c.Run([]string{"movie", "delete", "4"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// Extra arguments
+	if err := c.Run([]string{"movie", "delete", "4", "5"}); !errors.Is(err, ErrUsage) {
+		t.Errorf("expected ErrUsage, got: %v", err)
+	}
+}
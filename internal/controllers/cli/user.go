@@ -0,0 +1,133 @@
+package cli
+
+import (
+	"github.com/akrovv/filmlibrary/internal/domain"
+)
+
+const userUsage = `usage: filmctl user list
+       filmctl user create <username> [-admin]   (password is read from stdin)
+       filmctl user promote <username>
+       filmctl user demote <username>
+       filmctl user passwd <username>            (password is read from stdin)
+       filmctl user delete <username>`
+
+type userView struct {
+	Username string `json:"username"`
+	IsAdmin  bool   `json:"is_admin"`
+}
+
+func (c *cli) user(args []string) error {
+	if len(args) == 0 {
+		return usageError(userUsage)
+	}
+
+	fs := newFlagSet("user " + args[0])
+	switch args[0] {
+	case "list":
+		if _, err := parseArgs(fs, args[1:], 0, userUsage); err != nil {
+			return err
+		}
+		return c.userList()
+	case "create":
+		admin := fs.Bool("admin", false, "make the user an admin")
+		rest, err := parseArgs(fs, args[1:], 1, userUsage)
+		if err != nil {
+			return err
+		}
+		return c.userCreate(rest[0], *admin)
+	case "promote", "demote":
+		rest, err := parseArgs(fs, args[1:], 1, userUsage)
+		if err != nil {
+			return err
+		}
+		return c.userSetAdmin(rest[0], args[0] == "promote")
+	case "passwd":
+		rest, err := parseArgs(fs, args[1:], 1, userUsage)
+		if err != nil {
+			return err
+		}
+		return c.userPasswd(rest[0])
+	case "delete":
+		rest, err := parseArgs(fs, args[1:], 1, userUsage)
+		if err != nil {
+			return err
+		}
+		return c.userDelete(rest[0])
+	}
+
+	return usageError(userUsage)
+}
+
+func (c *cli) userList() error {
+	users, err := c.users.List()
+	if err != nil {
+		return err
+	}
+
+	views := make([]userView, 0, len(users))
+	rows := make([][]string, 0, len(users))
+	for _, user := range users {
+		role := "user"
+		if user.IsAdmin {
+			role = "admin"
+		}
+
+		views = append(views, userView{Username: user.Username, IsAdmin: user.IsAdmin})
+		rows = append(rows, []string{user.Username, role})
+	}
+
+	return c.print(views, []string{"USERNAME", "ROLE"}, rows)
+}
+
+func (c *cli) userCreate(username string, admin bool) error {
+	password, err := c.readPassword()
+	if err != nil {
+		return err
+	}
+
+	if err = c.users.Register(&domain.CRUser{Username: username, Password: password}); err != nil {
+		return err
+	}
+
+	if admin {
+		if err = c.users.SetAdmin(&domain.SetAdmin{Username: username, IsAdmin: true}); err != nil {
+			return err
+		}
+		return c.done("created admin %s", username)
+	}
+
+	return c.done("created user %s", username)
+}
+
+func (c *cli) userSetAdmin(username string, admin bool) error {
+	if err := c.users.SetAdmin(&domain.SetAdmin{Username: username, IsAdmin: admin}); err != nil {
+		return err
+	}
+
+	if admin {
+		return c.done("promoted %s to admin", username)
+	}
+
+	return c.done("demoted %s to user", username)
+}
+
+func (c *cli) userPasswd(username string) error {
+	password, err := c.readPassword()
+	if err != nil {
+		return err
+	}
+
+	if err = c.users.SetPassword(&domain.CRUser{Username: username, Password: password}); err != nil {
+		return err
+	}
+
+	return c.done("changed password of %s", username)
+}
+
+func (c *cli) userDelete(username string) error {
+	if err := c.users.Delete(&domain.DeleteUser{Username: username}); err != nil {
+		return err
+	}
+
+	return c.done("deleted user %s", username)
+}
@@ -0,0 +1,151 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+	"github.com/akrovv/filmlibrary/internal/service/mocks"
+	"github.com/golang/mock/gomock"
+)
+
+func TestUserCreate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	us := mocks.NewMockUserAdminService(ctrl)
+	out := &bytes.Buffer{}
+
+	// OK, admin
+	c := NewCLI(strings.NewReader("secret\n"), out, us, nil, nil, nil, nil, nil, nil)
+	us.EXPECT().Register(&domain.CRUser{Username: "root", Password: "secret"}).Return(nil)
+	us.EXPECT().SetAdmin(&domain.SetAdmin{Username: "root", IsAdmin: true}).Return(nil)
+
+	if err := c.Run([]string{"user", "create", "root", "-admin"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if out.String() != "created admin root\n" {
+		t.Errorf("unexpected output: %q", out.String())
+	}
+
+	// Register returned error
+	c = NewCLI(strings.NewReader("secret"), out, us, nil, nil, nil, nil, nil, nil)
+	us.EXPECT().Register(&domain.CRUser{Username: "root", Password: "secret"}).Return(domain.ErrConflict)
+
+	if err := c.Run([]string{"user", "create", "root"}); !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("expected ErrConflict, got: %v", err)
+	}
+
+	// No username
+	if err := c.Run([]string{"user", "create"}); !errors.Is(err, ErrUsage) {
+		t.Errorf("expected ErrUsage, got: %v", err)
+	}
+}
+
+func TestUserSetAdmin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	us := mocks.NewMockUserAdminService(ctrl)
+	out := &bytes.Buffer{}
+	c := NewCLI(strings.NewReader(""), out, us, nil, nil, nil, nil, nil, nil)
+
+	// Promote
+	us.EXPECT().SetAdmin(&domain.SetAdmin{Username: "user", IsAdmin: true}).Return(nil)
+
+	if err := c.Run([]string{"user", "promote", "user"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// Demote, in JSON
+	out.Reset()
+	us.EXPECT().SetAdmin(&domain.SetAdmin{Username: "user", IsAdmin: false}).Return(nil)
+
+	if err := c.Run([]string{"-o", "json", "user", "demote", "user"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	result := message{}
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatalf("can't decode output: %s", err)
+	}
+
+	if result.Message != "demoted user to user" {
+		t.Errorf("unexpected message: %q", result.Message)
+	}
+
+	// No such user
+	us.EXPECT().SetAdmin(&domain.SetAdmin{Username: "nobody", IsAdmin: true}).Return(domain.ErrNotFound)
+
+	if err := c.Run([]string{"user", "promote", "nobody"}); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got: %v", err)
+	}
+}
+
+func TestUserPasswd(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	us := mocks.NewMockUserAdminService(ctrl)
+	c := NewCLI(strings.NewReader("new password\r\n"), &bytes.Buffer{}, us, nil, nil, nil, nil, nil, nil)
+
+	us.EXPECT().SetPassword(&domain.CRUser{Username: "user", Password: "new password"}).Return(nil)
+
+	if err := c.Run([]string{"user", "passwd", "user"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
+
+func TestUserList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	us := mocks.NewMockUserAdminService(ctrl)
+	out := &bytes.Buffer{}
+	c := NewCLI(strings.NewReader(""), out, us, nil, nil, nil, nil, nil, nil)
+
+	users := []domain.User{
+		{Username: "admin", IsAdmin: true},
+		{Username: "user"},
+	}
+
+	// Table
+	us.EXPECT().List().Return(users, nil)
+
+	if err := c.Run([]string{"user", "list"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := "USERNAME  ROLE\nadmin     admin\nuser      user\n"
+	if out.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+
+	// JSON
+	out.Reset()
+	us.EXPECT().List().Return(users, nil)
+
+	if err := c.Run([]string{"-o", "json", "user", "list"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	views := []userView{}
+	if err := json.Unmarshal(out.Bytes(), &views); err != nil {
+		t.Fatalf("can't decode output: %s", err)
+	}
+
+	expectedViews := []userView{{Username: "admin", IsAdmin: true}, {Username: "user"}}
+	if !reflect.DeepEqual(views, expectedViews) {
+		t.Errorf("expected: %v, got: %v", expectedViews, views)
+	}
+
+	// Unknown format
+	if err := c.Run([]string{"-o", "xml", "user", "list"}); !errors.Is(err, ErrUsage) {
+		t.Errorf("expected ErrUsage, got: %v", err)
+	}
+}
@@ -27,12 +27,46 @@ func (c *actorController) ManagePath(w http.ResponseWriter, r *http.Request) {
 	switch method {
 	case "POST":
 		c.Create(w, r)
-	case "PUT":
-		c.Update(w, r)
-	case "DELETE":
-		c.Delete(w, r)
 	case "GET":
 		c.GetList(w, r)
+	default:
+		methodNotAllowed(w, r, "GET", "POST")
+	}
+}
+
+func (c *actorController) ManageItem(w http.ResponseWriter, r *http.Request) {
+	if _, err := pathID(r, actorsPath); err != nil {
+		c.logger.Infof("pathID error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	params := pathParams(r, actorsPath)
+	method := r.Method
+
+	switch {
+	case len(params) == 1:
+		switch method {
+		case "GET":
+			c.Get(w, r)
+		case "PUT":
+			c.Update(w, r)
+		case "PATCH":
+			c.Patch(w, r)
+		case "DELETE":
+			c.Delete(w, r)
+		default:
+			methodNotAllowed(w, r, "GET", "PUT", "PATCH", "DELETE")
+		}
+	case len(params) == 2 && params[1] == "movies":
+		switch method {
+		case "GET":
+			c.GetMovies(w, r)
+		default:
+			methodNotAllowed(w, r, "GET")
+		}
+	default:
+		ErrorJSON(w, r, domain.ErrNotFound)
 	}
 }
 
@@ -43,36 +77,37 @@ func (c *actorController) ManagePath(w http.ResponseWriter, r *http.Request) {
 // @Produce      json
 // @Param request body domain.CreateActor true "request"
 // @Success 201 {object} sender.JSONResponse
-// @Failure 400 {object} sender.JSONResponse
-// @Failure 500 {object} sender.JSONResponse
+// @Failure 400 {object} sender.Problem
+// @Failure 422 {object} sender.Problem
+// @Failure 500 {object} sender.Problem
 // @Router       /actor [post]
 func (c *actorController) Create(w http.ResponseWriter, r *http.Request) {
 	if r.Header.Get("Content-Type") != "application/json" {
 		c.logger.Infof("request didnt contain application/json")
-		sender.ErrorJSON(w, domain.ErrRequest, http.StatusBadRequest)
+		ErrorJSON(w, r, errContentType)
 		return
 	}
 
 	data, err := io.ReadAll(r.Body)
 	if err != nil {
 		c.logger.Infof("io.ReadAll error: %w", err)
-		sender.ErrorJSON(w, err, http.StatusInternalServerError)
+		ErrorJSON(w, r, err)
 		return
 	}
 	defer r.Body.Close()
 
 	createActorDTO := domain.CreateActor{}
-	err = json.Unmarshal(data, &createActorDTO)
+	err = unmarshalRequest(data, &createActorDTO)
 
 	if err != nil {
-		c.logger.Infof("json.Unmarshal error: %w", err)
-		sender.ErrorJSON(w, err, http.StatusInternalServerError)
+		c.logger.Infof("unmarshalRequest error: %w", err)
+		ErrorJSON(w, r, err)
 		return
 	}
 
 	if err = c.service.Create(&createActorDTO); err != nil {
 		c.logger.Infof("c.ActorService.Create error: %w", err)
-		sender.ErrorJSON(w, err, http.StatusInternalServerError)
+		ErrorJSON(w, r, err)
 		return
 	}
 
@@ -93,38 +128,61 @@ func (c *actorController) Create(w http.ResponseWriter, r *http.Request) {
 // @Tags		 actor
 // @Accept       json
 // @Produce      json
+// @Param id path int true "Actor ID"
+// @Param If-Match header string true "ETag of the actor, or * for any version"
 // @Param request body domain.Actor true "request"
 // @Success 200 {object} sender.JSONResponse
-// @Failure 400 {object} sender.JSONResponse
-// @Failure 500 {object} sender.JSONResponse
-// @Router       /actor [put]
+// @Failure 400 {object} sender.Problem
+// @Failure 404 {object} sender.Problem
+// @Failure 412 {object} sender.Problem
+// @Failure 422 {object} sender.Problem
+// @Failure 428 {object} sender.Problem
+// @Failure 500 {object} sender.Problem
+// @Router       /actors/{id} [put]
 func (c *actorController) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, actorsPath)
+	if err != nil {
+		c.logger.Infof("pathID error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	version, err := ifMatch(r, "actor", id)
+	if err != nil {
+		c.logger.Infof("ifMatch error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+
 	if r.Header.Get("Content-Type") != "application/json" {
 		c.logger.Infof("request didnt contain application/json")
-		sender.ErrorJSON(w, domain.ErrRequest, http.StatusBadRequest)
+		ErrorJSON(w, r, errContentType)
 		return
 	}
 
 	data, err := io.ReadAll(r.Body)
 	if err != nil {
 		c.logger.Infof("io.ReadAll error: %w", err)
-		sender.ErrorJSON(w, err, http.StatusInternalServerError)
+		ErrorJSON(w, r, err)
 		return
 	}
 	defer r.Body.Close()
 
 	actor := domain.Actor{}
-	err = json.Unmarshal(data, &actor)
+	err = unmarshalRequest(data, &actor)
 
 	if err != nil {
-		c.logger.Infof("json.Unmarshal error: %w", err)
-		sender.ErrorJSON(w, err, http.StatusInternalServerError)
+		c.logger.Infof("unmarshalRequest error: %w", err)
+		ErrorJSON(w, r, err)
 		return
 	}
+	actor.ID = id
+	actor.Version = version
 
-	if err = c.service.Update(&actor); err != nil {
+	err = c.service.Update(&actor)
+	if err != nil {
 		c.logger.Infof("c.ActorService.Update error: %w", err)
-		sender.ErrorJSON(w, err, http.StatusInternalServerError)
+		ErrorJSON(w, r, err)
 		return
 	}
 
@@ -140,43 +198,119 @@ func (c *actorController) Update(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// @Summary Delete
-// @Description  Delete an actor from the film library
+// @Summary Patch
+// @Description  Change only the supplied fields of an actor (JSON Merge Patch). Null clears date_of_birth
 // @Tags		 actor
-// @Accept       json
+// @Accept       application/merge-patch+json
 // @Produce      json
-// @Param request body domain.DeleteActor true "request"
+// @Param id path int true "Actor ID"
+// @Param If-Match header string true "ETag of the actor, or * for any version"
+// @Param request body domain.PatchActor true "request"
 // @Success 200 {object} sender.JSONResponse
-// @Failure 400 {object} sender.JSONResponse
-// @Failure 500 {object} sender.JSONResponse
-// @Router       /actor [delete]
-func (c *actorController) Delete(w http.ResponseWriter, r *http.Request) {
-	if r.Header.Get("Content-Type") != "application/json" {
-		c.logger.Infof("request didnt contain application/json")
-		sender.ErrorJSON(w, domain.ErrRequest, http.StatusBadRequest)
+// @Failure 400 {object} sender.Problem
+// @Failure 404 {object} sender.Problem
+// @Failure 412 {object} sender.Problem
+// @Failure 422 {object} sender.Problem
+// @Failure 428 {object} sender.Problem
+// @Failure 500 {object} sender.Problem
+// @Router       /actors/{id} [patch]
+func (c *actorController) Patch(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, actorsPath)
+	if err != nil {
+		c.logger.Infof("pathID error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	version, err := ifMatch(r, "actor", id)
+	if err != nil {
+		c.logger.Infof("ifMatch error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	if !isMergePatch(r) {
+		c.logger.Infof("request didnt contain application/merge-patch+json")
+		ErrorJSON(w, r, errMergePatchType)
 		return
 	}
 
 	data, err := io.ReadAll(r.Body)
 	if err != nil {
 		c.logger.Infof("io.ReadAll error: %w", err)
-		sender.ErrorJSON(w, err, http.StatusInternalServerError)
+		ErrorJSON(w, r, err)
 		return
 	}
 	defer r.Body.Close()
 
-	deleteActorDTO := domain.DeleteActor{}
-	err = json.Unmarshal(data, &deleteActorDTO)
+	patchActorDTO := domain.PatchActor{}
+	nulls, err := decodeMergePatch(data, &patchActorDTO, "date_of_birth")
+	if err != nil {
+		c.logger.Infof("decodeMergePatch error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+	patchActorDTO.NullDateBirth = nulls["date_of_birth"]
+	patchActorDTO.ID = id
+	patchActorDTO.Version = version
+
+	err = c.service.Patch(&patchActorDTO)
+	if err != nil {
+		c.logger.Infof("c.ActorService.Patch error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	if err = sender.WriteJSON(w, http.StatusOK, struct {
+		Status  string `json:"status"`
+		Message string `json:"message"`
+	}{
+		Status:  "OK",
+		Message: "actor was updated",
+	}); err != nil {
+		c.logger.Infof("WriteJSON %w", err)
+		return
+	}
+}
 
+// @Summary Delete
+// @Description  Delete an actor from the film library
+// @Tags		 actor
+// @Accept       json
+// @Produce      json
+// @Param id path int true "Actor ID"
+// @Param If-Match header string true "ETag of the actor, or * for any version"
+// @Success 200 {object} sender.JSONResponse
+// @Failure 400 {object} sender.Problem
+// @Failure 404 {object} sender.Problem
+// @Failure 412 {object} sender.Problem
+// @Failure 428 {object} sender.Problem
+// @Failure 500 {object} sender.Problem
+// @Router       /actors/{id} [delete]
+func (c *actorController) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, actorsPath)
 	if err != nil {
-		c.logger.Infof("json.Unmarshal error: %w", err)
-		sender.ErrorJSON(w, err, http.StatusInternalServerError)
+		c.logger.Infof("pathID error: %w", err)
+		ErrorJSON(w, r, err)
 		return
 	}
 
-	if err = c.service.Delete(&deleteActorDTO); err != nil {
+	version, err := ifMatch(r, "actor", id)
+	if err != nil {
+		c.logger.Infof("ifMatch error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	deleteActorDTO := domain.DeleteActor{
+		ID:      id,
+		Version: version,
+	}
+
+	err = c.service.Delete(&deleteActorDTO)
+	if err != nil {
 		c.logger.Infof("c.ActorService.Delete error: %w", err)
-		sender.ErrorJSON(w, err, http.StatusInternalServerError)
+		ErrorJSON(w, r, err)
 		return
 	}
 
@@ -193,27 +327,129 @@ func (c *actorController) Delete(w http.ResponseWriter, r *http.Request) {
 }
 
 // @Summary GetList
-// @Description  Get a list of all actors available in the film library
+// @Description  Get a list of all actors available in the film library, each with their filmography
 // @Tags		 actor
 // @Accept       json
 // @Produce      json
-// @Success 200 {object} []domain.ActorWithMovie
-// @Failure 400 {object} sender.JSONResponse
-// @Failure 500 {object} sender.JSONResponse
+// @Param limit query int false "Page size (1-100, default 20)"
+// @Param after query string false "Cursor of the next page (next_cursor)"
+// @Param before query string false "Cursor of the previous page (prev_cursor)"
+// @Success 200 {object} domain.ActorList
+// @Failure 400 {object} sender.Problem
+// @Failure 500 {object} sender.Problem
 // @Router       /actor [get]
 func (c *actorController) GetList(w http.ResponseWriter, r *http.Request) {
-	actors, err := c.service.GetList()
+	page, err := queryCursor(r)
+	if err != nil {
+		c.logger.Infof("queryCursor error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
 
+	getActorListDTO := domain.GetActorList{
+		Page: page,
+	}
+
+	actors, err := c.service.GetList(&getActorListDTO)
 	if err != nil {
 		c.logger.Infof("c.ActorService.GetList error: %w", err)
-		sender.ErrorJSON(w, err, http.StatusInternalServerError)
+		ErrorJSON(w, r, err)
 		return
 	}
 
 	jsonResult, err := json.Marshal(actors)
 	if err != nil {
 		c.logger.Infof("json.Marshal error: %w", err)
-		sender.ErrorJSON(w, err, http.StatusInternalServerError)
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	_, err = w.Write(jsonResult)
+	if err != nil {
+		c.logger.Infof("Write %w", err)
+		return
+	}
+}
+
+// @Summary Get
+// @Description  Get an actor from the film library
+// @Tags		 actor
+// @Accept       json
+// @Produce      json
+// @Param id path int true "Actor ID"
+// @Success 200 {object} domain.Actor
+// @Header 200 {string} ETag "Version of the actor to send back in If-Match"
+// @Failure 400 {object} sender.Problem
+// @Failure 404 {object} sender.Problem
+// @Failure 500 {object} sender.Problem
+// @Router       /actors/{id} [get]
+func (c *actorController) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, actorsPath)
+	if err != nil {
+		c.logger.Infof("pathID error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	getActorDTO := domain.GetActor{
+		ID: id,
+	}
+
+	actor, err := c.service.Get(&getActorDTO)
+	if err != nil {
+		c.logger.Infof("c.ActorService.Get error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	jsonResult, err := json.Marshal(actor)
+	if err != nil {
+		c.logger.Infof("json.Marshal error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	w.Header().Set("ETag", etag(actor.Version))
+	_, err = w.Write(jsonResult)
+	if err != nil {
+		c.logger.Infof("Write %w", err)
+		return
+	}
+}
+
+// @Summary GetMovies
+// @Description  Get the filmography of an actor in the film library
+// @Tags		 actor
+// @Accept       json
+// @Produce      json
+// @Param id path int true "Actor ID"
+// @Success 200 {object} []domain.Movie
+// @Failure 400 {object} sender.Problem
+// @Failure 500 {object} sender.Problem
+// @Router       /actors/{id}/movies [get]
+func (c *actorController) GetMovies(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, actorsPath)
+	if err != nil {
+		c.logger.Infof("pathID error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	getActorMoviesDTO := domain.GetActorMovies{
+		ID: id,
+	}
+
+	movies, err := c.service.GetMovies(&getActorMoviesDTO)
+	if err != nil {
+		c.logger.Infof("c.ActorService.GetMovies error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	jsonResult, err := json.Marshal(movies)
+	if err != nil {
+		c.logger.Infof("json.Marshal error: %w", err)
+		ErrorJSON(w, r, err)
 		return
 	}
 
@@ -73,8 +73,8 @@ func TestActorCreate(t *testing.T) {
 
 	actorHandler.Create(w, req)
 
-	if w.Code != http.StatusInternalServerError {
-		t.Errorf("expected 500, got: %d", w.Code)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got: %d", w.Code)
 	}
 
 	// io.ReadAll returned error
@@ -132,9 +132,11 @@ func TestActorUpdate(t *testing.T) {
 		Name:      "user",
 		Gender:    "Male",
 		DateBirth: expectedTime,
+		Version:   1,
 	}
 
-	req := httptest.NewRequest("PUT", "/actor", strings.NewReader(body))
+	req := httptest.NewRequest("PUT", "/actors/1", strings.NewReader(body))
+	req.Header.Add("If-Match", `"1"`)
 	req.Header.Add("Content-type", "application/json")
 	w := httptest.NewRecorder()
 
@@ -146,8 +148,20 @@ func TestActorUpdate(t *testing.T) {
 		t.Errorf("expected 200, got: %d", w.Code)
 	}
 
+	// Incorrect ID
+	req = httptest.NewRequest("PUT", "/actors/abc", strings.NewReader(body))
+	req.Header.Add("If-Match", `"1"`)
+	req.Header.Add("Content-type", "application/json")
+	w = httptest.NewRecorder()
+	actorHandler.Update(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got: %d", w.Code)
+	}
+
 	// Missed Content-Type
-	req.Header.Del("Content-type")
+	req = httptest.NewRequest("PUT", "/actors/1", strings.NewReader(body))
+	req.Header.Add("If-Match", `"1"`)
 	w = httptest.NewRecorder()
 	actorHandler.Update(w, req)
 
@@ -156,18 +170,20 @@ func TestActorUpdate(t *testing.T) {
 	}
 
 	// Incorrect JSON
-	req = httptest.NewRequest("PUT", "/actor", strings.NewReader(`{"actor_id": {`))
+	req = httptest.NewRequest("PUT", "/actors/1", strings.NewReader(`{"actor_id": {`))
+	req.Header.Add("If-Match", `"1"`)
 	req.Header.Add("Content-type", "application/json")
 	w = httptest.NewRecorder()
 
 	actorHandler.Update(w, req)
 
-	if w.Code != http.StatusInternalServerError {
-		t.Errorf("expected 500, got: %d", w.Code)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got: %d", w.Code)
 	}
 
 	// io.ReadAll returned error
-	req = httptest.NewRequest("PUT", "/actor", &BadReader{})
+	req = httptest.NewRequest("PUT", "/actors/1", &BadReader{})
+	req.Header.Add("If-Match", `"1"`)
 	req.Header.Add("Content-type", "application/json")
 	w = httptest.NewRecorder()
 
@@ -178,7 +194,8 @@ func TestActorUpdate(t *testing.T) {
 	}
 
 	// Register returned error
-	req = httptest.NewRequest("POST", "/register", strings.NewReader(body))
+	req = httptest.NewRequest("PUT", "/actors/1", strings.NewReader(body))
+	req.Header.Add("If-Match", `"1"`)
 	req.Header.Add("Content-type", "application/json")
 	w = httptest.NewRecorder()
 
@@ -190,7 +207,7 @@ func TestActorUpdate(t *testing.T) {
 	}
 }
 
-func TestActorDelete(t *testing.T) {
+func TestActorPatch(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -203,60 +220,164 @@ func TestActorDelete(t *testing.T) {
 
 	actorHandler := NewActorController(logger, as)
 
-	body := `{
-		"actor_id": 1
-	  }`
-
-	deleteActor := domain.DeleteActor{
-		ID: 1,
+	name := "name"
+	patchActor := domain.PatchActor{
+		ID:      1,
+		Name:    &name,
+		Version: 1,
 	}
 
-	req := httptest.NewRequest("DELETE", "/actor", strings.NewReader(body))
-	req.Header.Add("Content-type", "application/json")
+	req := httptest.NewRequest("PATCH", "/actors/1", strings.NewReader(`{"actor_name": "name"}`))
+	req.Header.Add("If-Match", `"1"`)
+	req.Header.Add("Content-type", "application/merge-patch+json")
 	w := httptest.NewRecorder()
 
 	// OK
-	as.EXPECT().Delete(&deleteActor).Return(nil)
-	actorHandler.Delete(w, req)
+	as.EXPECT().Patch(&patchActor).Return(nil)
+	actorHandler.Patch(w, req)
 
 	if w.Code != http.StatusOK {
 		t.Errorf("expected 200, got: %d", w.Code)
 	}
 
+	// Incorrect ID
+	req = httptest.NewRequest("PATCH", "/actors/abc", strings.NewReader(`{"actor_name": "name"}`))
+	req.Header.Add("If-Match", `"1"`)
+	req.Header.Add("Content-type", "application/merge-patch+json")
+	w = httptest.NewRecorder()
+	actorHandler.Patch(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got: %d", w.Code)
+	}
+
 	// Missed Content-Type
-	req.Header.Del("Content-type")
+	req = httptest.NewRequest("PATCH", "/actors/1", strings.NewReader(`{"actor_name": "name"}`))
+	req.Header.Add("If-Match", `"1"`)
 	w = httptest.NewRecorder()
-	actorHandler.Delete(w, req)
+	actorHandler.Patch(w, req)
 
 	if w.Code != http.StatusBadRequest {
 		t.Errorf("expected 400, got: %d", w.Code)
 	}
 
-	// Incorrect JSON
-	req = httptest.NewRequest("DELETE", "/actor", strings.NewReader(`{"actor_id": {`))
-	req.Header.Add("Content-type", "application/json")
+	// OK. Null clears the date of birth
+	req = httptest.NewRequest("PATCH", "/actors/1", strings.NewReader(`{"date_of_birth": null}`))
+	req.Header.Add("If-Match", `"1"`)
+	req.Header.Add("Content-type", "application/merge-patch+json")
 	w = httptest.NewRecorder()
 
-	actorHandler.Delete(w, req)
+	as.EXPECT().Patch(&domain.PatchActor{ID: 1, Version: 1, NullDateBirth: true}).Return(nil)
+	actorHandler.Patch(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got: %d", w.Code)
+	}
+
+	// Null member that can't be cleared
+	req = httptest.NewRequest("PATCH", "/actors/1", strings.NewReader(`{"gender": null}`))
+	req.Header.Add("If-Match", `"1"`)
+	req.Header.Add("Content-type", "application/merge-patch+json")
+	w = httptest.NewRecorder()
+	actorHandler.Patch(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got: %d", w.Code)
+	}
+
+	// Unknown member
+	req = httptest.NewRequest("PATCH", "/actors/1", strings.NewReader(`{"age": 30}`))
+	req.Header.Add("If-Match", `"1"`)
+	req.Header.Add("Content-type", "application/merge-patch+json")
+	w = httptest.NewRecorder()
+	actorHandler.Patch(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got: %d", w.Code)
+	}
+
+	// io.ReadAll returned error
+	req = httptest.NewRequest("PATCH", "/actors/1", &BadReader{})
+	req.Header.Add("If-Match", `"1"`)
+	req.Header.Add("Content-type", "application/merge-patch+json")
+	w = httptest.NewRecorder()
+	actorHandler.Patch(w, req)
 
 	if w.Code != http.StatusInternalServerError {
 		t.Errorf("expected 500, got: %d", w.Code)
 	}
 
-	// io.ReadAll returned error
-	req = httptest.NewRequest("DELETE", "/actor", &BadReader{})
-	req.Header.Add("Content-type", "application/json")
+	// Not found
+	req = httptest.NewRequest("PATCH", "/actors/1", strings.NewReader(`{"actor_name": "name"}`))
+	req.Header.Add("If-Match", `"1"`)
+	req.Header.Add("Content-type", "application/merge-patch+json")
 	w = httptest.NewRecorder()
 
-	actorHandler.Delete(w, req)
+	as.EXPECT().Patch(&patchActor).Return(domain.ErrNotFound)
+	actorHandler.Patch(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got: %d", w.Code)
+	}
+
+	// Patch returned error
+	req = httptest.NewRequest("PATCH", "/actors/1", strings.NewReader(`{"actor_name": "name"}`))
+	req.Header.Add("If-Match", `"1"`)
+	req.Header.Add("Content-type", "application/merge-patch+json")
+	w = httptest.NewRecorder()
+
+	as.EXPECT().Patch(&patchActor).Return(domain.ErrTest)
+	actorHandler.Patch(w, req)
 
 	if w.Code != http.StatusInternalServerError {
 		t.Errorf("expected 500, got: %d", w.Code)
 	}
+}
+
+func TestActorDelete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	as := mocks.NewMockActorService(ctrl)
+
+	logger, err := logger.NewLogger()
+	if err != nil {
+		t.Fatalf("can't create logger: %s", err)
+	}
+
+	actorHandler := NewActorController(logger, as)
+
+	deleteActor := domain.DeleteActor{
+		ID:      1,
+		Version: 1,
+	}
+
+	req := httptest.NewRequest("DELETE", "/actors/1", nil)
+	req.Header.Add("If-Match", `"1"`)
+	w := httptest.NewRecorder()
+
+	// OK
+	as.EXPECT().Delete(&deleteActor).Return(nil)
+	actorHandler.Delete(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got: %d", w.Code)
+	}
+
+	// Incorrect ID
+	req = httptest.NewRequest("DELETE", "/actors/-1", nil)
+	req.Header.Add("If-Match", `"1"`)
+	w = httptest.NewRecorder()
+
+	actorHandler.Delete(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got: %d", w.Code)
+	}
 
 	// Delete returned error
-	req = httptest.NewRequest("DELETE", "/actor", strings.NewReader(body))
-	req.Header.Add("Content-type", "application/json")
+	req = httptest.NewRequest("DELETE", "/actors/1", nil)
+	req.Header.Add("If-Match", `"1"`)
 	w = httptest.NewRecorder()
 
 	as.EXPECT().Delete(&deleteActor).Return(domain.ErrTest)
@@ -266,8 +387,28 @@ func TestActorDelete(t *testing.T) {
 		t.Errorf("expected 500, got: %d", w.Code)
 		return
 	}
-}
 
+	// Missed If-Match
+	req = httptest.NewRequest("DELETE", "/actors/1", nil)
+	w = httptest.NewRecorder()
+	actorHandler.Delete(w, req)
+
+	if w.Code != http.StatusPreconditionRequired {
+		t.Errorf("expected 428, got: %d", w.Code)
+	}
+
+	// Stale version
+	req = httptest.NewRequest("DELETE", "/actors/1", nil)
+	req.Header.Add("If-Match", `"1"`)
+	w = httptest.NewRecorder()
+
+	as.EXPECT().Delete(&deleteActor).Return(&domain.VersionConflictError{Resource: "actor", ID: 1, Version: 2})
+	actorHandler.Delete(w, req)
+
+	if w.Code != http.StatusPreconditionFailed {
+		t.Errorf("expected 412, got: %d", w.Code)
+	}
+}
 func TestActorGetList(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -281,22 +422,48 @@ func TestActorGetList(t *testing.T) {
 
 	actorHandler := NewActorController(logger, as)
 
-	actors := []domain.ActorWithMovie{}
+	getActorList := domain.GetActorList{
+		Page: domain.Page{Limit: 20},
+	}
 	req := httptest.NewRequest("GET", "/actor", nil)
 	w := httptest.NewRecorder()
 
 	// OK
-	as.EXPECT().GetList().Return(actors, nil)
+	as.EXPECT().GetList(&getActorList).Return(&domain.ActorList{}, nil)
 	actorHandler.GetList(w, req)
 
 	if w.Code != http.StatusOK {
 		t.Errorf("expected 200, got: %d", w.Code)
 	}
 
+	// OK. Page before the cursor
+	req = httptest.NewRequest("GET", "/actor?limit=5&before=cursor", nil)
+	w = httptest.NewRecorder()
+
+	as.EXPECT().GetList(&domain.GetActorList{
+		Page: domain.Page{Limit: 5, Before: "cursor"},
+	}).Return(&domain.ActorList{}, nil)
+	actorHandler.GetList(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got: %d", w.Code)
+	}
+
+	// Incorrect limit
+	req = httptest.NewRequest("GET", "/actor?limit=1000", nil)
+	w = httptest.NewRecorder()
+
+	actorHandler.GetList(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got: %d", w.Code)
+	}
+
 	// GetList returned error
+	req = httptest.NewRequest("GET", "/actor", nil)
 	w = httptest.NewRecorder()
 
-	as.EXPECT().GetList().Return(nil, domain.ErrTest)
+	as.EXPECT().GetList(&getActorList).Return(nil, domain.ErrTest)
 	actorHandler.GetList(w, req)
 
 	if w.Code != http.StatusInternalServerError {
@@ -333,8 +500,13 @@ func TestActorManagePath(t *testing.T) {
 	w = httptest.NewRecorder()
 	actorHandler.ManagePath(w, req)
 
-	if w.Code != http.StatusBadRequest {
-		t.Errorf("expected 200, got: %d", w.Code)
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got: %d", w.Code)
+		return
+	}
+
+	if allow := w.Header().Get("Allow"); allow != "GET, POST" {
+		t.Errorf("expected Allow: GET, POST, got: %s", allow)
 		return
 	}
 
@@ -343,13 +515,13 @@ func TestActorManagePath(t *testing.T) {
 	w = httptest.NewRecorder()
 	actorHandler.ManagePath(w, req)
 
-	if w.Code != http.StatusBadRequest {
-		t.Errorf("expected 200, got: %d", w.Code)
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got: %d", w.Code)
 		return
 	}
 
 	// GET
-	as.EXPECT().GetList().Return(nil, nil)
+	as.EXPECT().GetList(&domain.GetActorList{Page: domain.Page{Limit: 20}}).Return(nil, nil)
 	req = httptest.NewRequest("GET", "/actor", nil)
 	w = httptest.NewRecorder()
 
@@ -360,3 +532,184 @@ func TestActorManagePath(t *testing.T) {
 		return
 	}
 }
+
+func TestActorGet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	as := mocks.NewMockActorService(ctrl)
+
+	logger, err := logger.NewLogger()
+	if err != nil {
+		t.Fatalf("can't create logger: %s", err)
+	}
+
+	actorHandler := NewActorController(logger, as)
+
+	getActor := domain.GetActor{
+		ID: 1,
+	}
+
+	req := httptest.NewRequest("GET", "/actors/1", nil)
+	w := httptest.NewRecorder()
+
+	// OK
+	as.EXPECT().Get(&getActor).Return(&domain.Actor{ID: 1, Version: 2}, nil)
+	actorHandler.Get(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got: %d", w.Code)
+	}
+
+	if etag := w.Header().Get("ETag"); etag != `"2"` {
+		t.Errorf("expected ETag \"2\", got: %s", etag)
+	}
+
+	// Incorrect ID
+	req = httptest.NewRequest("GET", "/actors/abc", nil)
+	w = httptest.NewRecorder()
+
+	actorHandler.Get(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got: %d", w.Code)
+	}
+
+	// Actor not found
+	req = httptest.NewRequest("GET", "/actors/1", nil)
+	w = httptest.NewRecorder()
+
+	as.EXPECT().Get(&getActor).Return(nil, domain.ErrNotFound)
+	actorHandler.Get(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got: %d", w.Code)
+	}
+
+	// Get returned error
+	req = httptest.NewRequest("GET", "/actors/1", nil)
+	w = httptest.NewRecorder()
+
+	as.EXPECT().Get(&getActor).Return(nil, domain.ErrTest)
+	actorHandler.Get(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500, got: %d", w.Code)
+	}
+}
+
+func TestActorGetMovies(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	as := mocks.NewMockActorService(ctrl)
+
+	logger, err := logger.NewLogger()
+	if err != nil {
+		t.Fatalf("can't create logger: %s", err)
+	}
+
+	actorHandler := NewActorController(logger, as)
+
+	getActorMovies := domain.GetActorMovies{
+		ID: 1,
+	}
+
+	req := httptest.NewRequest("GET", "/actors/1/movies", nil)
+	w := httptest.NewRecorder()
+
+	// OK
+	as.EXPECT().GetMovies(&getActorMovies).Return([]domain.Movie{}, nil)
+	actorHandler.GetMovies(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got: %d", w.Code)
+	}
+
+	// GetMovies returned error
+	w = httptest.NewRecorder()
+
+	as.EXPECT().GetMovies(&getActorMovies).Return(nil, domain.ErrTest)
+	actorHandler.GetMovies(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500, got: %d", w.Code)
+	}
+}
+
+func TestActorManageItem(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	as := mocks.NewMockActorService(ctrl)
+
+	logger, err := logger.NewLogger()
+	if err != nil {
+		t.Fatalf("can't create logger: %s", err)
+	}
+
+	actorHandler := NewActorController(logger, as)
+
+	// GET
+	as.EXPECT().Get(&domain.GetActor{ID: 1}).Return(&domain.Actor{ID: 1}, nil)
+	req := httptest.NewRequest("GET", "/actors/1", nil)
+	w := httptest.NewRecorder()
+	actorHandler.ManageItem(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got: %d", w.Code)
+	}
+
+	// PUT without body
+	req = httptest.NewRequest("PUT", "/actors/1", nil)
+	req.Header.Add("If-Match", `"1"`)
+	w = httptest.NewRecorder()
+	actorHandler.ManageItem(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got: %d", w.Code)
+	}
+
+	// DELETE
+	as.EXPECT().Delete(&domain.DeleteActor{ID: 1, Version: 1}).Return(nil)
+	req = httptest.NewRequest("DELETE", "/actors/1", nil)
+	req.Header.Add("If-Match", `"1"`)
+	w = httptest.NewRecorder()
+	actorHandler.ManageItem(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got: %d", w.Code)
+	}
+
+	// GET movies
+	as.EXPECT().GetMovies(&domain.GetActorMovies{ID: 1}).Return(nil, nil)
+	req = httptest.NewRequest("GET", "/actors/1/movies", nil)
+	w = httptest.NewRecorder()
+	actorHandler.ManageItem(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got: %d", w.Code)
+	}
+
+	// Unsupported method
+	req = httptest.NewRequest("POST", "/actors/1", nil)
+	w = httptest.NewRecorder()
+	actorHandler.ManageItem(w, req)
+
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got: %d", w.Code)
+	}
+
+	if allow := w.Header().Get("Allow"); allow != "GET, PUT, PATCH, DELETE" {
+		t.Errorf("expected Allow: GET, PUT, PATCH, DELETE, got: %s", allow)
+	}
+
+	// Unknown path
+	req = httptest.NewRequest("GET", "/actors/1/unknown", nil)
+	w = httptest.NewRecorder()
+	actorHandler.ManageItem(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got: %d", w.Code)
+	}
+}
@@ -0,0 +1,198 @@
+package restapi
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+	"github.com/akrovv/filmlibrary/pkg/logger"
+	"github.com/akrovv/filmlibrary/pkg/sender"
+)
+
+type apiKeyController struct {
+	logger  logger.Logger
+	service APIKeyService
+}
+
+func NewAPIKeyController(logger logger.Logger, service APIKeyService) *apiKeyController {
+	return &apiKeyController{
+		logger:  logger,
+		service: service,
+	}
+}
+
+func (c *apiKeyController) ManagePath(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "POST":
+		c.Create(w, r)
+	case "GET":
+		c.List(w, r)
+	default:
+		methodNotAllowed(w, r, "GET", "POST")
+	}
+}
+
+func (c *apiKeyController) ManageItem(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "DELETE":
+		c.Delete(w, r)
+	default:
+		methodNotAllowed(w, r, "DELETE")
+	}
+}
+
+// @Summary Create
+// @Description  Create an API key of the user, sent as X-API-Key. The key is shown in this response only. Its scopes narrow down what the role of the user allows.
+// @Tags		 user
+// @Accept       json
+// @Produce      json
+// @Param request body domain.CreateAPIKey true "request"
+// @Success 201 {object} domain.CreatedAPIKey
+// @Failure 400 {object} sender.Problem
+// @Failure 401 {object} sender.Problem
+// @Failure 422 {object} sender.Problem
+// @Failure 500 {object} sender.Problem
+// @Router       /api-keys [post]
+func (c *apiKeyController) Create(w http.ResponseWriter, r *http.Request) {
+	user, err := contextUser(r)
+	if err != nil {
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	if r.Header.Get("Content-Type") != "application/json" {
+		c.logger.Infof("request didnt contain application/json")
+		ErrorJSON(w, r, errContentType)
+		return
+	}
+
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		c.logger.Infof("io.ReadAll error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+	defer r.Body.Close()
+
+	createAPIKeyDTO := domain.CreateAPIKey{}
+	if err = unmarshalRequest(data, &createAPIKeyDTO); err != nil {
+		c.logger.Infof("unmarshalRequest error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	createAPIKeyDTO.Username = user.Username
+
+	key, err := c.service.Create(&createAPIKeyDTO)
+	if err != nil {
+		c.logger.Infof("c.APIKeyService.Create error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	if err = sender.WriteJSON(w, http.StatusCreated, key); err != nil {
+		c.logger.Infof("WriteJSON %w", err)
+		return
+	}
+}
+
+// @Summary List
+// @Description  List the API keys of the user without the keys themselves. Admins may list the keys of anyone.
+// @Tags		 user
+// @Produce      json
+// @Param username query string false "Whose keys to list (admins only, default the current user)"
+// @Success 200 {object} domain.APIKeyList
+// @Failure 401 {object} sender.Problem
+// @Failure 403 {object} sender.Problem
+// @Failure 500 {object} sender.Problem
+// @Router       /api-keys [get]
+func (c *apiKeyController) List(w http.ResponseWriter, r *http.Request) {
+	user, err := contextUser(r)
+	if err != nil {
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	listAPIKeysDTO := domain.ListAPIKeys{
+		Username: user.Username,
+	}
+
+	if username := r.URL.Query().Get("username"); username != "" && username != user.Username {
+		if !user.IsAdmin {
+			ErrorJSON(w, r, fmt.Errorf("%w: api keys of other users are listed by admins only", domain.ErrForbidden))
+			return
+		}
+
+		listAPIKeysDTO.Username = username
+	}
+
+	list, err := c.service.List(&listAPIKeysDTO)
+	if err != nil {
+		c.logger.Infof("c.APIKeyService.List error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	if err = sender.WriteJSON(w, http.StatusOK, list); err != nil {
+		c.logger.Infof("WriteJSON %w", err)
+		return
+	}
+}
+
+// @Summary Delete
+// @Description  Revoke an API key of the user. Admins may revoke the keys of anyone.
+// @Tags		 user
+// @Produce      json
+// @Param id path int true "API key ID"
+// @Success 200 {object} sender.JSONResponse
+// @Failure 400 {object} sender.Problem
+// @Failure 401 {object} sender.Problem
+// @Failure 404 {object} sender.Problem
+// @Failure 500 {object} sender.Problem
+// @Router       /api-keys/{id} [delete]
+func (c *apiKeyController) Delete(w http.ResponseWriter, r *http.Request) {
+	user, err := contextUser(r)
+	if err != nil {
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	if len(pathParams(r, apiKeysPath)) != 1 {
+		ErrorJSON(w, r, domain.ErrNotFound)
+		return
+	}
+
+	id, err := pathID(r, apiKeysPath)
+	if err != nil {
+		c.logger.Infof("pathID error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	deleteAPIKeyDTO := domain.DeleteAPIKey{
+		ID: id,
+	}
+
+	if !user.IsAdmin {
+		deleteAPIKeyDTO.Username = user.Username
+	}
+
+	if err = c.service.Delete(&deleteAPIKeyDTO); err != nil {
+		c.logger.Infof("c.APIKeyService.Delete error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	if err = sender.WriteJSON(w, http.StatusOK, struct {
+		Status  string `json:"status"`
+		Message string `json:"message"`
+	}{
+		Status:  "OK",
+		Message: "api key was deleted",
+	}); err != nil {
+		c.logger.Infof("WriteJSON %w", err)
+		return
+	}
+}
@@ -0,0 +1,270 @@
+package restapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+	"github.com/akrovv/filmlibrary/internal/service/mocks"
+	"github.com/akrovv/filmlibrary/pkg/logger"
+	"github.com/golang/mock/gomock"
+)
+
+func TestCreateAPIKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	as := mocks.NewMockAPIKeyService(ctrl)
+
+	logger, err := logger.NewLogger()
+	if err != nil {
+		t.Fatalf("can't create logger: %s", err)
+	}
+
+	apiKeyHandler := NewAPIKeyController(logger, as)
+
+	user := &domain.User{Username: "user"}
+	body := []byte(`{"name": "ci import", "scopes": ["movies:write", "catalog:import"]}`)
+	dto := &domain.CreateAPIKey{Username: "user", Name: "ci import", Scopes: []string{"movies:write", "catalog:import"}}
+	created := &domain.CreatedAPIKey{
+		APIKey: domain.APIKey{ID: 1, Username: "user", Name: "ci import", Prefix: "flk_01234567", Scopes: dto.Scopes},
+		Key:    "flk_0123456789",
+	}
+
+	// OK. The key is shown once
+	req := httptest.NewRequest("POST", "/api-keys", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	as.EXPECT().Create(dto).Return(created, nil)
+	apiKeyHandler.ManagePath(w, withUser(req, user))
+
+	if w.Code != http.StatusCreated {
+		t.Errorf("expected 201, got: %d", w.Code)
+	}
+
+	if w.Header().Get("Cache-Control") != "no-store" {
+		t.Errorf("expected Cache-Control: no-store, got: %q", w.Header().Get("Cache-Control"))
+	}
+
+	got := &domain.CreatedAPIKey{}
+	if err = json.Unmarshal(w.Body.Bytes(), got); err != nil {
+		t.Fatalf("can't decode api key: %s", err)
+	}
+
+	if !reflect.DeepEqual(got, created) {
+		t.Errorf("expected: %+v, got: %+v", created, got)
+	}
+
+	// The owner comes from the context, not the body
+	req = httptest.NewRequest("POST", "/api-keys", bytes.NewReader([]byte(`{"username": "admin", "name": "ci import", "scopes": ["movies:write", "catalog:import"]}`)))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+
+	as.EXPECT().Create(dto).Return(created, nil)
+	apiKeyHandler.ManagePath(w, withUser(req, user))
+
+	if w.Code != http.StatusCreated {
+		t.Errorf("expected 201, got: %d", w.Code)
+	}
+
+	// Service returned error
+	req = httptest.NewRequest("POST", "/api-keys", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+
+	as.EXPECT().Create(dto).Return(nil, domain.ErrValidation)
+	apiKeyHandler.ManagePath(w, withUser(req, user))
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected 422, got: %d", w.Code)
+	}
+
+	// Bad JSON
+	req = httptest.NewRequest("POST", "/api-keys", bytes.NewReader([]byte(`{"name":`)))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	apiKeyHandler.ManagePath(w, withUser(req, user))
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got: %d", w.Code)
+	}
+
+	// Wrong Content-Type
+	req = httptest.NewRequest("POST", "/api-keys", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	apiKeyHandler.ManagePath(w, withUser(req, user))
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got: %d", w.Code)
+	}
+
+	// No user in the context
+	req = httptest.NewRequest("POST", "/api-keys", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	apiKeyHandler.ManagePath(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got: %d", w.Code)
+	}
+
+	// Wrong method
+	req = httptest.NewRequest("PUT", "/api-keys", nil)
+	w = httptest.NewRecorder()
+	apiKeyHandler.ManagePath(w, withUser(req, user))
+
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got: %d", w.Code)
+	}
+}
+
+func TestListAPIKeys(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	as := mocks.NewMockAPIKeyService(ctrl)
+
+	logger, err := logger.NewLogger()
+	if err != nil {
+		t.Fatalf("can't create logger: %s", err)
+	}
+
+	apiKeyHandler := NewAPIKeyController(logger, as)
+
+	user := &domain.User{Username: "user"}
+	admin := &domain.User{Username: "admin", IsAdmin: true}
+	list := &domain.APIKeyList{Keys: []domain.APIKey{
+		{ID: 1, Username: "user", Name: "ci import", Prefix: "flk_01234567", Scopes: []string{"movies:read"}},
+	}}
+
+	// OK. Own keys
+	req := httptest.NewRequest("GET", "/api-keys", nil)
+	w := httptest.NewRecorder()
+
+	as.EXPECT().List(&domain.ListAPIKeys{Username: "user"}).Return(list, nil)
+	apiKeyHandler.ManagePath(w, withUser(req, user))
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got: %d", w.Code)
+	}
+
+	got := &domain.APIKeyList{}
+	if err = json.Unmarshal(w.Body.Bytes(), got); err != nil {
+		t.Fatalf("can't decode api keys: %s", err)
+	}
+
+	if !reflect.DeepEqual(got, list) {
+		t.Errorf("expected: %+v, got: %+v", list, got)
+	}
+
+	// OK. Admin lists the keys of a user
+	req = httptest.NewRequest("GET", "/api-keys?username=user", nil)
+	w = httptest.NewRecorder()
+
+	as.EXPECT().List(&domain.ListAPIKeys{Username: "user"}).Return(list, nil)
+	apiKeyHandler.ManagePath(w, withUser(req, admin))
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got: %d", w.Code)
+	}
+
+	// User lists the keys of another user
+	req = httptest.NewRequest("GET", "/api-keys?username=admin", nil)
+	w = httptest.NewRecorder()
+	apiKeyHandler.ManagePath(w, withUser(req, user))
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected 403, got: %d", w.Code)
+	}
+
+	// Service returned error
+	req = httptest.NewRequest("GET", "/api-keys", nil)
+	w = httptest.NewRecorder()
+
+	as.EXPECT().List(&domain.ListAPIKeys{Username: "user"}).Return(nil, domain.ErrTest)
+	apiKeyHandler.ManagePath(w, withUser(req, user))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500, got: %d", w.Code)
+	}
+}
+
+func TestDeleteAPIKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	as := mocks.NewMockAPIKeyService(ctrl)
+
+	logger, err := logger.NewLogger()
+	if err != nil {
+		t.Fatalf("can't create logger: %s", err)
+	}
+
+	apiKeyHandler := NewAPIKeyController(logger, as)
+
+	user := &domain.User{Username: "user"}
+	admin := &domain.User{Username: "admin", IsAdmin: true}
+
+	// OK. Users revoke their own keys only
+	req := httptest.NewRequest("DELETE", "/api-keys/1", nil)
+	w := httptest.NewRecorder()
+
+	as.EXPECT().Delete(&domain.DeleteAPIKey{ID: 1, Username: "user"}).Return(nil)
+	apiKeyHandler.ManageItem(w, withUser(req, user))
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got: %d", w.Code)
+	}
+
+	// OK. Admins revoke any key
+	w = httptest.NewRecorder()
+
+	as.EXPECT().Delete(&domain.DeleteAPIKey{ID: 1}).Return(nil)
+	apiKeyHandler.ManageItem(w, withUser(req, admin))
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got: %d", w.Code)
+	}
+
+	// Key not found
+	w = httptest.NewRecorder()
+
+	as.EXPECT().Delete(&domain.DeleteAPIKey{ID: 1, Username: "user"}).Return(domain.ErrNotFound)
+	apiKeyHandler.ManageItem(w, withUser(req, user))
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got: %d", w.Code)
+	}
+
+	// Bad ID
+	req = httptest.NewRequest("DELETE", "/api-keys/abc", nil)
+	w = httptest.NewRecorder()
+	apiKeyHandler.ManageItem(w, withUser(req, user))
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got: %d", w.Code)
+	}
+
+	// No key ID
+	req = httptest.NewRequest("DELETE", "/api-keys/", nil)
+	w = httptest.NewRecorder()
+	apiKeyHandler.ManageItem(w, withUser(req, user))
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got: %d", w.Code)
+	}
+
+	// Wrong method
+	req = httptest.NewRequest("GET", "/api-keys/1", nil)
+	w = httptest.NewRecorder()
+	apiKeyHandler.ManageItem(w, withUser(req, user))
+
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got: %d", w.Code)
+	}
+}
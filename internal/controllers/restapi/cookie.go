@@ -0,0 +1,88 @@
+package restapi
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const sessionCookie = "session-id"
+
+// SessionCookie sets and clears the session-id cookie with the attributes
+// from the config.
+type SessionCookie struct {
+	Domain   string
+	Path     string
+	Secure   bool
+	HttpOnly bool
+	SameSite http.SameSite
+}
+
+// NewSessionCookie parses sameSite, one of lax, strict or none, lax when it
+// is left out. Browsers drop SameSite=None cookies without Secure, so that
+// pair is refused.
+func NewSessionCookie(domain, path string, secure, httpOnly bool, sameSite string) (*SessionCookie, error) {
+	cookie := SessionCookie{
+		Domain:   domain,
+		Path:     path,
+		Secure:   secure,
+		HttpOnly: httpOnly,
+	}
+
+	switch strings.ToLower(sameSite) {
+	case "", "lax":
+		cookie.SameSite = http.SameSiteLaxMode
+	case "strict":
+		cookie.SameSite = http.SameSiteStrictMode
+	case "none":
+		if !secure {
+			return nil, fmt.Errorf("session cookie with SameSite=None must be secure")
+		}
+		cookie.SameSite = http.SameSiteNoneMode
+	default:
+		return nil, fmt.Errorf("unknown SameSite mode %q, want lax, strict or none", sameSite)
+	}
+
+	if cookie.Path == "" {
+		cookie.Path = "/"
+	}
+
+	return &cookie, nil
+}
+
+func (c *SessionCookie) cookie(value string) *http.Cookie {
+	return &http.Cookie{
+		Name:     sessionCookie,
+		Value:    value,
+		Domain:   c.Domain,
+		Path:     c.Path,
+		Secure:   c.Secure,
+		HttpOnly: c.HttpOnly,
+		SameSite: c.SameSite,
+	}
+}
+
+// Set hands the token to the client until expires.
+func (c *SessionCookie) Set(w http.ResponseWriter, token string, expires time.Time) {
+	cookie := c.cookie(token)
+	cookie.Expires = expires
+	http.SetCookie(w, cookie)
+}
+
+// Clear tells the client to forget the token.
+func (c *SessionCookie) Clear(w http.ResponseWriter) {
+	cookie := c.cookie("")
+	cookie.MaxAge = -1
+	http.SetCookie(w, cookie)
+}
+
+// Token returns the token from the session-id cookie.
+func (c *SessionCookie) Token(r *http.Request) (string, error) {
+	cookie, err := r.Cookie(sessionCookie)
+	if err != nil {
+		return "", err
+	}
+
+	return cookie.Value, nil
+}
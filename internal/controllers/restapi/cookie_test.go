@@ -0,0 +1,36 @@
+package restapi
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestNewSessionCookie(t *testing.T) {
+	cookie, err := NewSessionCookie("example.com", "", false, true, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if cookie.SameSite != http.SameSiteLaxMode || cookie.Path != "/" || cookie.Domain != "example.com" {
+		t.Errorf("expected lax cookie for / of example.com, got: %+v", cookie)
+	}
+
+	cookie, err = NewSessionCookie("", "/api", true, true, "None")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if cookie.SameSite != http.SameSiteNoneMode || cookie.Path != "/api" {
+		t.Errorf("expected SameSite=None cookie for /api, got: %+v", cookie)
+	}
+
+	// SameSite=None without Secure
+	if _, err = NewSessionCookie("", "/", false, true, "none"); err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	// Unknown SameSite mode
+	if _, err = NewSessionCookie("", "/", true, true, "loose"); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
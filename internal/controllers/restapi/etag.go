@@ -0,0 +1,42 @@
+package restapi
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+)
+
+// etag formats a row version as a strong entity tag.
+func etag(version int64) string {
+	return strconv.Quote(strconv.FormatInt(version, 10))
+}
+
+// ifMatch returns the version a write to the resource with the given ID is
+// conditional on, taken from the If-Match header. "*" gives 0, which matches
+// any version. Only a single strong tag can match, as that is all the ETag we
+// send can be, so anything else fails the precondition.
+func ifMatch(r *http.Request, resource string, id int64) (int64, error) {
+	value := strings.TrimSpace(r.Header.Get("If-Match"))
+	if value == "" {
+		return 0, fmt.Errorf("%w: If-Match header is required", domain.ErrPreconditionRequired)
+	}
+
+	if value == "*" {
+		return 0, nil
+	}
+
+	tag, err := strconv.Unquote(value)
+	if err != nil || !strings.HasPrefix(value, `"`) {
+		return 0, &domain.VersionConflictError{Resource: resource, ID: id}
+	}
+
+	version, err := strconv.ParseInt(tag, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, &domain.VersionConflictError{Resource: resource, ID: id}
+	}
+
+	return version, nil
+}
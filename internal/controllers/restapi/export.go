@@ -0,0 +1,86 @@
+package restapi
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/akrovv/filmlibrary/pkg/catalog"
+	"github.com/akrovv/filmlibrary/pkg/logger"
+)
+
+type exportController struct {
+	logger  logger.Logger
+	service ExportService
+}
+
+func NewExportController(logger logger.Logger, service ExportService) *exportController {
+	return &exportController{
+		logger:  logger,
+		service: service,
+	}
+}
+
+// @Summary Export
+// @Description  Stream the whole catalogue: a manifest with the schema version and row counts, every actor, then every movie with its cast. The output can be sent back to /import as it is.
+// @Tags		 import
+// @Produce      application/x-ndjson
+// @Produce      json
+// @Produce      text/csv
+// @Param format query string false "ndjson (default), json or csv"
+// @Success 200 {string} string "Export file"
+// @Failure 400 {object} sender.Problem
+// @Failure 401 {object} sender.Problem
+// @Failure 403 {object} sender.Problem
+// @Failure 500 {object} sender.Problem
+// @Router       /export [get]
+func (c *exportController) Export(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		methodNotAllowed(w, r, "GET")
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = catalog.NDJSON
+	}
+
+	out := &exportWriter{ResponseWriter: w, format: format}
+	encoder, err := catalog.NewEncoder(out, format)
+	if err != nil {
+		c.logger.Infof("catalog.NewEncoder error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	if err = c.service.Export(encoder); err == nil {
+		err = encoder.Close()
+	}
+	if err != nil {
+		c.logger.Infof("c.ExportService.Export error: %w", err)
+		// Once the export has started the status is sent, the client is
+		// left with a file whose counts don't match the manifest.
+		if !out.started {
+			ErrorJSON(w, r, err)
+		}
+		return
+	}
+}
+
+// exportWriter sends the headers of the file with its first bytes, so that
+// an error before them can still be sent as a problem.
+type exportWriter struct {
+	http.ResponseWriter
+	format  string
+	started bool
+}
+
+func (w *exportWriter) Write(data []byte) (int, error) {
+	if !w.started {
+		w.started = true
+		w.Header().Set("Content-Type", catalog.ContentType(w.format))
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="filmlibrary.%s"`, w.format))
+		w.WriteHeader(http.StatusOK)
+	}
+
+	return w.ResponseWriter.Write(data)
+}
@@ -0,0 +1,163 @@
+package restapi
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+	"github.com/akrovv/filmlibrary/internal/service/mocks"
+	"github.com/akrovv/filmlibrary/pkg/logger"
+	"github.com/golang/mock/gomock"
+)
+
+func TestExport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	es := mocks.NewMockExportService(ctrl)
+	is := mocks.NewMockImportService(ctrl)
+
+	logger, err := logger.NewLogger()
+	if err != nil {
+		t.Fatalf("can't create logger: %s", err)
+	}
+
+	exportHandler := NewExportController(logger, es)
+	importHandler := NewImportController(logger, is, 0)
+
+	releaseDate := time.Date(1995, 12, 15, 0, 0, 0, 0, time.UTC)
+	dateBirth := time.Date(1940, 4, 25, 0, 0, 0, 0, time.UTC)
+
+	pacino := domain.ExportActor{ID: 1, ExternalID: "nm0000199", Name: "Al Pacino", Gender: "Male", DateBirth: dateBirth}
+	deNiro := domain.ExportActor{ID: 2, Name: "Robert De Niro", Gender: "Male"}
+	keaton := domain.ExportActor{ID: 3, Name: "Diane Keaton", Gender: "Female"}
+
+	export := func(w domain.ExportWriter) error {
+		steps := []error{
+			w.WriteManifest(&domain.ExportManifest{SchemaVersion: 4, Movies: 3, Actors: 3, MovieActors: 2}),
+			w.WriteActor(&pacino),
+			w.WriteActor(&deNiro),
+			w.WriteActor(&keaton),
+			w.WriteMovie(&domain.ExportMovie{ID: 1, ExternalID: "tt0113277", Title: "Heat", Description: "L.A., \"1995\"",
+				ReleaseDate: releaseDate, Rating: 8, Actors: []domain.ExportActor{pacino, deNiro}}),
+			w.WriteMovie(&domain.ExportMovie{ID: 2, Title: "Ronin", Rating: 7, Actors: []domain.ExportActor{}}),
+			w.WriteMovie(&domain.ExportMovie{ID: 3, Title: "Ronin", Rating: 7, Actors: []domain.ExportActor{}}),
+		}
+		for _, err := range steps {
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	importActor := func(a domain.ExportActor) domain.ImportActor {
+		return domain.ImportActor{ExternalID: a.ExternalID, Name: a.Name, Gender: a.Gender, DateBirth: a.DateBirth}
+	}
+
+	expectedMovies := []domain.ImportMovie{
+		{ExternalID: "tt0113277", Title: "Heat", Description: "L.A., \"1995\"", ReleaseDate: releaseDate, Rating: 8,
+			Actors: []domain.ImportActor{importActor(pacino), importActor(deNiro)}},
+		{Title: "Ronin", Rating: 7, Actors: []domain.ImportActor{}},
+		{Title: "Ronin", Rating: 7, Actors: []domain.ImportActor{}},
+	}
+
+	// Every format is imported back as it was exported
+	for format, contentType := range map[string]string{
+		"ndjson": "application/x-ndjson",
+		"json":   "application/json",
+		"csv":    "text/csv; charset=utf-8",
+	} {
+		req := httptest.NewRequest("GET", "/export?format="+format, nil)
+		w := httptest.NewRecorder()
+
+		es.EXPECT().Export(gomock.Any()).DoAndReturn(export)
+		exportHandler.Export(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: expected 200, got: %d", format, w.Code)
+		}
+
+		if w.Header().Get("Content-Type") != contentType {
+			t.Errorf("%s: expected Content-Type %s, got: %s", format, contentType, w.Header().Get("Content-Type"))
+		}
+
+		req = httptest.NewRequest("POST", "/import?format="+format, bytes.NewReader(w.Body.Bytes()))
+		w = httptest.NewRecorder()
+
+		is.EXPECT().ImportMovies(gomock.Any()).DoAndReturn(func(dto *domain.ImportMovies) (*domain.ImportReport, error) {
+			if len(dto.Rows) != 6 {
+				t.Fatalf("%s: expected 6 rows, got: %+v", format, dto.Rows)
+			}
+
+			for i, actor := range []domain.ExportActor{pacino, deNiro, keaton} {
+				expected := importActor(actor)
+				if dto.Rows[i].Err != nil || !reflect.DeepEqual(dto.Rows[i].Actor, &expected) {
+					t.Errorf("%s: expected actor %+v, got: %+v", format, expected, dto.Rows[i])
+				}
+			}
+
+			for i, movie := range expectedMovies {
+				row := dto.Rows[i+3]
+				if row.Err != nil || row.Actor != nil || !reflect.DeepEqual(row.Movie, movie) {
+					t.Errorf("%s: expected movie %+v, got: %+v", format, movie, row)
+				}
+			}
+
+			return &domain.ImportReport{}, nil
+		})
+		importHandler.Import(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("%s: expected 200 on import, got: %d", format, w.Code)
+		}
+	}
+
+	// NDJSON by default, the manifest first
+	req := httptest.NewRequest("GET", "/export", nil)
+	w := httptest.NewRecorder()
+
+	es.EXPECT().Export(gomock.Any()).DoAndReturn(export)
+	exportHandler.Export(w, req)
+
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	if len(lines) != 7 || !strings.HasPrefix(lines[0], `{"type":"manifest","schema_version":4,`) {
+		t.Errorf("unexpected export: %s", w.Body.String())
+	}
+
+	// Unknown format
+	req = httptest.NewRequest("GET", "/export?format=xml", nil)
+	w = httptest.NewRecorder()
+
+	exportHandler.Export(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got: %d", w.Code)
+	}
+
+	// Failed before anything was sent
+	req = httptest.NewRequest("GET", "/export", nil)
+	w = httptest.NewRecorder()
+
+	es.EXPECT().Export(gomock.Any()).Return(domain.ErrTest)
+	exportHandler.Export(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500, got: %d", w.Code)
+	}
+
+	// Wrong method
+	req = httptest.NewRequest("POST", "/export", nil)
+	w = httptest.NewRecorder()
+
+	exportHandler.Export(w, req)
+
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got: %d", w.Code)
+	}
+}
@@ -0,0 +1,151 @@
+package restapi
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+)
+
+const maxYearBucket = 100
+
+// queryList splits a comma-separated query parameter, nil when it is absent.
+func queryList(r *http.Request, name string) []string {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return nil
+	}
+
+	items := strings.Split(value, ",")
+	for i := range items {
+		items[i] = strings.TrimSpace(items[i])
+	}
+
+	return items
+}
+
+// queryRange reads the bounds of a range, either of them may be left out.
+func queryRange(r *http.Request, from, to string, min, max int) (int, int, error) {
+	bounds := [2]int{}
+	for i, name := range []string{from, to} {
+		n, err := queryInt(r, name, 0)
+		if err == nil && r.URL.Query().Get(name) != "" && (n < min || n > max) {
+			err = domain.NewFieldError(domain.ErrRequest, name, fmt.Sprintf("must be between %d and %d", min, max))
+		}
+		if err != nil {
+			return 0, 0, err
+		}
+		bounds[i] = n
+	}
+
+	if bounds[0] != 0 && bounds[1] != 0 && bounds[0] > bounds[1] {
+		return 0, 0, domain.NewFieldError(domain.ErrRequest, from, "can't be greater than "+to)
+	}
+
+	return bounds[0], bounds[1], nil
+}
+
+// queryGenders reads a list of genders, matched regardless of case.
+func queryGenders(r *http.Request, name string) ([]string, error) {
+	var genders []string
+
+	for _, value := range queryList(r, name) {
+		known := ""
+		for _, gender := range domain.Genders {
+			if strings.EqualFold(value, gender) {
+				known = gender
+			}
+		}
+
+		if known == "" {
+			return nil, domain.NewFieldError(domain.ErrRequest, name, "must be a list of "+strings.Join(domain.Genders, ", "))
+		}
+
+		genders = append(genders, known)
+	}
+
+	return genders, nil
+}
+
+// queryMovieFilter reads the filters of a movie listing.
+func queryMovieFilter(r *http.Request) (domain.MovieFilter, error) {
+	filter := domain.MovieFilter{}
+
+	var err error
+	if filter.FromYear, filter.ToYear, err = queryRange(r, "from_year", "to_year", 1, 9999); err != nil {
+		return filter, err
+	}
+
+	minRating, maxRating, err := queryRange(r, "min_rating", "max_rating", domain.MinRating, domain.MaxRating)
+	if err != nil {
+		return filter, err
+	}
+	filter.MinRating, filter.MaxRating = uint8(minRating), uint8(maxRating)
+
+	seen := make(map[int64]bool)
+	for _, value := range queryList(r, "actors") {
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || id <= 0 {
+			return filter, domain.NewFieldError(domain.ErrRequest, "actors", "must be a list of actor IDs")
+		}
+
+		if !seen[id] {
+			filter.ActorIDs = append(filter.ActorIDs, id)
+			seen[id] = true
+		}
+	}
+
+	switch r.URL.Query().Get("actors_match") {
+	case "", "any":
+	case "all":
+		filter.AllActors = true
+	default:
+		return filter, domain.NewFieldError(domain.ErrRequest, "actors_match", "must be any or all")
+	}
+
+	if filter.WithGenders, err = queryGenders(r, "with_gender"); err != nil {
+		return filter, err
+	}
+	if filter.WithoutGenders, err = queryGenders(r, "without_gender"); err != nil {
+		return filter, err
+	}
+
+	filter.Query = strings.TrimSpace(r.URL.Query().Get("q"))
+	if utf8.RuneCountInString(filter.Query) > domain.MaxSearchQueryLength {
+		return filter, domain.NewFieldError(domain.ErrRequest, "q", fmt.Sprintf("must be at most %d characters", domain.MaxSearchQueryLength))
+	}
+
+	if filter.Query != "" {
+		if filter.Language, err = queryLanguage(r); err != nil {
+			return filter, err
+		}
+	}
+
+	return filter, nil
+}
+
+// queryFacets reads whether a listing asks for its facets and their year
+// bucket width, which is zero when it doesn't.
+func queryFacets(r *http.Request) (bool, int, error) {
+	facets, err := queryBool(r, "facets")
+	if err != nil {
+		return false, 0, err
+	}
+
+	bucket, err := queryInt(r, "year_bucket", domain.DefaultYearBucket)
+	if err != nil {
+		return false, 0, err
+	}
+	if bucket == 0 || bucket > maxYearBucket {
+		return false, 0, domain.NewFieldError(domain.ErrRequest, "year_bucket", fmt.Sprintf("must be between 1 and %d", maxYearBucket))
+	}
+
+	if !facets {
+		return false, 0, nil
+	}
+
+	return true, bucket, nil
+}
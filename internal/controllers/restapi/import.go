@@ -0,0 +1,124 @@
+package restapi
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+	"github.com/akrovv/filmlibrary/pkg/catalog"
+	"github.com/akrovv/filmlibrary/pkg/logger"
+	"github.com/akrovv/filmlibrary/pkg/sender"
+)
+
+const (
+	DefaultMaxImportSize = 64 << 20
+	defaultBatchSize     = 100
+	maxBatchSize         = 1000
+)
+
+type importController struct {
+	logger  logger.Logger
+	service ImportService
+	maxSize int64
+}
+
+// NewImportController accepts files of up to maxSize bytes, the whole file
+// is held in memory. A zero maxSize is DefaultMaxImportSize.
+func NewImportController(logger logger.Logger, service ImportService, maxSize int64) *importController {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxImportSize
+	}
+
+	return &importController{
+		logger:  logger,
+		service: service,
+		maxSize: maxSize,
+	}
+}
+
+// @Summary Import
+// @Description  Import movies with their cast from a CSV, JSON array or NDJSON file. Movies are matched by external ID or else by title and release date, actors by external ID or else by name, and the missing ones are created. Rows that fail are reported without stopping the import. Files are limited to IMPORT_MAX_SIZE MB, 64 by default.
+// @Tags		 import
+// @Accept       json
+// @Accept       text/csv
+// @Accept       application/x-ndjson
+// @Produce      json
+// @Param format query string false "csv, json or ndjson, taken from Content-Type if left out"
+// @Param dry_run query bool false "Roll everything back and only report what would happen"
+// @Param batch_size query int false "Rows per transaction, 100 by default"
+// @Param request body string true "Import file"
+// @Success 200 {object} domain.ImportReport
+// @Failure 400 {object} sender.Problem
+// @Failure 401 {object} sender.Problem
+// @Failure 403 {object} sender.Problem
+// @Failure 500 {object} sender.Problem
+// @Router       /import [post]
+func (c *importController) Import(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		methodNotAllowed(w, r, "POST")
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = catalog.FormatOf(r.Header.Get("Content-Type"), "")
+	}
+
+	dryRun, err := queryBool(r, "dry_run")
+	if err != nil {
+		c.logger.Infof("queryBool error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	batchSize, err := queryInt(r, "batch_size", defaultBatchSize)
+	if err == nil && (batchSize == 0 || batchSize > maxBatchSize) {
+		err = domain.NewFieldError(domain.ErrRequest, "batch_size", fmt.Sprintf("must be between 1 and %d", maxBatchSize))
+	}
+	if err != nil {
+		c.logger.Infof("queryInt error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, c.maxSize))
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		err = fmt.Errorf("%w: the file is larger than %d MB, import it with filmctl import or raise IMPORT_MAX_SIZE",
+			domain.ErrRequest, c.maxSize>>20)
+	}
+	if err != nil {
+		c.logger.Infof("io.ReadAll error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+	defer r.Body.Close()
+
+	rows, err := catalog.DecodeMovies(bytes.NewReader(data), format)
+	if err != nil {
+		c.logger.Infof("catalog.DecodeMovies error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	importDTO := domain.ImportMovies{
+		Rows:      rows,
+		BatchSize: batchSize,
+		DryRun:    dryRun,
+	}
+
+	report, err := c.service.ImportMovies(&importDTO)
+	if err != nil {
+		c.logger.Infof("c.ImportService.ImportMovies error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	if err = sender.WriteJSON(w, http.StatusOK, report); err != nil {
+		c.logger.Infof("WriteJSON %w", err)
+		return
+	}
+}
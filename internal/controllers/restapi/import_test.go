@@ -0,0 +1,221 @@
+package restapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+	"github.com/akrovv/filmlibrary/internal/service/mocks"
+	"github.com/akrovv/filmlibrary/pkg/logger"
+	"github.com/akrovv/filmlibrary/pkg/sender"
+	"github.com/golang/mock/gomock"
+)
+
+func TestImport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	is := mocks.NewMockImportService(ctrl)
+
+	logger, err := logger.NewLogger()
+	if err != nil {
+		t.Fatalf("can't create logger: %s", err)
+	}
+
+	importHandler := NewImportController(logger, is, 0)
+
+	report := &domain.ImportReport{
+		Created: 1,
+		Rows:    []domain.ImportResult{{Row: 2, Title: "Heat", Status: domain.ImportCreated}},
+	}
+
+	// CSV, lines of the same movie are merged
+	body := "external_id,movie_title,release_date,rating,actor_name,gender,date_of_birth\n" +
+		"tt0113277,Heat,1995-12-15,8,Al Pacino,Male,1940-04-25\n" +
+		"tt0113277,Heat,1995-12-15,8,Robert De Niro,Male,\n" +
+		",Ronin,1998-09-25,x,,,\n"
+
+	expectedDTO := domain.ImportMovies{
+		Rows: []domain.ImportRow{
+			{
+				Row: 2,
+				Movie: domain.ImportMovie{
+					ExternalID:  "tt0113277",
+					Title:       "Heat",
+					ReleaseDate: time.Date(1995, 12, 15, 0, 0, 0, 0, time.UTC),
+					Rating:      8,
+					Actors: []domain.ImportActor{
+						{Name: "Al Pacino", Gender: "Male", DateBirth: time.Date(1940, 4, 25, 0, 0, 0, 0, time.UTC)},
+						{Name: "Robert De Niro", Gender: "Male"},
+					},
+				},
+			},
+		},
+		BatchSize: 10,
+		DryRun:    true,
+	}
+
+	req := httptest.NewRequest("POST", "/import?dry_run=true&batch_size=10", strings.NewReader(body))
+	req.Header.Add("Content-Type", "text/csv")
+	w := httptest.NewRecorder()
+
+	is.EXPECT().ImportMovies(gomock.Any()).DoAndReturn(func(dto *domain.ImportMovies) (*domain.ImportReport, error) {
+		if len(dto.Rows) != 2 {
+			t.Fatalf("expected 2 rows, got: %d", len(dto.Rows))
+		}
+
+		if dto.Rows[1].Row != 4 || !errors.Is(dto.Rows[1].Err, domain.ErrRequest) {
+			t.Errorf("expected row 4 to fail, got: %+v", dto.Rows[1])
+		}
+
+		dto.Rows = dto.Rows[:1]
+		if !reflect.DeepEqual(dto, &expectedDTO) {
+			t.Errorf("expected: %+v, got: %+v", expectedDTO, dto)
+		}
+
+		return report, nil
+	})
+	importHandler.Import(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got: %d", w.Code)
+	}
+
+	got := domain.ImportReport{}
+	if err = json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("can't decode report: %s", err)
+	}
+
+	if !reflect.DeepEqual(&got, report) {
+		t.Errorf("expected: %+v, got: %+v", report, got)
+	}
+
+	// NDJSON, a line that doesn't decode fails alone
+	body = `{"movie_title": "Heat", "release_date": "1995-12-15", "rating": 8, "actors": [{"external_id": "nm0000199"}]}` + "\n" +
+		"\n" +
+		`{"movie_title": "Ronin", "rating": "high"}` + "\n"
+
+	req = httptest.NewRequest("POST", "/import?format=ndjson", strings.NewReader(body))
+	w = httptest.NewRecorder()
+
+	is.EXPECT().ImportMovies(gomock.Any()).DoAndReturn(func(dto *domain.ImportMovies) (*domain.ImportReport, error) {
+		if len(dto.Rows) != 2 || dto.BatchSize != defaultBatchSize || dto.DryRun {
+			t.Fatalf("unexpected import: %+v", dto)
+		}
+
+		if dto.Rows[0].Err != nil || dto.Rows[0].Movie.Actors[0].ExternalID != "nm0000199" {
+			t.Errorf("unexpected first row: %+v", dto.Rows[0])
+		}
+
+		if dto.Rows[1].Row != 3 || dto.Rows[1].Err == nil {
+			t.Errorf("expected line 3 to fail, got: %+v", dto.Rows[1])
+		}
+
+		return report, nil
+	})
+	importHandler.Import(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got: %d", w.Code)
+	}
+
+	// JSON array
+	body = `[{"movie_title": "Heat", "release_date": "1995-12-15T00:00:00Z", "rating": 8}, {"movie_title": "Ronin", "release_date": "25.09.1998"}]`
+
+	req = httptest.NewRequest("POST", "/import", strings.NewReader(body))
+	req.Header.Add("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+
+	is.EXPECT().ImportMovies(gomock.Any()).DoAndReturn(func(dto *domain.ImportMovies) (*domain.ImportReport, error) {
+		if len(dto.Rows) != 2 || dto.Rows[0].Err != nil || dto.Rows[1].Err == nil {
+			t.Errorf("expected the second movie to fail, got: %+v", dto.Rows)
+		}
+
+		return report, nil
+	})
+	importHandler.Import(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got: %d", w.Code)
+	}
+
+	// Broken JSON
+	req = httptest.NewRequest("POST", "/import", strings.NewReader(`[{"movie_title": `))
+	req.Header.Add("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+
+	importHandler.Import(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got: %d", w.Code)
+	}
+
+	// Unknown CSV column
+	req = httptest.NewRequest("POST", "/import?format=csv", strings.NewReader("movie_title,budget\nHeat,60000000\n"))
+	w = httptest.NewRecorder()
+
+	importHandler.Import(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got: %d", w.Code)
+	}
+
+	// Unknown format
+	req = httptest.NewRequest("POST", "/import", strings.NewReader(body))
+	req.Header.Add("Content-Type", "application/xml")
+	w = httptest.NewRecorder()
+
+	importHandler.Import(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got: %d", w.Code)
+	}
+
+	problem := sender.Problem{}
+	if err = json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("can't decode problem: %s", err)
+	}
+
+	if len(problem.Errors) != 1 || problem.Errors[0].Field != "format" {
+		t.Errorf("expected a format field error, got: %+v", problem.Errors)
+	}
+
+	// Invalid query parameters
+	for _, query := range []string{"dry_run=maybe", "batch_size=0", "batch_size=5000"} {
+		req = httptest.NewRequest("POST", "/import?format=json&"+query, strings.NewReader(body))
+		w = httptest.NewRecorder()
+
+		importHandler.Import(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got: %d", query, w.Code)
+		}
+	}
+
+	// ImportMovies returned error
+	req = httptest.NewRequest("POST", "/import?format=json", strings.NewReader(body))
+	w = httptest.NewRecorder()
+
+	is.EXPECT().ImportMovies(gomock.Any()).Return(nil, domain.ErrTest)
+	importHandler.Import(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500, got: %d", w.Code)
+	}
+
+	// GET
+	req = httptest.NewRequest("GET", "/import", nil)
+	w = httptest.NewRecorder()
+
+	importHandler.Import(w, req)
+
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got: %d", w.Code)
+	}
+}
@@ -9,22 +9,57 @@ type UserService interface {
 	Login(user *domain.CRUser) (*domain.User, error)
 }
 
+type TokenService interface {
+	Issue(dto *domain.TokenRequest) (*domain.TokenPair, error)
+	Authenticate(dto *domain.GetAccessToken) (*domain.User, error)
+}
+
 type SessionService interface {
-	Create(dto *domain.CreateSession) (string, error)
-	Get(dto *domain.GetSession) (*domain.User, error)
+	Create(dto *domain.CreateSession) (*domain.ActiveSession, error)
+	Get(dto *domain.GetSession) (*domain.ActiveSession, error)
+	List(dto *domain.ListSessions) (*domain.SessionList, error)
+	Delete(dto *domain.DeleteSession) error
 }
 
 type ActorService interface {
 	Create(dto *domain.CreateActor) error
 	Update(dto *domain.Actor) error
+	Patch(dto *domain.PatchActor) error
 	Delete(dto *domain.DeleteActor) error
-	GetList() ([]domain.ActorWithMovie, error)
+	GetList(dto *domain.GetActorList) (*domain.ActorList, error)
+	Get(dto *domain.GetActor) (*domain.Actor, error)
+	GetMovies(dto *domain.GetActorMovies) ([]domain.Movie, error)
 }
 
 type MovieService interface {
 	Create(dto *domain.CreateMovie) error
 	Update(dto *domain.Movie) error
+	Patch(dto *domain.PatchMovie) error
+	AddActor(dto *domain.MovieActor) error
+	RemoveActor(dto *domain.MovieActor) error
 	Delete(dto *domain.DeleteMovie) error
-	Get(dto *domain.GetMovie) (*domain.MovieWithoudID, error)
-	GetOrderedList(dto *domain.GetOrderedMovie) ([]domain.MovieWithoudID, error)
+	Get(dto *domain.GetMovie) (*domain.MoviePage, error)
+	Search(dto *domain.SearchMovies) (*domain.SearchPage, error)
+	GetOrderedList(dto *domain.GetOrderedMovie) (*domain.MovieList, error)
+	GetByID(dto *domain.GetMovieByID) (*domain.MovieWithActors, error)
+	GetActors(dto *domain.GetMovieActors) ([]domain.Actor, error)
+}
+
+type ImportService interface {
+	ImportMovies(dto *domain.ImportMovies) (*domain.ImportReport, error)
+}
+
+type ExportService interface {
+	Export(w domain.ExportWriter) error
+}
+
+type SuggestService interface {
+	Suggest(dto *domain.Suggest) (*domain.SuggestList, error)
+}
+
+type APIKeyService interface {
+	Create(dto *domain.CreateAPIKey) (*domain.CreatedAPIKey, error)
+	List(dto *domain.ListAPIKeys) (*domain.APIKeyList, error)
+	Delete(dto *domain.DeleteAPIKey) error
+	Authenticate(dto *domain.GetAPIKey) (*domain.ScopedUser, error)
 }
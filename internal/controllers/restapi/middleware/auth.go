@@ -3,22 +3,63 @@ package middleware
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/akrovv/filmlibrary/internal/controllers/restapi"
 	"github.com/akrovv/filmlibrary/internal/domain"
 )
 
-func Auth(next http.Handler, sessionService restapi.SessionService) http.Handler {
+// apiKeyHeader carries the API keys of the clients that don't log in, such
+// as the import jobs of CI.
+const apiKeyHeader = "X-API-Key"
+
+// Auth resolves the X-API-Key header, the Authorization: Bearer access token
+// or else the session-id cookie into the user of the request. The scopes of
+// an API key go into the context too for Role to check. Every request with
+// the cookie renews the session and the cookie, which carries a new token
+// once the session was rotated.
+func Auth(next http.Handler,
+	sessionService restapi.SessionService,
+	tokenService restapi.TokenService,
+	apiKeyService restapi.APIKeyService,
+	cookie *restapi.SessionCookie) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		cookie, err := r.Cookie("session-id")
+		if key := r.Header.Get(apiKeyHeader); key != "" {
+			scoped, err := apiKeyService.Authenticate(&domain.GetAPIKey{Key: key})
+			if err != nil {
+				restapi.ErrorJSON(w, r, err)
+				return
+			}
+
+			var scopesContext domain.UserContext = "scopes"
+			ctx := context.WithValue(r.Context(), scopesContext, scoped.Scopes)
+			serveUser(next, w, r.WithContext(ctx), scoped.User)
+			return
+		}
+
+		if header := r.Header.Get("Authorization"); header != "" {
+			user, err := bearerUser(header, tokenService)
+			if err != nil {
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				restapi.ErrorJSON(w, r, err)
+				return
+			}
+
+			serveUser(next, w, r, user)
+			return
+		}
+
+		token, err := cookie.Token(r)
 		if err != nil && !errors.Is(err, http.ErrNoCookie) {
+			restapi.ErrorJSON(w, r, domain.ErrUnauthorized)
 			return
 		}
 
 		path := r.URL.Path
 		if err != nil {
-			if path == "/register" || path == "/login" || path == "/swagger.yaml" || path == "/docs" {
+			if path == "/register" || path == "/login" || path == "/token" || path == "/swagger.yaml" || path == "/docs" {
 				next.ServeHTTP(w, r)
 				return
 			}
@@ -27,18 +68,37 @@ func Auth(next http.Handler, sessionService restapi.SessionService) http.Handler
 			return
 		}
 
-		value := cookie.Value
 		getSessionDTO := domain.GetSession{
-			Username: value,
+			Token: token,
 		}
 
-		user, err := sessionService.Get(&getSessionDTO)
+		session, err := sessionService.Get(&getSessionDTO)
 		if err != nil {
+			if errors.Is(err, domain.ErrUnauthorized) {
+				cookie.Clear(w)
+			}
+			restapi.ErrorJSON(w, r, err)
 			return
 		}
 
-		var userContext domain.UserContext = "user"
-		ctx := context.WithValue(r.Context(), userContext, user)
-		next.ServeHTTP(w, r.WithContext(ctx))
+		if session.Token != "" {
+			cookie.Set(w, session.Token, session.ExpiresAt)
+		}
+		serveUser(next, w, r, session.User)
 	})
 }
+
+func bearerUser(header string, tokenService restapi.TokenService) (*domain.User, error) {
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return nil, fmt.Errorf("%w: Authorization must be Bearer with an access token", domain.ErrUnauthorized)
+	}
+
+	return tokenService.Authenticate(&domain.GetAccessToken{Token: strings.TrimSpace(token)})
+}
+
+func serveUser(next http.Handler, w http.ResponseWriter, r *http.Request, user *domain.User) {
+	var userContext domain.UserContext = "user"
+	ctx := context.WithValue(r.Context(), userContext, user)
+	next.ServeHTTP(w, r.WithContext(ctx))
+}
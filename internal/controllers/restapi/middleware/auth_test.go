@@ -0,0 +1,179 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/akrovv/filmlibrary/internal/controllers/restapi"
+	"github.com/akrovv/filmlibrary/internal/domain"
+	"github.com/akrovv/filmlibrary/internal/service/mocks"
+	"github.com/golang/mock/gomock"
+)
+
+func newTestCookie(t *testing.T) *restapi.SessionCookie {
+	cookie, err := restapi.NewSessionCookie("", "/", true, true, "strict")
+	if err != nil {
+		t.Fatalf("can't create session cookie: %s", err)
+	}
+
+	return cookie
+}
+
+// recorder is the next handler of a middleware, it keeps the user and the
+// scopes the request reached it with.
+type recorder struct {
+	called bool
+	user   *domain.User
+	scopes []string
+}
+
+func (h *recorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var (
+		userContext   domain.UserContext = "user"
+		scopesContext domain.UserContext = "scopes"
+	)
+
+	h.called = true
+	h.user, _ = r.Context().Value(userContext).(*domain.User)
+	h.scopes, _ = r.Context().Value(scopesContext).([]string)
+}
+
+func TestAuthAPIKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ss := mocks.NewMockSessionService(ctrl)
+	ts := mocks.NewMockTokenService(ctrl)
+	as := mocks.NewMockAPIKeyService(ctrl)
+
+	next := &recorder{}
+	handler := Auth(next, ss, ts, as, newTestCookie(t))
+
+	scoped := &domain.ScopedUser{
+		User:   &domain.User{Username: "ci", IsAdmin: true},
+		Scopes: []string{"movies:write", "catalog:import"},
+	}
+
+	// OK. The key goes before the bearer token and the cookie, which the
+	// session and token services would reject
+	req := httptest.NewRequest("POST", "/import", nil)
+	req.Header.Set("X-API-Key", "flk_key")
+	req.Header.Set("Authorization", "Bearer access")
+	req.AddCookie(&http.Cookie{Name: "session-id", Value: "token"})
+	w := httptest.NewRecorder()
+
+	as.EXPECT().Authenticate(&domain.GetAPIKey{Key: "flk_key"}).Return(scoped, nil)
+	handler.ServeHTTP(w, req)
+
+	if !next.called {
+		t.Fatalf("expected the request to pass, got: %d", w.Code)
+	}
+
+	if !reflect.DeepEqual(next.user, scoped.User) || !reflect.DeepEqual(next.scopes, scoped.Scopes) {
+		t.Errorf("expected user %+v with scopes %v, got: %+v with %v", scoped.User, scoped.Scopes, next.user, next.scopes)
+	}
+
+	// Revoked or unknown key
+	next = &recorder{}
+	handler = Auth(next, ss, ts, as, newTestCookie(t))
+	w = httptest.NewRecorder()
+
+	as.EXPECT().Authenticate(&domain.GetAPIKey{Key: "flk_key"}).Return(nil, domain.ErrUnauthorized)
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got: %d", w.Code)
+	}
+
+	if next.called {
+		t.Error("expected the request to stop")
+	}
+
+	// Postgres returned error
+	w = httptest.NewRecorder()
+
+	as.EXPECT().Authenticate(&domain.GetAPIKey{Key: "flk_key"}).Return(nil, domain.ErrTest)
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500, got: %d", w.Code)
+	}
+
+	// OK. Without a key the bearer token is used, and no scopes apply
+	req = httptest.NewRequest("GET", "/movie", nil)
+	req.Header.Set("Authorization", "Bearer access")
+	w = httptest.NewRecorder()
+
+	ts.EXPECT().Authenticate(&domain.GetAccessToken{Token: "access"}).Return(&domain.User{Username: "user"}, nil)
+	handler.ServeHTTP(w, req)
+
+	if !next.called || next.user.Username != "user" || next.scopes != nil {
+		t.Errorf("expected user without scopes, got: %+v with %v", next.user, next.scopes)
+	}
+}
+
+func TestAuthSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ss := mocks.NewMockSessionService(ctrl)
+	ts := mocks.NewMockTokenService(ctrl)
+	as := mocks.NewMockAPIKeyService(ctrl)
+
+	next := &recorder{}
+	handler := Auth(next, ss, ts, as, newTestCookie(t))
+
+	user := &domain.User{Username: "user"}
+	expires := time.Now().Add(time.Minute * 30)
+
+	// OK. The cookie is renewed with the token of the session
+	req := httptest.NewRequest("GET", "/movie", nil)
+	req.AddCookie(&http.Cookie{Name: "session-id", Value: "token"})
+	w := httptest.NewRecorder()
+
+	ss.EXPECT().Get(&domain.GetSession{Token: "token"}).Return(&domain.ActiveSession{User: user, Token: "rotated", ExpiresAt: expires}, nil)
+	handler.ServeHTTP(w, req)
+
+	if !next.called || next.user != user {
+		t.Errorf("expected user %+v, got: %+v", user, next.user)
+	}
+
+	if cookies := w.Result().Cookies(); len(cookies) != 1 || cookies[0].Value != "rotated" {
+		t.Errorf("expected the rotated token in the cookie, got: %v", cookies)
+	}
+
+	// OK. A session rotated by a parallel request leaves the cookie alone
+	next = &recorder{}
+	handler = Auth(next, ss, ts, as, newTestCookie(t))
+	w = httptest.NewRecorder()
+
+	ss.EXPECT().Get(&domain.GetSession{Token: "token"}).Return(&domain.ActiveSession{User: user, ExpiresAt: expires}, nil)
+	handler.ServeHTTP(w, req)
+
+	if !next.called || next.user != user {
+		t.Errorf("expected user %+v, got: %+v", user, next.user)
+	}
+
+	if cookies := w.Result().Cookies(); len(cookies) != 0 {
+		t.Errorf("expected no cookie, got: %v", cookies)
+	}
+
+	// Session expired
+	next = &recorder{}
+	handler = Auth(next, ss, ts, as, newTestCookie(t))
+	w = httptest.NewRecorder()
+
+	ss.EXPECT().Get(&domain.GetSession{Token: "token"}).Return(nil, domain.ErrUnauthorized)
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got: %d", w.Code)
+	}
+
+	if cookies := w.Result().Cookies(); len(cookies) != 1 || cookies[0].MaxAge >= 0 {
+		t.Errorf("expected the cookie to be cleared, got: %v", cookies)
+	}
+}
@@ -5,12 +5,13 @@ import (
 	"time"
 
 	"github.com/akrovv/filmlibrary/pkg/logger"
+	"github.com/akrovv/filmlibrary/pkg/sender"
 )
 
 func Logger(next http.Handler, logger logger.Logger) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		t := time.Now()
 		next.ServeHTTP(w, r)
-		logger.Infof("[%s] %s timeAnswer=%v", r.Method, r.URL.Path, time.Since(t))
+		logger.Infof("[%s] %s requestID=%s timeAnswer=%v", r.Method, r.URL.Path, w.Header().Get(sender.RequestIDHeader), time.Since(t))
 	})
 }
@@ -1,15 +1,23 @@
 package middleware
 
 import (
+	"fmt"
 	"net/http"
 
+	"github.com/akrovv/filmlibrary/internal/controllers/restapi"
 	"github.com/akrovv/filmlibrary/internal/domain"
 	"github.com/casbin/casbin/v2"
 )
 
-func Role(next http.Handler, enforcer *casbin.Enforcer) http.Handler {
+// Role lets through the requests the casbin role of the user allows. A
+// request made with an API key must also be allowed by one of the scopes of
+// the key, which scopeEnforcer checks with the scopes as subjects.
+func Role(next http.Handler, enforcer, scopeEnforcer *casbin.Enforcer) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		var userContext domain.UserContext = "user"
+		var (
+			userContext   domain.UserContext = "user"
+			scopesContext domain.UserContext = "scopes"
+		)
 
 		ctxUser := r.Context().Value(userContext)
 		sub := "anonymous"
@@ -27,15 +35,39 @@ func Role(next http.Handler, enforcer *casbin.Enforcer) http.Handler {
 		act := r.Method
 		ok, err := enforcer.Enforce(sub, obj, act)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			restapi.ErrorJSON(w, r, err)
 			return
 		}
 
 		if !ok {
-			w.WriteHeader(http.StatusForbidden)
+			restapi.ErrorJSON(w, r, fmt.Errorf("%w: %s %s is not allowed for %s", domain.ErrForbidden, act, obj, sub))
 			return
 		}
 
+		if scopes, ok := r.Context().Value(scopesContext).([]string); ok {
+			ok, err = scopeAllows(scopeEnforcer, scopes, obj, act)
+			if err != nil {
+				restapi.ErrorJSON(w, r, err)
+				return
+			}
+
+			if !ok {
+				restapi.ErrorJSON(w, r, fmt.Errorf("%w: %s %s is not in the scopes of the api key", domain.ErrForbidden, act, obj))
+				return
+			}
+		}
+
 		next.ServeHTTP(w, r)
 	})
 }
+
+func scopeAllows(enforcer *casbin.Enforcer, scopes []string, obj, act string) (bool, error) {
+	for _, scope := range scopes {
+		ok, err := enforcer.Enforce(scope, obj, act)
+		if err != nil || ok {
+			return ok, err
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+	"github.com/casbin/casbin/v2"
+)
+
+func newTestEnforcer(t *testing.T, policy string) *casbin.Enforcer {
+	enforcer, err := casbin.NewEnforcer("../../../../rbac_model.conf", "../../../../"+policy)
+	if err != nil {
+		t.Fatalf("can't create enforcer: %s", err)
+	}
+
+	return enforcer
+}
+
+func withScopedUser(r *http.Request, user *domain.User, scopes []string) *http.Request {
+	var (
+		userContext   domain.UserContext = "user"
+		scopesContext domain.UserContext = "scopes"
+	)
+
+	ctx := context.WithValue(r.Context(), userContext, user)
+	if scopes != nil {
+		ctx = context.WithValue(ctx, scopesContext, scopes)
+	}
+
+	return r.WithContext(ctx)
+}
+
+func TestRoleScopes(t *testing.T) {
+	enforcer := newTestEnforcer(t, "rbac_policy.csv")
+	scopeEnforcer := newTestEnforcer(t, "rbac_scopes.csv")
+
+	user := &domain.User{Username: "user"}
+	admin := &domain.User{Username: "admin", IsAdmin: true}
+
+	for _, test := range []struct {
+		name   string
+		user   *domain.User
+		scopes []string
+		method string
+		path   string
+		code   int
+	}{
+		{"scope allows", user, []string{"movies:read"}, "GET", "/movies/1", http.StatusOK},
+		{"one of the scopes allows", admin, []string{"actors:read", "catalog:import"}, "POST", "/import", http.StatusOK},
+		{"write scope reads", admin, []string{"movies:write"}, "GET", "/movie", http.StatusOK},
+		{"scope missing, role allows", admin, []string{"movies:read"}, "POST", "/movie", http.StatusForbidden},
+		{"scope of another resource", admin, []string{"actors:write"}, "DELETE", "/movies/1", http.StatusForbidden},
+		{"scope allows, role doesn't", user, []string{"catalog:import"}, "POST", "/import", http.StatusForbidden},
+		{"key manages keys", admin, []string{"movies:write"}, "GET", "/api-keys", http.StatusForbidden},
+		{"key without scopes", admin, []string{}, "GET", "/movie", http.StatusForbidden},
+		{"no key, role only", admin, nil, "POST", "/movie", http.StatusOK},
+	} {
+		next := &recorder{}
+		handler := Role(next, enforcer, scopeEnforcer)
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, withScopedUser(httptest.NewRequest(test.method, test.path, nil), test.user, test.scopes))
+
+		if w.Code != test.code {
+			t.Errorf("%s: expected %d, got: %d", test.name, test.code, w.Code)
+		}
+
+		if next.called != (test.code == http.StatusOK) {
+			t.Errorf("%s: expected the request to reach the handler: %v", test.name, test.code == http.StatusOK)
+		}
+	}
+}
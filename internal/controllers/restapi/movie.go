@@ -28,12 +28,55 @@ func (c *movieController) ManagePath(w http.ResponseWriter, r *http.Request) {
 	switch method {
 	case "POST":
 		c.Create(w, r)
-	case "PUT":
-		c.Update(w, r)
-	case "DELETE":
-		c.Delete(w, r)
 	case "GET":
 		c.Get(w, r)
+	default:
+		methodNotAllowed(w, r, "GET", "POST")
+	}
+}
+
+func (c *movieController) ManageItem(w http.ResponseWriter, r *http.Request) {
+	if _, err := pathID(r, moviesPath); err != nil {
+		c.logger.Infof("pathID error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	params := pathParams(r, moviesPath)
+	method := r.Method
+
+	switch {
+	case len(params) == 1:
+		switch method {
+		case "GET":
+			c.GetByID(w, r)
+		case "PUT":
+			c.Update(w, r)
+		case "PATCH":
+			c.Patch(w, r)
+		case "DELETE":
+			c.Delete(w, r)
+		default:
+			methodNotAllowed(w, r, "GET", "PUT", "PATCH", "DELETE")
+		}
+	case len(params) == 2 && params[1] == "actors":
+		switch method {
+		case "GET":
+			c.GetActors(w, r)
+		case "POST":
+			c.AddActor(w, r)
+		default:
+			methodNotAllowed(w, r, "GET", "POST")
+		}
+	case len(params) == 3 && params[1] == "actors":
+		switch method {
+		case "DELETE":
+			c.RemoveActor(w, r)
+		default:
+			methodNotAllowed(w, r, "DELETE")
+		}
+	default:
+		ErrorJSON(w, r, domain.ErrNotFound)
 	}
 }
 
@@ -44,35 +87,36 @@ func (c *movieController) ManagePath(w http.ResponseWriter, r *http.Request) {
 // @Produce      json
 // @Param request body domain.CreateMovie true "request"
 // @Success 200 {object} sender.JSONResponse
-// @Failure 400 {object} sender.JSONResponse
-// @Failure 500 {object} sender.JSONResponse
+// @Failure 400 {object} sender.Problem
+// @Failure 422 {object} sender.Problem
+// @Failure 500 {object} sender.Problem
 // @Router       /movie [post]
 func (c *movieController) Create(w http.ResponseWriter, r *http.Request) {
 	if r.Header.Get("Content-Type") != "application/json" {
 		c.logger.Infof("request didnt contain application/json")
-		sender.ErrorJSON(w, domain.ErrRequest, http.StatusBadRequest)
+		ErrorJSON(w, r, errContentType)
 		return
 	}
 
 	data, err := io.ReadAll(r.Body)
 	if err != nil {
 		c.logger.Infof("io.ReadAll error: %w", err)
-		sender.ErrorJSON(w, err, http.StatusInternalServerError)
+		ErrorJSON(w, r, err)
 		return
 	}
 	defer r.Body.Close()
 
 	createMovieDTO := domain.CreateMovie{}
-	err = json.Unmarshal(data, &createMovieDTO)
+	err = unmarshalRequest(data, &createMovieDTO)
 	if err != nil {
-		c.logger.Infof("json.Unmarshal error: %w", err)
-		sender.ErrorJSON(w, err, http.StatusInternalServerError)
+		c.logger.Infof("unmarshalRequest error: %w", err)
+		ErrorJSON(w, r, err)
 		return
 	}
 
 	if err = c.service.Create(&createMovieDTO); err != nil {
 		c.logger.Infof("c.MovieService.Create error: %w", err)
-		sender.ErrorJSON(w, err, http.StatusInternalServerError)
+		ErrorJSON(w, r, err)
 		return
 	}
 
@@ -93,38 +137,61 @@ func (c *movieController) Create(w http.ResponseWriter, r *http.Request) {
 // @Tags		 movie
 // @Accept       json
 // @Produce      json
+// @Param id path int true "Movie ID"
+// @Param If-Match header string true "ETag of the movie, or * for any version"
 // @Param request body domain.Movie true "request"
 // @Success 200
-// @Failure 400 {object} sender.JSONResponse
-// @Failure 500 {object} sender.JSONResponse
-// @Router       /movie [put]
+// @Failure 400 {object} sender.Problem
+// @Failure 404 {object} sender.Problem
+// @Failure 412 {object} sender.Problem
+// @Failure 422 {object} sender.Problem
+// @Failure 428 {object} sender.Problem
+// @Failure 500 {object} sender.Problem
+// @Router       /movies/{id} [put]
 func (c *movieController) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, moviesPath)
+	if err != nil {
+		c.logger.Infof("pathID error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	version, err := ifMatch(r, "movie", id)
+	if err != nil {
+		c.logger.Infof("ifMatch error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+
 	if r.Header.Get("Content-Type") != "application/json" {
 		c.logger.Infof("request didnt contain application/json")
-		sender.ErrorJSON(w, domain.ErrRequest, http.StatusBadRequest)
+		ErrorJSON(w, r, errContentType)
 		return
 	}
 
 	data, err := io.ReadAll(r.Body)
 	if err != nil {
 		c.logger.Infof("io.ReadAll error: %w", err)
-		sender.ErrorJSON(w, err, http.StatusInternalServerError)
+		ErrorJSON(w, r, err)
 		return
 	}
 	defer r.Body.Close()
 
 	movie := domain.Movie{}
-	err = json.Unmarshal(data, &movie)
+	err = unmarshalRequest(data, &movie)
 
 	if err != nil {
-		c.logger.Infof("json.Unmarshal error: %w", err)
-		sender.ErrorJSON(w, err, http.StatusInternalServerError)
+		c.logger.Infof("unmarshalRequest error: %w", err)
+		ErrorJSON(w, r, err)
 		return
 	}
+	movie.ID = id
+	movie.Version = version
 
-	if err = c.service.Update(&movie); err != nil {
+	err = c.service.Update(&movie)
+	if err != nil {
 		c.logger.Infof("c.MovieService.Update error: %w", err)
-		sender.ErrorJSON(w, err, http.StatusInternalServerError)
+		ErrorJSON(w, r, err)
 		return
 	}
 
@@ -140,43 +207,121 @@ func (c *movieController) Update(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// @Summary Delete
-// @Description  Delete a movie from the film library
+// @Summary Patch
+// @Description  Change only the supplied fields of a movie (JSON Merge Patch), actors replaces the whole cast. Null clears description, release_date or rating
 // @Tags		 movie
-// @Accept       json
+// @Accept       application/merge-patch+json
 // @Produce      json
-// @Param request body domain.Movie true "request"
-// @Success 200
-// @Failure 400 {object} sender.JSONResponse
-// @Failure 500 {object} sender.JSONResponse
-// @Router       /movie [delete]
-func (c *movieController) Delete(w http.ResponseWriter, r *http.Request) {
-	if r.Header.Get("Content-Type") != "application/json" {
-		c.logger.Infof("request didnt contain application/json")
-		sender.ErrorJSON(w, domain.ErrRequest, http.StatusBadRequest)
+// @Param id path int true "Movie ID"
+// @Param If-Match header string true "ETag of the movie, or * for any version"
+// @Param request body domain.PatchMovie true "request"
+// @Success 200 {object} sender.JSONResponse
+// @Failure 400 {object} sender.Problem
+// @Failure 404 {object} sender.Problem
+// @Failure 412 {object} sender.Problem
+// @Failure 422 {object} sender.Problem
+// @Failure 428 {object} sender.Problem
+// @Failure 500 {object} sender.Problem
+// @Router       /movies/{id} [patch]
+func (c *movieController) Patch(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, moviesPath)
+	if err != nil {
+		c.logger.Infof("pathID error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	version, err := ifMatch(r, "movie", id)
+	if err != nil {
+		c.logger.Infof("ifMatch error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	if !isMergePatch(r) {
+		c.logger.Infof("request didnt contain application/merge-patch+json")
+		ErrorJSON(w, r, errMergePatchType)
 		return
 	}
 
 	data, err := io.ReadAll(r.Body)
 	if err != nil {
 		c.logger.Infof("io.ReadAll error: %w", err)
-		sender.ErrorJSON(w, err, http.StatusInternalServerError)
+		ErrorJSON(w, r, err)
 		return
 	}
 	defer r.Body.Close()
 
-	deleteMove := domain.DeleteMovie{}
-	err = json.Unmarshal(data, &deleteMove)
+	patchMovieDTO := domain.PatchMovie{}
+	nulls, err := decodeMergePatch(data, &patchMovieDTO, "description", "release_date", "rating")
+	if err != nil {
+		c.logger.Infof("decodeMergePatch error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+	patchMovieDTO.NullDescription = nulls["description"]
+	patchMovieDTO.NullReleaseDate = nulls["release_date"]
+	patchMovieDTO.NullRating = nulls["rating"]
+	patchMovieDTO.ID = id
+	patchMovieDTO.Version = version
 
+	err = c.service.Patch(&patchMovieDTO)
 	if err != nil {
-		c.logger.Infof("json.Unmarshal error: %w", err)
-		sender.ErrorJSON(w, err, http.StatusInternalServerError)
+		c.logger.Infof("c.MovieService.Patch error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	if err = sender.WriteJSON(w, http.StatusOK, struct {
+		Status  string `json:"status"`
+		Message string `json:"message"`
+	}{
+		Status:  "OK",
+		Message: "movie was updated",
+	}); err != nil {
+		c.logger.Infof("WriteJSON %w", err)
 		return
 	}
+}
 
-	if err = c.service.Delete(&deleteMove); err != nil {
+// @Summary Delete
+// @Description  Delete a movie from the film library
+// @Tags		 movie
+// @Accept       json
+// @Produce      json
+// @Param id path int true "Movie ID"
+// @Param If-Match header string true "ETag of the movie, or * for any version"
+// @Success 200
+// @Failure 400 {object} sender.Problem
+// @Failure 404 {object} sender.Problem
+// @Failure 412 {object} sender.Problem
+// @Failure 428 {object} sender.Problem
+// @Failure 500 {object} sender.Problem
+// @Router       /movies/{id} [delete]
+func (c *movieController) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, moviesPath)
+	if err != nil {
+		c.logger.Infof("pathID error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	version, err := ifMatch(r, "movie", id)
+	if err != nil {
+		c.logger.Infof("ifMatch error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	deleteMove := domain.DeleteMovie{
+		ID:      id,
+		Version: version,
+	}
+
+	err = c.service.Delete(&deleteMove)
+	if err != nil {
 		c.logger.Infof("c.MovieService.Delete error: %w", err)
-		sender.ErrorJSON(w, err, http.StatusInternalServerError)
+		ErrorJSON(w, r, err)
 		return
 	}
 
@@ -193,53 +338,112 @@ func (c *movieController) Delete(w http.ResponseWriter, r *http.Request) {
 }
 
 // @Summary Get
-// @Description  Delete a movie from the film library
+// @Description  Search movies by a fragment of the title and/or of an actor name, best matches first. When no movie contains them, the misspelt terms are matched fuzzily by trigram similarity, the most similar first, with fuzzy set and a did_you_mean suggestion of the closest known title and actor name.
 // @Tags		 movie
 // @Accept       json
 // @Produce      json
-// @Param title query string true "Movie title"
-// @Param actor query string true "Actor name"
-// @Success 200 {object} domain.MovieWithoudID
-// @Failure 400 {object} sender.JSONResponse
-// @Failure 500 {object} sender.JSONResponse
+// @Param title query string false "Movie title"
+// @Param actor query string false "Actor name"
+// @Param similarity query number false "Least similarity of a fuzzy match, above 0 and at most 1 (default 0.4 or SEARCH_SIMILARITY)"
+// @Param limit query int false "Page size (1-100, default 20)"
+// @Param offset query int false "Number of movies to skip"
+// @Success 200 {object} domain.MoviePage
+// @Failure 400 {object} sender.Problem
+// @Failure 500 {object} sender.Problem
 // @Router       /movie [get]
 func (c *movieController) Get(w http.ResponseWriter, r *http.Request) {
 	title := r.URL.Query().Get("title")
 	name := r.URL.Query().Get("actor")
 
 	if title == "" && name == "" {
-		c.logger.Infof("empty query: %w", domain.ErrRequest)
-		sender.ErrorJSON(w, domain.ErrRequest, http.StatusBadRequest)
+		err := domain.NewFieldError(domain.ErrRequest, "title", "title or actor is required")
+		c.logger.Infof("empty query: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	limit, offset, err := queryPage(r)
+	if err != nil {
+		c.logger.Infof("queryPage error: %w", err)
+		ErrorJSON(w, r, err)
 		return
 	}
 
-	var searchType string
-	if title != "" {
-		searchType = "title"
-	} else {
-		searchType = "actor"
+	similarity, err := querySimilarity(r)
+	if err != nil {
+		c.logger.Infof("querySimilarity error: %w", err)
+		ErrorJSON(w, r, err)
+		return
 	}
 
 	getMovieDTO := domain.GetMovie{
 		Title:      title,
 		ActorName:  name,
-		SearchType: searchType,
+		Similarity: similarity,
+		Limit:      limit,
+		Offset:     offset,
 	}
 
 	movie, err := c.service.Get(&getMovieDTO)
 	if err != nil {
 		c.logger.Infof("c.MovieService.Get error: %w", err)
-		sender.ErrorJSON(w, err, http.StatusInternalServerError)
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	jsonResult, err := json.Marshal(movie)
+	if err != nil {
+		c.logger.Infof("json.Marshal error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	_, err = w.Write(jsonResult)
+	if err != nil {
+		c.logger.Infof("Write %w", err)
+		return
+	}
+}
+
+// @Summary GetByID
+// @Description  Get a movie with its full cast from the film library
+// @Tags		 movie
+// @Accept       json
+// @Produce      json
+// @Param id path int true "Movie ID"
+// @Success 200 {object} domain.MovieWithActors
+// @Header 200 {string} ETag "Version of the movie to send back in If-Match"
+// @Failure 400 {object} sender.Problem
+// @Failure 404 {object} sender.Problem
+// @Failure 500 {object} sender.Problem
+// @Router       /movies/{id} [get]
+func (c *movieController) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, moviesPath)
+	if err != nil {
+		c.logger.Infof("pathID error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	getMovieByIDDTO := domain.GetMovieByID{
+		ID: id,
+	}
+
+	movie, err := c.service.GetByID(&getMovieByIDDTO)
+	if err != nil {
+		c.logger.Infof("c.MovieService.GetByID error: %w", err)
+		ErrorJSON(w, r, err)
 		return
 	}
 
 	jsonResult, err := json.Marshal(movie)
 	if err != nil {
 		c.logger.Infof("json.Marshal error: %w", err)
-		sender.ErrorJSON(w, err, http.StatusInternalServerError)
+		ErrorJSON(w, r, err)
 		return
 	}
 
+	w.Header().Set("ETag", etag(movie.Version))
 	_, err = w.Write(jsonResult)
 	if err != nil {
 		c.logger.Infof("Write %w", err)
@@ -248,37 +452,89 @@ func (c *movieController) Get(w http.ResponseWriter, r *http.Request) {
 }
 
 // @Summary GetList
-// @Description  Delete a movie from the film library
+// @Description  Get the movies sorted by one or more fields, narrowed down by any of the filters. With facets=true the response holds the counts of the filtered movies by release year bucket and by rating, each facet counted without its own filter.
 // @Tags		 movie
 // @Accept       json
 // @Produce      json
-// @Param order query string false "Order"
-// @Success 200 {object} domain.GetOrderedMovie
-// @Failure 400 {object} sender.JSONResponse
-// @Failure 500 {object} sender.JSONResponse
+// @Param sort query string false "Comma-separated sort fields (title, rating, release_date), prefix with - for descending" default(-rating)
+// @Param limit query int false "Page size (1-100, default 20)"
+// @Param after query string false "Cursor of the next page (next_cursor)"
+// @Param before query string false "Cursor of the previous page (prev_cursor)"
+// @Param from_year query int false "Released in this year or later"
+// @Param to_year query int false "Released in this year or earlier"
+// @Param min_rating query int false "Rating at least (1-10)"
+// @Param max_rating query int false "Rating at most (1-10)"
+// @Param actors query string false "Comma-separated actor IDs of the cast"
+// @Param actors_match query string false "any (default) or all of the actors"
+// @Param with_gender query string false "Comma-separated genders each played by someone of the cast"
+// @Param without_gender query string false "Comma-separated genders played by nobody of the cast"
+// @Param q query string false "Full-text query as taken by /search"
+// @Param lang query string false "Language of q: english (default), russian or simple"
+// @Param facets query bool false "Return the facet counts"
+// @Param year_bucket query int false "Width in years of the release year facet (1-100, default 10)"
+// @Success 200 {object} domain.MovieList
+// @Failure 400 {object} sender.Problem
+// @Failure 500 {object} sender.Problem
 // @Router       /movie/all [get]
 func (c *movieController) GetOrderedList(w http.ResponseWriter, r *http.Request) {
-	order := r.URL.Query().Get("order")
+	if r.Method != "GET" {
+		methodNotAllowed(w, r, "GET")
+		return
+	}
+
+	spec := r.URL.Query().Get("sort")
+
+	if spec == "" {
+		spec = "-rating"
+	}
+
+	sort, err := domain.ParseSort(spec, domain.MovieSortFields)
+	if err != nil {
+		c.logger.Infof("domain.ParseSort error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	page, err := queryCursor(r)
+	if err != nil {
+		c.logger.Infof("queryCursor error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	filter, err := queryMovieFilter(r)
+	if err != nil {
+		c.logger.Infof("queryMovieFilter error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
 
-	if order == "" {
-		order = "rating"
+	facets, yearBucket, err := queryFacets(r)
+	if err != nil {
+		c.logger.Infof("queryFacets error: %w", err)
+		ErrorJSON(w, r, err)
+		return
 	}
 
 	getOrderedDTO := domain.GetOrderedMovie{
-		Order: order,
+		Sort:       sort,
+		Page:       page,
+		Filter:     filter,
+		Facets:     facets,
+		YearBucket: yearBucket,
 	}
 
 	movies, err := c.service.GetOrderedList(&getOrderedDTO)
 	if err != nil {
 		c.logger.Infof("c.MovieService.GetOrderedList error: %w", err)
-		sender.ErrorJSON(w, err, http.StatusInternalServerError)
+		ErrorJSON(w, r, err)
 		return
 	}
 
 	jsonResult, err := json.Marshal(movies)
 	if err != nil {
 		c.logger.Infof("json.Marshal error: %w", err)
-		sender.ErrorJSON(w, err, http.StatusInternalServerError)
+		ErrorJSON(w, r, err)
 		return
 	}
 
@@ -288,3 +544,195 @@ func (c *movieController) GetOrderedList(w http.ResponseWriter, r *http.Request)
 		return
 	}
 }
+
+// @Summary GetActors
+// @Description  Get the cast of a movie in the film library
+// @Tags		 movie
+// @Accept       json
+// @Produce      json
+// @Param id path int true "Movie ID"
+// @Success 200 {object} []domain.Actor
+// @Failure 400 {object} sender.Problem
+// @Failure 500 {object} sender.Problem
+// @Router       /movies/{id}/actors [get]
+func (c *movieController) GetActors(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, moviesPath)
+	if err != nil {
+		c.logger.Infof("pathID error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	getMovieActorsDTO := domain.GetMovieActors{
+		ID: id,
+	}
+
+	actors, err := c.service.GetActors(&getMovieActorsDTO)
+	if err != nil {
+		c.logger.Infof("c.MovieService.GetActors error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	jsonResult, err := json.Marshal(actors)
+	if err != nil {
+		c.logger.Infof("json.Marshal error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	_, err = w.Write(jsonResult)
+	if err != nil {
+		c.logger.Infof("Write %w", err)
+		return
+	}
+}
+
+// @Summary AddActor
+// @Description  Add an actor to the cast of a movie, keeping the rest of the cast
+// @Tags		 movie
+// @Accept       json
+// @Produce      json
+// @Param id path int true "Movie ID"
+// @Param If-Match header string true "ETag of the movie, or * for any version"
+// @Param request body domain.MovieActor true "request"
+// @Success 200 {object} sender.JSONResponse
+// @Failure 400 {object} sender.Problem
+// @Failure 404 {object} sender.Problem
+// @Failure 412 {object} sender.Problem
+// @Failure 422 {object} sender.Problem
+// @Failure 428 {object} sender.Problem
+// @Failure 500 {object} sender.Problem
+// @Router       /movies/{id}/actors [post]
+func (c *movieController) AddActor(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, moviesPath)
+	if err != nil {
+		c.logger.Infof("pathID error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	version, err := ifMatch(r, "movie", id)
+	if err != nil {
+		c.logger.Infof("ifMatch error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	if r.Header.Get("Content-Type") != "application/json" {
+		c.logger.Infof("request didnt contain application/json")
+		ErrorJSON(w, r, errContentType)
+		return
+	}
+
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		c.logger.Infof("io.ReadAll error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+	defer r.Body.Close()
+
+	movieActorDTO := domain.MovieActor{}
+	err = unmarshalRequest(data, &movieActorDTO)
+	if err != nil {
+		c.logger.Infof("unmarshalRequest error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	if movieActorDTO.ActorID <= 0 {
+		err = domain.NewFieldError(domain.ErrRequest, "actor_id", "must be a positive integer")
+		c.logger.Infof("incorrect actor_id: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+	movieActorDTO.MovieID = id
+	movieActorDTO.Version = version
+
+	if err = c.service.AddActor(&movieActorDTO); err != nil {
+		c.logger.Infof("c.MovieService.AddActor error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	if err = sender.WriteJSON(w, http.StatusOK, struct {
+		Status  string `json:"status"`
+		Message string `json:"message"`
+	}{
+		Status:  "OK",
+		Message: "actor was added to the cast",
+	}); err != nil {
+		c.logger.Infof("WriteJSON %w", err)
+		return
+	}
+}
+
+// @Summary RemoveActor
+// @Description  Remove an actor from the cast of a movie, keeping the rest of the cast
+// @Tags		 movie
+// @Accept       json
+// @Produce      json
+// @Param id path int true "Movie ID"
+// @Param actorId path int true "Actor ID"
+// @Param If-Match header string true "ETag of the movie, or * for any version"
+// @Success 200 {object} sender.JSONResponse
+// @Failure 400 {object} sender.Problem
+// @Failure 404 {object} sender.Problem
+// @Failure 412 {object} sender.Problem
+// @Failure 428 {object} sender.Problem
+// @Failure 500 {object} sender.Problem
+// @Router       /movies/{id}/actors/{actorId} [delete]
+func (c *movieController) RemoveActor(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, moviesPath)
+	if err != nil {
+		c.logger.Infof("pathID error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	params := pathParams(r, moviesPath)
+	if len(params) != 3 {
+		c.logger.Infof("incorrect path: %w", domain.ErrRequest)
+		ErrorJSON(w, r, domain.ErrRequest)
+		return
+	}
+
+	actorID, err := parseID(params[2])
+	if err != nil {
+		c.logger.Infof("parseID error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	version, err := ifMatch(r, "movie", id)
+	if err != nil {
+		c.logger.Infof("ifMatch error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	movieActorDTO := domain.MovieActor{
+		MovieID: id,
+		ActorID: actorID,
+		Version: version,
+	}
+
+	err = c.service.RemoveActor(&movieActorDTO)
+	if err != nil {
+		c.logger.Infof("c.MovieService.RemoveActor error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	if err = sender.WriteJSON(w, http.StatusOK, struct {
+		Status  string `json:"status"`
+		Message string `json:"message"`
+	}{
+		Status:  "OK",
+		Message: "actor was removed from the cast",
+	}); err != nil {
+		c.logger.Infof("WriteJSON %w", err)
+		return
+	}
+}
@@ -1,8 +1,10 @@
 package restapi
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
 	"time"
@@ -10,6 +12,7 @@ import (
 	"github.com/akrovv/filmlibrary/internal/domain"
 	"github.com/akrovv/filmlibrary/internal/service/mocks"
 	"github.com/akrovv/filmlibrary/pkg/logger"
+	"github.com/akrovv/filmlibrary/pkg/sender"
 	"github.com/golang/mock/gomock"
 )
 
@@ -76,8 +79,8 @@ func TestMovieCreate(t *testing.T) {
 
 	movieHandler.Create(w, req)
 
-	if w.Code != http.StatusInternalServerError {
-		t.Errorf("expected 500, got: %d", w.Code)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got: %d", w.Code)
 	}
 
 	// io.ReadAll returned error
@@ -102,6 +105,33 @@ func TestMovieCreate(t *testing.T) {
 	if w.Code != http.StatusInternalServerError {
 		t.Errorf("expected 500, got: %d", w.Code)
 	}
+
+	// Create returned validation error
+	req = httptest.NewRequest("POST", "/movie", strings.NewReader(body))
+	req.Header.Add("Content-type", "application/json")
+	w = httptest.NewRecorder()
+
+	ms.EXPECT().Create(&createMovieDTO).Return(&domain.InvalidFieldsError{
+		Kind: domain.ErrValidation,
+		Fields: []domain.FieldError{
+			{Field: "movie_title", Message: "is required"},
+			{Field: "rating", Message: "must be between 1 and 10"},
+		},
+	})
+	movieHandler.Create(w, req)
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected 422, got: %d", w.Code)
+	}
+
+	problem := sender.Problem{}
+	if err = json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("can't decode problem: %s", err)
+	}
+
+	if problem.Code != "validation_failed" || len(problem.Errors) != 2 {
+		t.Errorf("expected both field errors, got: %+v", problem)
+	}
 }
 
 func TestMovieUpdate(t *testing.T) {
@@ -139,9 +169,11 @@ func TestMovieUpdate(t *testing.T) {
 		ReleaseDate: expectedTime,
 		Rating:      5,
 		Actors:      []int64{1, 2},
+		Version:     1,
 	}
 
-	req := httptest.NewRequest("PUT", "/movie", strings.NewReader(body))
+	req := httptest.NewRequest("PUT", "/movies/1", strings.NewReader(body))
+	req.Header.Add("If-Match", `"1"`)
 	req.Header.Add("Content-type", "application/json")
 	w := httptest.NewRecorder()
 
@@ -153,8 +185,20 @@ func TestMovieUpdate(t *testing.T) {
 		t.Errorf("expected 200, got: %d", w.Code)
 	}
 
+	// Incorrect ID
+	req = httptest.NewRequest("PUT", "/movies/0", strings.NewReader(body))
+	req.Header.Add("If-Match", `"1"`)
+	req.Header.Add("Content-type", "application/json")
+	w = httptest.NewRecorder()
+	movieHandler.Update(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got: %d", w.Code)
+	}
+
 	// Missed Content-Type
-	req.Header.Del("Content-type")
+	req = httptest.NewRequest("PUT", "/movies/1", strings.NewReader(body))
+	req.Header.Add("If-Match", `"1"`)
 	w = httptest.NewRecorder()
 	movieHandler.Update(w, req)
 
@@ -163,18 +207,20 @@ func TestMovieUpdate(t *testing.T) {
 	}
 
 	// Incorrect JSON
-	req = httptest.NewRequest("PUT", "/movie", strings.NewReader(`{"movie_title": {`))
+	req = httptest.NewRequest("PUT", "/movies/1", strings.NewReader(`{"movie_title": {`))
+	req.Header.Add("If-Match", `"1"`)
 	req.Header.Add("Content-type", "application/json")
 	w = httptest.NewRecorder()
 
 	movieHandler.Update(w, req)
 
-	if w.Code != http.StatusInternalServerError {
-		t.Errorf("expected 500, got: %d", w.Code)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got: %d", w.Code)
 	}
 
 	// io.ReadAll returned error
-	req = httptest.NewRequest("PUT", "/movie", &BadReader{})
+	req = httptest.NewRequest("PUT", "/movies/1", &BadReader{})
+	req.Header.Add("If-Match", `"1"`)
 	req.Header.Add("Content-type", "application/json")
 	w = httptest.NewRecorder()
 
@@ -185,7 +231,8 @@ func TestMovieUpdate(t *testing.T) {
 	}
 
 	// Update returned error
-	req = httptest.NewRequest("POST", "/register", strings.NewReader(body))
+	req = httptest.NewRequest("PUT", "/movies/1", strings.NewReader(body))
+	req.Header.Add("If-Match", `"1"`)
 	req.Header.Add("Content-type", "application/json")
 	w = httptest.NewRecorder()
 
@@ -195,9 +242,72 @@ func TestMovieUpdate(t *testing.T) {
 	if w.Code != http.StatusInternalServerError {
 		t.Errorf("expected 500, got: %d", w.Code)
 	}
-}
 
-func TestMovieDelete(t *testing.T) {
+	// Missed If-Match
+	req = httptest.NewRequest("PUT", "/movies/1", strings.NewReader(body))
+	req.Header.Add("Content-type", "application/json")
+	w = httptest.NewRecorder()
+	movieHandler.Update(w, req)
+
+	if w.Code != http.StatusPreconditionRequired {
+		t.Errorf("expected 428, got: %d", w.Code)
+	}
+
+	// Weak or unknown If-Match fails the precondition
+	for _, tag := range []string{`W/"1"`, `"abc"`, `"0"`, `1`} {
+		req = httptest.NewRequest("PUT", "/movies/1", strings.NewReader(body))
+		req.Header.Add("If-Match", tag)
+		req.Header.Add("Content-type", "application/json")
+		w = httptest.NewRecorder()
+		movieHandler.Update(w, req)
+
+		if w.Code != http.StatusPreconditionFailed {
+			t.Errorf("%s: expected 412, got: %d", tag, w.Code)
+		}
+	}
+
+	// If-Match: * matches any version
+	req = httptest.NewRequest("PUT", "/movies/1", strings.NewReader(body))
+	req.Header.Add("If-Match", "*")
+	req.Header.Add("Content-type", "application/json")
+	w = httptest.NewRecorder()
+
+	anyVersion := movie
+	anyVersion.Version = 0
+	ms.EXPECT().Update(&anyVersion).Return(nil)
+	movieHandler.Update(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got: %d", w.Code)
+	}
+
+	// Stale version
+	req = httptest.NewRequest("PUT", "/movies/1", strings.NewReader(body))
+	req.Header.Add("If-Match", `"1"`)
+	req.Header.Add("Content-type", "application/json")
+	w = httptest.NewRecorder()
+
+	ms.EXPECT().Update(&movie).Return(&domain.VersionConflictError{Resource: "movie", ID: 1, Version: 2})
+	movieHandler.Update(w, req)
+
+	if w.Code != http.StatusPreconditionFailed {
+		t.Errorf("expected 412, got: %d", w.Code)
+	}
+
+	// Not found
+	req = httptest.NewRequest("PUT", "/movies/1", strings.NewReader(body))
+	req.Header.Add("If-Match", `"1"`)
+	req.Header.Add("Content-type", "application/json")
+	w = httptest.NewRecorder()
+
+	ms.EXPECT().Update(&movie).Return(domain.ErrNotFound)
+	movieHandler.Update(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got: %d", w.Code)
+	}
+}
+func TestMoviePatch(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -211,70 +321,270 @@ func TestMovieDelete(t *testing.T) {
 	movieHandler := NewMovieController(logger, ms)
 
 	body := `{
-		"movie_id": 1
+		"movie_title": "title",
+		"release_date": "2007-02-02T00:00:00Z"
 	  }`
 
-	deleteMovie := domain.DeleteMovie{
-		ID: 1,
+	expectedTime, err := time.Parse(time.RFC3339, "2007-02-02T00:00:00Z")
+
+	if err != nil {
+		t.Fatalf("can't parse time: %s", err)
 	}
 
-	req := httptest.NewRequest("DELETE", "/movie", strings.NewReader(body))
-	req.Header.Add("Content-type", "application/json")
+	title := "title"
+	movie := domain.PatchMovie{
+		ID:          1,
+		Title:       &title,
+		ReleaseDate: &expectedTime,
+		Version:     1,
+	}
+
+	req := httptest.NewRequest("PATCH", "/movies/1", strings.NewReader(body))
+	req.Header.Add("If-Match", `"1"`)
+	req.Header.Add("Content-type", "application/merge-patch+json")
 	w := httptest.NewRecorder()
 
 	// OK
-	ms.EXPECT().Delete(&deleteMovie).Return(nil)
-	movieHandler.Delete(w, req)
+	ms.EXPECT().Patch(&movie).Return(nil)
+	movieHandler.Patch(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got: %d", w.Code)
+	}
+
+	// OK with application/json and the whole cast
+	req = httptest.NewRequest("PATCH", "/movies/1", strings.NewReader(`{"actors": []}`))
+	req.Header.Add("If-Match", `"1"`)
+	req.Header.Add("Content-type", "application/json")
+	w = httptest.NewRecorder()
+
+	ms.EXPECT().Patch(&domain.PatchMovie{ID: 1, Actors: []int64{}, Version: 1}).Return(nil)
+	movieHandler.Patch(w, req)
 
 	if w.Code != http.StatusOK {
 		t.Errorf("expected 200, got: %d", w.Code)
 	}
 
+	// Incorrect ID
+	req = httptest.NewRequest("PATCH", "/movies/0", strings.NewReader(body))
+	req.Header.Add("If-Match", `"1"`)
+	req.Header.Add("Content-type", "application/merge-patch+json")
+	w = httptest.NewRecorder()
+	movieHandler.Patch(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got: %d", w.Code)
+	}
+
 	// Missed Content-Type
-	req.Header.Del("Content-type")
+	req = httptest.NewRequest("PATCH", "/movies/1", strings.NewReader(body))
+	req.Header.Add("If-Match", `"1"`)
 	w = httptest.NewRecorder()
-	movieHandler.Delete(w, req)
+	movieHandler.Patch(w, req)
 
 	if w.Code != http.StatusBadRequest {
 		t.Errorf("expected 400, got: %d", w.Code)
 	}
 
 	// Incorrect JSON
-	req = httptest.NewRequest("DELETE", "/movie", strings.NewReader(`{"movie_title": {`))
-	req.Header.Add("Content-type", "application/json")
+	req = httptest.NewRequest("PATCH", "/movies/1", strings.NewReader(`[1, 2]`))
+	req.Header.Add("If-Match", `"1"`)
+	req.Header.Add("Content-type", "application/merge-patch+json")
 	w = httptest.NewRecorder()
+	movieHandler.Patch(w, req)
 
-	movieHandler.Delete(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got: %d", w.Code)
+	}
+
+	// OK. Null clears a nullable member
+	req = httptest.NewRequest("PATCH", "/movies/1", strings.NewReader(`{"description": null, "release_date": null, "rating": null}`))
+	req.Header.Add("If-Match", `"1"`)
+	req.Header.Add("Content-type", "application/merge-patch+json")
+	w = httptest.NewRecorder()
+
+	ms.EXPECT().Patch(&domain.PatchMovie{ID: 1, Version: 1, NullDescription: true, NullReleaseDate: true, NullRating: true}).Return(nil)
+	movieHandler.Patch(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got: %d", w.Code)
+	}
+
+	// Null member that can't be cleared
+	for _, body := range []string{`{"movie_title": null}`, `{"actors": null}`} {
+		req = httptest.NewRequest("PATCH", "/movies/1", strings.NewReader(body))
+		req.Header.Add("If-Match", `"1"`)
+		req.Header.Add("Content-type", "application/merge-patch+json")
+		w = httptest.NewRecorder()
+		movieHandler.Patch(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got: %d", body, w.Code)
+		}
+	}
+
+	// io.ReadAll returned error
+	req = httptest.NewRequest("PATCH", "/movies/1", &BadReader{})
+	req.Header.Add("If-Match", `"1"`)
+	req.Header.Add("Content-type", "application/merge-patch+json")
+	w = httptest.NewRecorder()
+	movieHandler.Patch(w, req)
 
 	if w.Code != http.StatusInternalServerError {
 		t.Errorf("expected 500, got: %d", w.Code)
 	}
 
-	// io.ReadAll returned error
-	req = httptest.NewRequest("DELETE", "/movie", &BadReader{})
+	// Not found
+	req = httptest.NewRequest("PATCH", "/movies/1", strings.NewReader(body))
+	req.Header.Add("If-Match", `"1"`)
+	req.Header.Add("Content-type", "application/merge-patch+json")
+	w = httptest.NewRecorder()
+
+	ms.EXPECT().Patch(&movie).Return(domain.ErrNotFound)
+	movieHandler.Patch(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got: %d", w.Code)
+	}
+
+	// Patch returned error
+	req = httptest.NewRequest("PATCH", "/movies/1", strings.NewReader(body))
+	req.Header.Add("If-Match", `"1"`)
+	req.Header.Add("Content-type", "application/merge-patch+json")
+	w = httptest.NewRecorder()
+
+	ms.EXPECT().Patch(&movie).Return(domain.ErrTest)
+	movieHandler.Patch(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500, got: %d", w.Code)
+	}
+}
+
+func TestMovieAddActor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ms := mocks.NewMockMovieService(ctrl)
+
+	logger, err := logger.NewLogger()
+	if err != nil {
+		t.Fatalf("can't create logger: %s", err)
+	}
+
+	movieHandler := NewMovieController(logger, ms)
+
+	movieActor := domain.MovieActor{
+		MovieID: 1,
+		ActorID: 2,
+		Version: 3,
+	}
+
+	req := httptest.NewRequest("POST", "/movies/1/actors", strings.NewReader(`{"actor_id": 2}`))
+	req.Header.Add("If-Match", `"3"`)
+	req.Header.Add("Content-type", "application/json")
+	w := httptest.NewRecorder()
+
+	// OK
+	ms.EXPECT().AddActor(&movieActor).Return(nil)
+	movieHandler.AddActor(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got: %d", w.Code)
+	}
+
+	// Missed If-Match
+	req = httptest.NewRequest("POST", "/movies/1/actors", strings.NewReader(`{"actor_id": 2}`))
 	req.Header.Add("Content-type", "application/json")
 	w = httptest.NewRecorder()
+	movieHandler.AddActor(w, req)
 
-	movieHandler.Delete(w, req)
+	if w.Code != http.StatusPreconditionRequired {
+		t.Errorf("expected 428, got: %d", w.Code)
+	}
+
+	// Stale If-Match
+	req = httptest.NewRequest("POST", "/movies/1/actors", strings.NewReader(`{"actor_id": 2}`))
+	req.Header.Add("If-Match", `"3"`)
+	req.Header.Add("Content-type", "application/json")
+	w = httptest.NewRecorder()
+
+	ms.EXPECT().AddActor(&movieActor).Return(&domain.VersionConflictError{Resource: "movie", ID: 1, Version: 4})
+	movieHandler.AddActor(w, req)
+
+	if w.Code != http.StatusPreconditionFailed {
+		t.Errorf("expected 412, got: %d", w.Code)
+	}
+
+	// Incorrect ID
+	req = httptest.NewRequest("POST", "/movies/abc/actors", strings.NewReader(`{"actor_id": 2}`))
+	req.Header.Add("If-Match", `"3"`)
+	req.Header.Add("Content-type", "application/json")
+	w = httptest.NewRecorder()
+	movieHandler.AddActor(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got: %d", w.Code)
+	}
+
+	// Missed Content-Type
+	req = httptest.NewRequest("POST", "/movies/1/actors", strings.NewReader(`{"actor_id": 2}`))
+	req.Header.Add("If-Match", `"3"`)
+	w = httptest.NewRecorder()
+	movieHandler.AddActor(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got: %d", w.Code)
+	}
+
+	// Missed actor_id
+	req = httptest.NewRequest("POST", "/movies/1/actors", strings.NewReader(`{}`))
+	req.Header.Add("If-Match", `"3"`)
+	req.Header.Add("Content-type", "application/json")
+	w = httptest.NewRecorder()
+	movieHandler.AddActor(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got: %d", w.Code)
+	}
+
+	// Incorrect JSON
+	req = httptest.NewRequest("POST", "/movies/1/actors", strings.NewReader(`{"actor_id": {`))
+	req.Header.Add("If-Match", `"3"`)
+	req.Header.Add("Content-type", "application/json")
+	w = httptest.NewRecorder()
+	movieHandler.AddActor(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got: %d", w.Code)
+	}
+
+	// io.ReadAll returned error
+	req = httptest.NewRequest("POST", "/movies/1/actors", &BadReader{})
+	req.Header.Add("If-Match", `"3"`)
+	req.Header.Add("Content-type", "application/json")
+	w = httptest.NewRecorder()
+	movieHandler.AddActor(w, req)
 
 	if w.Code != http.StatusInternalServerError {
 		t.Errorf("expected 500, got: %d", w.Code)
 	}
 
-	// Delete returned error
-	req = httptest.NewRequest("POST", "/register", strings.NewReader(body))
+	// AddActor returned error
+	req = httptest.NewRequest("POST", "/movies/1/actors", strings.NewReader(`{"actor_id": 2}`))
+	req.Header.Add("If-Match", `"3"`)
 	req.Header.Add("Content-type", "application/json")
 	w = httptest.NewRecorder()
 
-	ms.EXPECT().Delete(&deleteMovie).Return(domain.ErrTest)
-	movieHandler.Delete(w, req)
+	ms.EXPECT().AddActor(&movieActor).Return(domain.ErrTest)
+	movieHandler.AddActor(w, req)
 
 	if w.Code != http.StatusInternalServerError {
 		t.Errorf("expected 500, got: %d", w.Code)
 	}
 }
 
-func TestMovieGet(t *testing.T) {
+func TestMovieRemoveActor(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -286,61 +596,92 @@ func TestMovieGet(t *testing.T) {
 	}
 
 	movieHandler := NewMovieController(logger, ms)
-	getMovieTitle := domain.GetMovie{
-		Title:      "br",
-		SearchType: "title",
-	}
 
-	getMovieActor := domain.GetMovie{
-		ActorName:  "ti",
-		SearchType: "actor",
+	movieActor := domain.MovieActor{
+		MovieID: 1,
+		ActorID: 2,
+		Version: 3,
 	}
 
-	req := httptest.NewRequest("GET", "/movie?title=br", nil)
+	req := httptest.NewRequest("DELETE", "/movies/1/actors/2", nil)
+	req.Header.Add("If-Match", `"3"`)
 	w := httptest.NewRecorder()
 
-	// OK. Title
-	ms.EXPECT().Get(&getMovieTitle).Return(&domain.MovieWithoudID{}, nil)
-	movieHandler.Get(w, req)
+	// OK
+	ms.EXPECT().RemoveActor(&movieActor).Return(nil)
+	movieHandler.RemoveActor(w, req)
 
 	if w.Code != http.StatusOK {
 		t.Errorf("expected 200, got: %d", w.Code)
 	}
 
-	// OK. ActorName
-	req = httptest.NewRequest("GET", "/movie?actor=ti", nil)
+	// Missed If-Match
+	req = httptest.NewRequest("DELETE", "/movies/1/actors/2", nil)
 	w = httptest.NewRecorder()
+	movieHandler.RemoveActor(w, req)
 
-	ms.EXPECT().Get(&getMovieActor).Return(&domain.MovieWithoudID{}, nil)
-	movieHandler.Get(w, req)
-
-	if w.Code != http.StatusOK {
-		t.Errorf("expected 200, got: %d", w.Code)
+	if w.Code != http.StatusPreconditionRequired {
+		t.Errorf("expected 428, got: %d", w.Code)
 	}
 
-	// Get returned error
-	req = httptest.NewRequest("GET", "/movie?title=br", nil)
+	// Stale If-Match
+	req = httptest.NewRequest("DELETE", "/movies/1/actors/2", nil)
+	req.Header.Add("If-Match", `"3"`)
 	w = httptest.NewRecorder()
 
-	ms.EXPECT().Get(&getMovieTitle).Return(nil, domain.ErrTest)
-	movieHandler.Get(w, req)
+	ms.EXPECT().RemoveActor(&movieActor).Return(&domain.VersionConflictError{Resource: "movie", ID: 1, Version: 4})
+	movieHandler.RemoveActor(w, req)
 
-	if w.Code != http.StatusInternalServerError {
-		t.Errorf("expected 500, got: %d", w.Code)
+	if w.Code != http.StatusPreconditionFailed {
+		t.Errorf("expected 412, got: %d", w.Code)
 	}
 
-	// Empty query
-	req = httptest.NewRequest("GET", "/movie", nil)
+	// Incorrect actor ID
+	req = httptest.NewRequest("DELETE", "/movies/1/actors/abc", nil)
+	req.Header.Add("If-Match", `"3"`)
 	w = httptest.NewRecorder()
+	movieHandler.RemoveActor(w, req)
 
-	movieHandler.Get(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got: %d", w.Code)
+	}
+
+	// Missed actor ID
+	req = httptest.NewRequest("DELETE", "/movies/1/actors", nil)
+	req.Header.Add("If-Match", `"3"`)
+	w = httptest.NewRecorder()
+	movieHandler.RemoveActor(w, req)
 
 	if w.Code != http.StatusBadRequest {
 		t.Errorf("expected 400, got: %d", w.Code)
 	}
+
+	// Not in the cast
+	req = httptest.NewRequest("DELETE", "/movies/1/actors/2", nil)
+	req.Header.Add("If-Match", `"3"`)
+	w = httptest.NewRecorder()
+
+	ms.EXPECT().RemoveActor(&movieActor).Return(domain.ErrNotFound)
+	movieHandler.RemoveActor(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got: %d", w.Code)
+	}
+
+	// RemoveActor returned error
+	req = httptest.NewRequest("DELETE", "/movies/1/actors/2", nil)
+	req.Header.Add("If-Match", `"3"`)
+	w = httptest.NewRecorder()
+
+	ms.EXPECT().RemoveActor(&movieActor).Return(domain.ErrTest)
+	movieHandler.RemoveActor(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500, got: %d", w.Code)
+	}
 }
 
-func TestMovieGetOrderedList(t *testing.T) {
+func TestMovieDelete(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -353,45 +694,602 @@ func TestMovieGetOrderedList(t *testing.T) {
 
 	movieHandler := NewMovieController(logger, ms)
 
-	orderByMovieTitle := domain.GetOrderedMovie{
-		Order: "movie_title",
-	}
-
-	orderByDefault := domain.GetOrderedMovie{
-		Order: "rating",
+	deleteMovie := domain.DeleteMovie{
+		ID:      1,
+		Version: 1,
 	}
 
-	req := httptest.NewRequest("GET", "/movie/all?order=movie_title", nil)
+	req := httptest.NewRequest("DELETE", "/movies/1", nil)
+	req.Header.Add("If-Match", `"1"`)
 	w := httptest.NewRecorder()
 
-	// OK. Order by movie title
-	ms.EXPECT().GetOrderedList(&orderByMovieTitle).Return([]domain.MovieWithoudID{}, nil)
-	movieHandler.GetOrderedList(w, req)
+	// OK
+	ms.EXPECT().Delete(&deleteMovie).Return(nil)
+	movieHandler.Delete(w, req)
 
 	if w.Code != http.StatusOK {
 		t.Errorf("expected 200, got: %d", w.Code)
 	}
 
-	// OK. Missed order
-	req = httptest.NewRequest("GET", "/movie/all", nil)
+	// Incorrect ID
+	req = httptest.NewRequest("DELETE", "/movies/abc", nil)
+	req.Header.Add("If-Match", `"1"`)
 	w = httptest.NewRecorder()
 
-	ms.EXPECT().GetOrderedList(&orderByDefault).Return([]domain.MovieWithoudID{}, nil)
-	movieHandler.GetOrderedList(w, req)
+	movieHandler.Delete(w, req)
 
-	if w.Code != http.StatusOK {
-		t.Errorf("expected 200, got: %d", w.Code)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got: %d", w.Code)
 	}
 
-	// GetOrderedList returned error
-	req = httptest.NewRequest("GET", "/movie?title=br", nil)
-	req.Header.Add("Content-type", "application/json")
+	// Delete returned error
+	req = httptest.NewRequest("DELETE", "/movies/1", nil)
+	req.Header.Add("If-Match", `"1"`)
 	w = httptest.NewRecorder()
 
-	ms.EXPECT().GetOrderedList(&orderByDefault).Return(nil, domain.ErrTest)
-	movieHandler.GetOrderedList(w, req)
+	ms.EXPECT().Delete(&deleteMovie).Return(domain.ErrTest)
+	movieHandler.Delete(w, req)
 
 	if w.Code != http.StatusInternalServerError {
 		t.Errorf("expected 500, got: %d", w.Code)
 	}
 }
+
+func TestMovieGet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ms := mocks.NewMockMovieService(ctrl)
+
+	logger, err := logger.NewLogger()
+	if err != nil {
+		t.Fatalf("can't create logger: %s", err)
+	}
+
+	movieHandler := NewMovieController(logger, ms)
+	getMovieTitle := domain.GetMovie{
+		Title: "br",
+		Limit: 20,
+	}
+
+	getMovieActor := domain.GetMovie{
+		ActorName: "ti",
+		Limit:     20,
+	}
+
+	getMovieBoth := domain.GetMovie{
+		Title:     "br",
+		ActorName: "ti",
+		Limit:     10,
+		Offset:    30,
+	}
+
+	req := httptest.NewRequest("GET", "/movie?title=br", nil)
+	w := httptest.NewRecorder()
+
+	// OK. Title
+	ms.EXPECT().Get(&getMovieTitle).Return(&domain.MoviePage{}, nil)
+	movieHandler.Get(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got: %d", w.Code)
+	}
+
+	// OK. ActorName
+	req = httptest.NewRequest("GET", "/movie?actor=ti", nil)
+	w = httptest.NewRecorder()
+
+	ms.EXPECT().Get(&getMovieActor).Return(&domain.MoviePage{}, nil)
+	movieHandler.Get(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got: %d", w.Code)
+	}
+
+	// OK. Title, ActorName and page
+	req = httptest.NewRequest("GET", "/movie?title=br&actor=ti&limit=10&offset=30", nil)
+	w = httptest.NewRecorder()
+
+	ms.EXPECT().Get(&getMovieBoth).Return(&domain.MoviePage{}, nil)
+	movieHandler.Get(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got: %d", w.Code)
+	}
+
+	// OK. Fuzzy match with a threshold
+	req = httptest.NewRequest("GET", "/movie?actor=di+caprio&similarity=0.5", nil)
+	w = httptest.NewRecorder()
+
+	ms.EXPECT().Get(&domain.GetMovie{ActorName: "di caprio", Similarity: 0.5, Limit: 20}).Return(&domain.MoviePage{
+		Movies:     []domain.Movie{{ID: 1, Title: "Titanic"}},
+		Total:      1,
+		Limit:      20,
+		Fuzzy:      true,
+		DidYouMean: &domain.DidYouMean{Actor: "Leonardo DiCaprio"},
+	}, nil)
+	movieHandler.Get(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got: %d", w.Code)
+	}
+
+	page := struct {
+		Fuzzy      bool              `json:"fuzzy"`
+		DidYouMean map[string]string `json:"did_you_mean"`
+	}{}
+	if err = json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if !page.Fuzzy || page.DidYouMean["actor"] != "Leonardo DiCaprio" {
+		t.Errorf("expected a fuzzy page with a suggestion, got: %s", w.Body.String())
+	}
+
+	// Get returned error
+	req = httptest.NewRequest("GET", "/movie?title=br", nil)
+	w = httptest.NewRecorder()
+
+	ms.EXPECT().Get(&getMovieTitle).Return(nil, domain.ErrTest)
+	movieHandler.Get(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500, got: %d", w.Code)
+	}
+
+	// Empty query
+	req = httptest.NewRequest("GET", "/movie", nil)
+	w = httptest.NewRecorder()
+
+	movieHandler.Get(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got: %d", w.Code)
+	}
+
+	// Incorrect limit, offset or similarity
+	for _, query := range []string{"limit=0", "limit=101", "limit=abc", "offset=-1", "similarity=0", "similarity=1.5", "similarity=abc"} {
+		req = httptest.NewRequest("GET", "/movie?title=br&"+query, nil)
+		w = httptest.NewRecorder()
+
+		movieHandler.Get(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got: %d", query, w.Code)
+		}
+	}
+}
+
+func TestMovieGetOrderedList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ms := mocks.NewMockMovieService(ctrl)
+
+	logger, err := logger.NewLogger()
+	if err != nil {
+		t.Fatalf("can't create logger: %s", err)
+	}
+
+	movieHandler := NewMovieController(logger, ms)
+
+	orderByFields := domain.GetOrderedMovie{
+		Sort: []domain.SortField{
+			{Field: "rating", Desc: true},
+			{Field: "title"},
+			{Field: "release_date"},
+		},
+		Page: domain.Page{Limit: 50, After: "cursor"},
+	}
+
+	orderByDefault := domain.GetOrderedMovie{
+		Sort: []domain.SortField{
+			{Field: "rating", Desc: true},
+		},
+		Page: domain.Page{Limit: 20},
+	}
+
+	req := httptest.NewRequest("GET", "/movie/all?sort=-rating,title,%2Brelease_date&limit=50&after=cursor", nil)
+	w := httptest.NewRecorder()
+
+	// OK. Order by several fields
+	ms.EXPECT().GetOrderedList(&orderByFields).Return(&domain.MovieList{}, nil)
+	movieHandler.GetOrderedList(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got: %d", w.Code)
+	}
+
+	// OK. Missed order
+	req = httptest.NewRequest("GET", "/movie/all", nil)
+	w = httptest.NewRecorder()
+
+	ms.EXPECT().GetOrderedList(&orderByDefault).Return(&domain.MovieList{}, nil)
+	movieHandler.GetOrderedList(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got: %d", w.Code)
+	}
+
+	// GetOrderedList returned error
+	req = httptest.NewRequest("GET", "/movie?title=br", nil)
+	req.Header.Add("Content-type", "application/json")
+	w = httptest.NewRecorder()
+
+	ms.EXPECT().GetOrderedList(&orderByDefault).Return(nil, domain.ErrTest)
+	movieHandler.GetOrderedList(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500, got: %d", w.Code)
+	}
+
+	// Unknown or repeated sort field
+	for _, spec := range []string{"rating;DROP", "movie_id", "title,-title", "rating,"} {
+		req = httptest.NewRequest("GET", "/movie/all?sort="+url.QueryEscape(spec), nil)
+		w = httptest.NewRecorder()
+
+		movieHandler.GetOrderedList(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got: %d", spec, w.Code)
+		}
+
+		if !strings.Contains(w.Body.String(), "title, rating, release_date") {
+			t.Errorf("%s: expected allowed fields in response, got: %s", spec, w.Body.String())
+		}
+
+		if contentType := w.Header().Get("Content-Type"); contentType != sender.ProblemContentType {
+			t.Errorf("%s: expected problem+json, got: %s", spec, contentType)
+		}
+
+		problem := sender.Problem{}
+		if err = json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+			t.Fatalf("%s: can't decode problem: %s", spec, err)
+		}
+
+		if problem.Code != "bad_request" || problem.Status != http.StatusBadRequest || problem.Instance != "/movie/all" {
+			t.Errorf("%s: unexpected problem: %+v", spec, problem)
+		}
+
+		if len(problem.Errors) != 1 || problem.Errors[0].Field != "sort" {
+			t.Errorf("%s: expected a sort field error, got: %+v", spec, problem.Errors)
+		}
+	}
+
+	// OK. Filters and facets
+	req = httptest.NewRequest("GET", "/movie/all?sort=title&from_year=1990&to_year=1999&min_rating=7&max_rating=9"+
+		"&actors=4,9,4&actors_match=all&with_gender=female&without_gender=Male&q=heist&lang=russian&facets=true&year_bucket=5", nil)
+	w = httptest.NewRecorder()
+
+	ms.EXPECT().GetOrderedList(&domain.GetOrderedMovie{
+		Sort: []domain.SortField{{Field: "title"}},
+		Page: domain.Page{Limit: 20},
+		Filter: domain.MovieFilter{
+			FromYear:       1990,
+			ToYear:         1999,
+			MinRating:      7,
+			MaxRating:      9,
+			ActorIDs:       []int64{4, 9},
+			AllActors:      true,
+			WithGenders:    []string{"Female"},
+			WithoutGenders: []string{"Male"},
+			Query:          "heist",
+			Language:       "russian",
+		},
+		Facets:     true,
+		YearBucket: 5,
+	}).Return(&domain.MovieList{
+		Movies: []domain.Movie{{ID: 1, Title: "Heat"}},
+		Facets: &domain.MovieFacets{
+			Years:   []domain.YearBucket{{From: 1995, To: 1999, Count: 1}},
+			Ratings: []domain.RatingCount{{Rating: 8, Count: 1}},
+		},
+	}, nil)
+	movieHandler.GetOrderedList(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got: %d", w.Code)
+	}
+
+	if !strings.Contains(w.Body.String(), `"facets":{"years":[{"from":1995,"to":1999,"count":1}],"ratings":[{"rating":8,"count":1}]}`) {
+		t.Errorf("expected facets in response, got: %s", w.Body.String())
+	}
+
+	// Incorrect filters
+	for _, query := range []string{
+		"from_year=2000&to_year=1990", "from_year=0", "min_rating=11", "max_rating=-1", "min_rating=8&max_rating=7",
+		"actors=1,x", "actors=0", "actors_match=some", "with_gender=other", "without_gender=,",
+		"q=" + strings.Repeat("a", domain.MaxSearchQueryLength+1), "q=heist&lang=klingon",
+		"facets=maybe", "facets=true&year_bucket=0", "facets=true&year_bucket=101",
+	} {
+		req = httptest.NewRequest("GET", "/movie/all?"+query, nil)
+		w = httptest.NewRecorder()
+
+		movieHandler.GetOrderedList(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got: %d", query, w.Code)
+		}
+	}
+
+	// Both cursors
+	req = httptest.NewRequest("GET", "/movie/all?after=a&before=b", nil)
+	w = httptest.NewRecorder()
+
+	movieHandler.GetOrderedList(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got: %d", w.Code)
+	}
+}
+
+func TestMovieGetByID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ms := mocks.NewMockMovieService(ctrl)
+
+	logger, err := logger.NewLogger()
+	if err != nil {
+		t.Fatalf("can't create logger: %s", err)
+	}
+
+	movieHandler := NewMovieController(logger, ms)
+
+	getMovieByID := domain.GetMovieByID{
+		ID: 1,
+	}
+
+	req := httptest.NewRequest("GET", "/movies/1", nil)
+	w := httptest.NewRecorder()
+
+	// OK
+	ms.EXPECT().GetByID(&getMovieByID).Return(&domain.MovieWithActors{Movie: domain.Movie{ID: 1, Version: 4}}, nil)
+	movieHandler.GetByID(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got: %d", w.Code)
+	}
+
+	if etag := w.Header().Get("ETag"); etag != `"4"` {
+		t.Errorf("expected ETag \"4\", got: %s", etag)
+	}
+
+	// Incorrect ID
+	req = httptest.NewRequest("GET", "/movies/abc", nil)
+	w = httptest.NewRecorder()
+
+	movieHandler.GetByID(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got: %d", w.Code)
+	}
+
+	// Movie not found
+	req = httptest.NewRequest("GET", "/movies/1", nil)
+	w = httptest.NewRecorder()
+
+	ms.EXPECT().GetByID(&getMovieByID).Return(nil, domain.ErrNotFound)
+	movieHandler.GetByID(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got: %d", w.Code)
+	}
+
+	// GetByID returned error
+	req = httptest.NewRequest("GET", "/movies/1", nil)
+	w = httptest.NewRecorder()
+
+	ms.EXPECT().GetByID(&getMovieByID).Return(nil, domain.ErrTest)
+	movieHandler.GetByID(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500, got: %d", w.Code)
+	}
+}
+
+func TestMovieGetActors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ms := mocks.NewMockMovieService(ctrl)
+
+	logger, err := logger.NewLogger()
+	if err != nil {
+		t.Fatalf("can't create logger: %s", err)
+	}
+
+	movieHandler := NewMovieController(logger, ms)
+
+	getMovieActors := domain.GetMovieActors{
+		ID: 1,
+	}
+
+	req := httptest.NewRequest("GET", "/movies/1/actors", nil)
+	w := httptest.NewRecorder()
+
+	// OK
+	ms.EXPECT().GetActors(&getMovieActors).Return([]domain.Actor{}, nil)
+	movieHandler.GetActors(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got: %d", w.Code)
+	}
+
+	// Incorrect ID
+	req = httptest.NewRequest("GET", "/movies/abc/actors", nil)
+	w = httptest.NewRecorder()
+
+	movieHandler.GetActors(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got: %d", w.Code)
+	}
+
+	// GetActors returned error
+	req = httptest.NewRequest("GET", "/movies/1/actors", nil)
+	w = httptest.NewRecorder()
+
+	ms.EXPECT().GetActors(&getMovieActors).Return(nil, domain.ErrTest)
+	movieHandler.GetActors(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500, got: %d", w.Code)
+	}
+}
+
+func TestMovieManagePath(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ms := mocks.NewMockMovieService(ctrl)
+
+	logger, err := logger.NewLogger()
+	if err != nil {
+		t.Fatalf("can't create logger: %s", err)
+	}
+
+	movieHandler := NewMovieController(logger, ms)
+
+	// POST
+	req := httptest.NewRequest("POST", "/movie", nil)
+	w := httptest.NewRecorder()
+	movieHandler.ManagePath(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got: %d", w.Code)
+	}
+
+	// PUT
+	req = httptest.NewRequest("PUT", "/movie", nil)
+	w = httptest.NewRecorder()
+	movieHandler.ManagePath(w, req)
+
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got: %d", w.Code)
+	}
+
+	if allow := w.Header().Get("Allow"); allow != "GET, POST" {
+		t.Errorf("expected Allow: GET, POST, got: %s", allow)
+	}
+
+	problem := sender.Problem{}
+	if err = json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("can't decode problem: %s", err)
+	}
+
+	if problem.Code != "method_not_allowed" || problem.Status != http.StatusMethodNotAllowed {
+		t.Errorf("unexpected problem: %+v", problem)
+	}
+}
+
+func TestMovieManageItem(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ms := mocks.NewMockMovieService(ctrl)
+
+	logger, err := logger.NewLogger()
+	if err != nil {
+		t.Fatalf("can't create logger: %s", err)
+	}
+
+	movieHandler := NewMovieController(logger, ms)
+
+	// GET
+	ms.EXPECT().GetByID(&domain.GetMovieByID{ID: 1}).Return(&domain.MovieWithActors{}, nil)
+	req := httptest.NewRequest("GET", "/movies/1", nil)
+	w := httptest.NewRecorder()
+	movieHandler.ManageItem(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got: %d", w.Code)
+	}
+
+	// DELETE
+	ms.EXPECT().Delete(&domain.DeleteMovie{ID: 1, Version: 1}).Return(nil)
+	req = httptest.NewRequest("DELETE", "/movies/1", nil)
+	req.Header.Add("If-Match", `"1"`)
+	w = httptest.NewRecorder()
+	movieHandler.ManageItem(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got: %d", w.Code)
+	}
+
+	// PUT without body
+	req = httptest.NewRequest("PUT", "/movies/1", nil)
+	req.Header.Add("If-Match", `"1"`)
+	w = httptest.NewRecorder()
+	movieHandler.ManageItem(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got: %d", w.Code)
+	}
+
+	// GET actors
+	ms.EXPECT().GetActors(&domain.GetMovieActors{ID: 1}).Return(nil, nil)
+	req = httptest.NewRequest("GET", "/movies/1/actors", nil)
+	w = httptest.NewRecorder()
+	movieHandler.ManageItem(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got: %d", w.Code)
+	}
+
+	// Unsupported method
+	req = httptest.NewRequest("PUT", "/movies/1/actors", nil)
+	w = httptest.NewRecorder()
+	movieHandler.ManageItem(w, req)
+
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got: %d", w.Code)
+	}
+
+	if allow := w.Header().Get("Allow"); allow != "GET, POST" {
+		t.Errorf("expected Allow: GET, POST, got: %s", allow)
+	}
+
+	// PATCH
+	rating := uint8(7)
+	ms.EXPECT().Patch(&domain.PatchMovie{ID: 1, Rating: &rating, Version: 1}).Return(nil)
+	req = httptest.NewRequest("PATCH", "/movies/1", strings.NewReader(`{"rating": 7}`))
+	req.Header.Add("If-Match", `"1"`)
+	req.Header.Add("Content-type", "application/merge-patch+json")
+	w = httptest.NewRecorder()
+	movieHandler.ManageItem(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got: %d", w.Code)
+	}
+
+	// DELETE cast member
+	ms.EXPECT().RemoveActor(&domain.MovieActor{MovieID: 1, ActorID: 2, Version: 1}).Return(nil)
+	req = httptest.NewRequest("DELETE", "/movies/1/actors/2", nil)
+	req.Header.Add("If-Match", `"1"`)
+	w = httptest.NewRecorder()
+	movieHandler.ManageItem(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got: %d", w.Code)
+	}
+
+	// Incorrect ID
+	req = httptest.NewRequest("DELETE", "/movies/abc", nil)
+	req.Header.Add("If-Match", `"1"`)
+	w = httptest.NewRecorder()
+	movieHandler.ManageItem(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got: %d", w.Code)
+	}
+
+	// Unknown path
+	req = httptest.NewRequest("GET", "/movies/1/unknown", nil)
+	w = httptest.NewRecorder()
+	movieHandler.ManageItem(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got: %d", w.Code)
+	}
+}
@@ -0,0 +1,61 @@
+package restapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+)
+
+const mergePatchType = "application/merge-patch+json"
+
+// isMergePatch reports whether the request carries a JSON Merge Patch (RFC 7396).
+// Plain application/json is accepted too for clients that can't set the type.
+func isMergePatch(r *http.Request) bool {
+	contentType := r.Header.Get("Content-Type")
+	return contentType == mergePatchType || contentType == "application/json"
+}
+
+// decodeMergePatch decodes a merge patch document into dst, whose pointer fields
+// stay nil for the members the patch leaves out. A null member removes the
+// value, which only the nullable members can have, and is returned among the
+// nulls rather than decoded. Unknown members are rejected rather than silently
+// ignored.
+func decodeMergePatch(data []byte, dst interface{}, nullable ...string) (map[string]bool, error) {
+	members := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &members); err != nil {
+		return nil, fmt.Errorf("%w: %s", domain.ErrRequest, err)
+	}
+
+	nulls := map[string]bool{}
+	for name, value := range members {
+		if string(value) != "null" {
+			continue
+		}
+
+		if !contains(nullable, name) {
+			return nil, domain.NewFieldError(domain.ErrRequest, name, "can't be null")
+		}
+		nulls[name] = true
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(dst); err != nil {
+		return nil, fmt.Errorf("%w: %s", domain.ErrRequest, err)
+	}
+
+	return nulls, nil
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+
+	return false
+}
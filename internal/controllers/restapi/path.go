@@ -0,0 +1,52 @@
+package restapi
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+)
+
+const (
+	moviesPath   = "/movies/"
+	actorsPath   = "/actors/"
+	sessionsPath = "/sessions/"
+	apiKeysPath  = "/api-keys/"
+)
+
+// pathParams returns the segments of the URL path that follow prefix,
+// e.g. "/movies/1/actors" with prefix "/movies/" gives ["1", "actors"].
+func pathParams(r *http.Request, prefix string) []string {
+	rest := strings.Trim(strings.TrimPrefix(r.URL.Path, prefix), "/")
+	if rest == "" {
+		return nil
+	}
+
+	return strings.Split(rest, "/")
+}
+
+// pathID returns the resource ID that directly follows prefix in the URL path.
+func pathID(r *http.Request, prefix string) (int64, error) {
+	params := pathParams(r, prefix)
+	if len(params) == 0 {
+		return 0, domain.ErrRequest
+	}
+
+	return parseID(params[0])
+}
+
+// parseID parses a single path segment as a resource ID.
+func parseID(segment string) (int64, error) {
+	id, err := strconv.ParseInt(segment, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, domain.ErrRequest
+	}
+
+	return id, nil
+}
+
+func methodNotAllowed(w http.ResponseWriter, r *http.Request, allowed ...string) {
+	w.Header().Set("Allow", strings.Join(allowed, ", "))
+	ErrorJSON(w, r, domain.ErrMethod)
+}
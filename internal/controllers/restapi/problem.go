@@ -0,0 +1,66 @@
+package restapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+	"github.com/akrovv/filmlibrary/pkg/sender"
+)
+
+// problems maps the domain error kinds to HTTP status codes and stable
+// machine-readable codes, the first match wins.
+var problems = []struct {
+	err    error
+	status int
+	code   string
+}{
+	{domain.ErrRequest, http.StatusBadRequest, "bad_request"},
+	{domain.ErrUnauthorized, http.StatusUnauthorized, "unauthorized"},
+	{domain.ErrForbidden, http.StatusForbidden, "forbidden"},
+	{domain.ErrNotFound, http.StatusNotFound, "not_found"},
+	{domain.ErrMethod, http.StatusMethodNotAllowed, "method_not_allowed"},
+	{domain.ErrConflict, http.StatusConflict, "conflict"},
+	{domain.ErrPreconditionRequired, http.StatusPreconditionRequired, "precondition_required"},
+	{domain.ErrValidation, http.StatusUnprocessableEntity, "validation_failed"},
+}
+
+// ErrorJSON is the single way errors are sent to clients: err is turned
+// into a problem+json response with the status classifyError maps it to.
+func ErrorJSON(w http.ResponseWriter, r *http.Request, err error) {
+	sender.ErrorJSON(w, r, err, classifyError)
+}
+
+// classifyError is the sender.Classifier of the API. Malformed JSON is the
+// client's fault as well, anything unknown is an internal error.
+func classifyError(err error) (int, string, []sender.FieldError) {
+	var fields []sender.FieldError
+
+	var invalid *domain.InvalidFieldsError
+	if errors.As(err, &invalid) {
+		fields = make([]sender.FieldError, 0, len(invalid.Fields))
+		for _, field := range invalid.Fields {
+			fields = append(fields, sender.FieldError(field))
+		}
+	}
+
+	var conflict *domain.VersionConflictError
+	if errors.As(err, &conflict) {
+		return http.StatusPreconditionFailed, "version_conflict", fields
+	}
+
+	for _, p := range problems {
+		if errors.Is(err, p.err) {
+			return p.status, p.code, fields
+		}
+	}
+
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+		return http.StatusBadRequest, "bad_request", fields
+	}
+
+	return http.StatusInternalServerError, "internal", fields
+}
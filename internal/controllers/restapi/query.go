@@ -0,0 +1,109 @@
+package restapi
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+// queryInt reads a non-negative integer query parameter, returning def when it is absent.
+func queryInt(r *http.Request, name string, def int) (int, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return def, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, domain.NewFieldError(domain.ErrRequest, name, "must be a non-negative integer")
+	}
+
+	return n, nil
+}
+
+func queryLimit(r *http.Request) (int, error) {
+	limit, err := queryInt(r, "limit", defaultLimit)
+	if err != nil {
+		return 0, err
+	}
+
+	if limit == 0 || limit > maxLimit {
+		return 0, domain.NewFieldError(domain.ErrRequest, "limit", fmt.Sprintf("must be between 1 and %d", maxLimit))
+	}
+
+	return limit, nil
+}
+
+// queryPage reads the limit and offset query parameters of a paginated listing.
+func queryPage(r *http.Request) (int, int, error) {
+	limit, err := queryLimit(r)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	offset, err := queryInt(r, "offset", 0)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return limit, offset, nil
+}
+
+// queryCursor reads the limit, after and before query parameters of a
+// keyset-paginated listing.
+func queryCursor(r *http.Request) (domain.Page, error) {
+	limit, err := queryLimit(r)
+	if err != nil {
+		return domain.Page{}, err
+	}
+
+	page := domain.Page{
+		Limit:  limit,
+		After:  r.URL.Query().Get("after"),
+		Before: r.URL.Query().Get("before"),
+	}
+
+	if page.After != "" && page.Before != "" {
+		return domain.Page{}, domain.NewFieldError(domain.ErrRequest, "before", "can't be combined with after")
+	}
+
+	return page, nil
+}
+
+// querySimilarity reads the similarity threshold of a fuzzy search, zero
+// when it is absent so that the configured one applies.
+func querySimilarity(r *http.Request) (float64, error) {
+	value := r.URL.Query().Get("similarity")
+	if value == "" {
+		return 0, nil
+	}
+
+	similarity, err := strconv.ParseFloat(value, 64)
+	if err != nil || !(similarity > 0 && similarity <= 1) {
+		return 0, domain.NewFieldError(domain.ErrRequest, "similarity", "must be a number above 0 and at most 1")
+	}
+
+	return similarity, nil
+}
+
+// queryBool reads a boolean query parameter, false when it is absent.
+func queryBool(r *http.Request, name string) (bool, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return false, nil
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, domain.NewFieldError(domain.ErrRequest, name, "must be true or false")
+	}
+
+	return b, nil
+}
@@ -0,0 +1,23 @@
+package restapi
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+)
+
+var (
+	errContentType    = fmt.Errorf("%w: Content-Type must be application/json", domain.ErrRequest)
+	errMergePatchType = fmt.Errorf("%w: Content-Type must be %s", domain.ErrRequest, mergePatchType)
+)
+
+// unmarshalRequest decodes a JSON request body, a body that doesn't decode
+// is reported as an incorrect request.
+func unmarshalRequest(data []byte, v interface{}) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%w: %s", domain.ErrRequest, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package restapi
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+	"github.com/akrovv/filmlibrary/pkg/sender"
+)
+
+// querySearch reads the q and lang query parameters of a search.
+func querySearch(r *http.Request) (string, string, error) {
+	q := strings.TrimSpace(r.URL.Query().Get("q"))
+	if q == "" {
+		return "", "", domain.NewFieldError(domain.ErrRequest, "q", "is required")
+	}
+	if utf8.RuneCountInString(q) > domain.MaxSearchQueryLength {
+		return "", "", domain.NewFieldError(domain.ErrRequest, "q", fmt.Sprintf("must be at most %d characters", domain.MaxSearchQueryLength))
+	}
+
+	lang, err := queryLanguage(r)
+	if err != nil {
+		return "", "", err
+	}
+
+	return q, lang, nil
+}
+
+// queryLanguage reads the lang query parameter, the search language.
+func queryLanguage(r *http.Request) (string, error) {
+	lang := r.URL.Query().Get("lang")
+	if lang == "" {
+		return domain.SearchLanguages[0], nil
+	}
+
+	for _, known := range domain.SearchLanguages {
+		if lang == known {
+			return lang, nil
+		}
+	}
+
+	return "", domain.NewFieldError(domain.ErrRequest, "lang", "must be one of "+strings.Join(domain.SearchLanguages, ", "))
+}
+
+// @Summary Search
+// @Description  Full-text search of movie titles and descriptions, best matches first. The query takes words, "quoted phrases", or and -excluded words, which are matched by their stems in the chosen language. Matched words are wrapped in <mark></mark> in the title and snippet.
+// @Tags		 movie
+// @Produce      json
+// @Param q query string true "Search query"
+// @Param lang query string false "english (default), russian or simple, the last one without stemming"
+// @Param limit query int false "Page size (1-100, default 20)"
+// @Param offset query int false "Number of movies to skip"
+// @Success 200 {object} domain.SearchPage
+// @Failure 400 {object} sender.Problem
+// @Failure 500 {object} sender.Problem
+// @Router       /search [get]
+func (c *movieController) Search(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		methodNotAllowed(w, r, "GET")
+		return
+	}
+
+	q, lang, err := querySearch(r)
+	if err != nil {
+		c.logger.Infof("querySearch error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	limit, offset, err := queryPage(r)
+	if err != nil {
+		c.logger.Infof("queryPage error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	searchDTO := domain.SearchMovies{
+		Query:    q,
+		Language: lang,
+		Limit:    limit,
+		Offset:   offset,
+	}
+
+	page, err := c.service.Search(&searchDTO)
+	if err != nil {
+		c.logger.Infof("c.MovieService.Search error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	if err = sender.WriteJSON(w, http.StatusOK, page); err != nil {
+		c.logger.Infof("WriteJSON %w", err)
+		return
+	}
+}
@@ -0,0 +1,89 @@
+package restapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+	"github.com/akrovv/filmlibrary/internal/service/mocks"
+	"github.com/akrovv/filmlibrary/pkg/logger"
+	"github.com/golang/mock/gomock"
+)
+
+func TestMovieSearch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ms := mocks.NewMockMovieService(ctrl)
+
+	logger, err := logger.NewLogger()
+	if err != nil {
+		t.Fatalf("can't create logger: %s", err)
+	}
+
+	movieHandler := NewMovieController(logger, ms)
+
+	page := &domain.SearchPage{
+		Movies: []domain.MovieHit{{Movie: domain.Movie{ID: 1, Title: "Heat"}, Rank: 0.4, TitleHighlight: "<mark>Heat</mark>"}},
+		Total:  1,
+		Limit:  20,
+	}
+
+	// OK, English by default
+	req := httptest.NewRequest("GET", "/search?q="+url.QueryEscape(`"bank robbers" -comedy`), nil)
+	w := httptest.NewRecorder()
+
+	ms.EXPECT().Search(&domain.SearchMovies{Query: `"bank robbers" -comedy`, Language: "english", Limit: 20}).Return(page, nil)
+	movieHandler.Search(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got: %d", w.Code)
+	}
+
+	got := &domain.SearchPage{}
+	if err = json.Unmarshal(w.Body.Bytes(), got); err != nil {
+		t.Fatalf("can't decode page: %s", err)
+	}
+
+	if !reflect.DeepEqual(got, page) {
+		t.Errorf("expected: %+v, got: %+v", page, got)
+	}
+
+	// Language and page given
+	req = httptest.NewRequest("GET", "/search?q=heat&lang=russian&limit=5&offset=10", nil)
+	w = httptest.NewRecorder()
+
+	ms.EXPECT().Search(&domain.SearchMovies{Query: "heat", Language: "russian", Limit: 5, Offset: 10}).Return(page, nil)
+	movieHandler.Search(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got: %d", w.Code)
+	}
+
+	// Invalid queries
+	for _, query := range []string{"", "q=+", "q=heat&lang=klingon", "q=" + strings.Repeat("a", domain.MaxSearchQueryLength+1), "q=heat&limit=0"} {
+		req = httptest.NewRequest("GET", "/search?"+query, nil)
+		w = httptest.NewRecorder()
+
+		movieHandler.Search(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%q: expected 400, got: %d", query, w.Code)
+		}
+	}
+
+	// Wrong method
+	req = httptest.NewRequest("POST", "/search?q=heat", nil)
+	w = httptest.NewRecorder()
+
+	movieHandler.Search(w, req)
+
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got: %d", w.Code)
+	}
+}
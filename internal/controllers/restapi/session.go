@@ -0,0 +1,201 @@
+package restapi
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+	"github.com/akrovv/filmlibrary/pkg/logger"
+	"github.com/akrovv/filmlibrary/pkg/sender"
+)
+
+type sessionController struct {
+	logger  logger.Logger
+	service SessionService
+	cookie  *SessionCookie
+}
+
+func NewSessionController(logger logger.Logger, service SessionService, cookie *SessionCookie) *sessionController {
+	return &sessionController{
+		logger:  logger,
+		service: service,
+		cookie:  cookie,
+	}
+}
+
+// contextUser returns the user the Auth middleware put in the request context.
+func contextUser(r *http.Request) (*domain.User, error) {
+	var userContext domain.UserContext = "user"
+
+	user, ok := r.Context().Value(userContext).(*domain.User)
+	if !ok || user == nil {
+		return nil, domain.ErrUnauthorized
+	}
+
+	return user, nil
+}
+
+// clientIP returns the address of the peer of the request without its port.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+func (c *sessionController) ManageItem(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "DELETE":
+		c.Delete(w, r)
+	default:
+		methodNotAllowed(w, r, "DELETE")
+	}
+}
+
+// @Summary Logout
+// @Description  End the current session
+// @Tags		 user
+// @Produce      json
+// @Success 200 {object} sender.JSONResponse
+// @Failure 401 {object} sender.Problem
+// @Failure 500 {object} sender.Problem
+// @Router       /logout [post]
+func (c *sessionController) Logout(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		methodNotAllowed(w, r, "POST")
+		return
+	}
+
+	token, err := c.cookie.Token(r)
+	if err != nil || token == "" {
+		ErrorJSON(w, r, domain.ErrUnauthorized)
+		return
+	}
+
+	deleteSessionDTO := domain.DeleteSession{
+		Token: token,
+	}
+
+	if err = c.service.Delete(&deleteSessionDTO); err != nil {
+		c.logger.Infof("c.SessionService.Delete error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	c.cookie.Clear(w)
+
+	if err = sender.WriteJSON(w, http.StatusOK, struct {
+		Status  string `json:"status"`
+		Message string `json:"message"`
+	}{
+		Status:  "OK",
+		Message: "user was logout",
+	}); err != nil {
+		c.logger.Infof("WriteJSON %w", err)
+		return
+	}
+}
+
+// @Summary List
+// @Description  List the active sessions of the user, the latest first. Admins may list the sessions of anyone.
+// @Tags		 user
+// @Produce      json
+// @Param username query string false "Whose sessions to list (admins only, default the current user)"
+// @Success 200 {object} domain.SessionList
+// @Failure 401 {object} sender.Problem
+// @Failure 403 {object} sender.Problem
+// @Failure 500 {object} sender.Problem
+// @Router       /sessions [get]
+func (c *sessionController) List(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		methodNotAllowed(w, r, "GET")
+		return
+	}
+
+	user, err := contextUser(r)
+	if err != nil {
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	// The cookie is missing for the clients authenticated otherwise.
+	token, _ := c.cookie.Token(r)
+
+	listSessionsDTO := domain.ListSessions{
+		Username: user.Username,
+		Token:    token,
+	}
+
+	if username := r.URL.Query().Get("username"); username != "" && username != user.Username {
+		if !user.IsAdmin {
+			ErrorJSON(w, r, fmt.Errorf("%w: sessions of other users are listed by admins only", domain.ErrForbidden))
+			return
+		}
+
+		listSessionsDTO.Username = username
+	}
+
+	list, err := c.service.List(&listSessionsDTO)
+	if err != nil {
+		c.logger.Infof("c.SessionService.List error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	if err = sender.WriteJSON(w, http.StatusOK, list); err != nil {
+		c.logger.Infof("WriteJSON %w", err)
+		return
+	}
+}
+
+// @Summary Delete
+// @Description  Revoke a session of the user. Admins may revoke the sessions of anyone.
+// @Tags		 user
+// @Produce      json
+// @Param id path string true "Session ID"
+// @Success 200 {object} sender.JSONResponse
+// @Failure 401 {object} sender.Problem
+// @Failure 404 {object} sender.Problem
+// @Failure 500 {object} sender.Problem
+// @Router       /sessions/{id} [delete]
+func (c *sessionController) Delete(w http.ResponseWriter, r *http.Request) {
+	user, err := contextUser(r)
+	if err != nil {
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	params := pathParams(r, sessionsPath)
+	if len(params) != 1 {
+		ErrorJSON(w, r, domain.ErrNotFound)
+		return
+	}
+
+	deleteSessionDTO := domain.DeleteSession{
+		ID: params[0],
+	}
+
+	if !user.IsAdmin {
+		deleteSessionDTO.Username = user.Username
+	}
+
+	if err = c.service.Delete(&deleteSessionDTO); err != nil {
+		c.logger.Infof("c.SessionService.Delete error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	if err = sender.WriteJSON(w, http.StatusOK, struct {
+		Status  string `json:"status"`
+		Message string `json:"message"`
+	}{
+		Status:  "OK",
+		Message: "session was deleted",
+	}); err != nil {
+		c.logger.Infof("WriteJSON %w", err)
+		return
+	}
+}
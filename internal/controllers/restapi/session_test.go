@@ -0,0 +1,234 @@
+package restapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+	"github.com/akrovv/filmlibrary/internal/service/mocks"
+	"github.com/akrovv/filmlibrary/pkg/logger"
+	"github.com/golang/mock/gomock"
+)
+
+func newTestCookie(t *testing.T) *SessionCookie {
+	cookie, err := NewSessionCookie("", "/", true, true, "strict")
+	if err != nil {
+		t.Fatalf("can't create session cookie: %s", err)
+	}
+
+	return cookie
+}
+
+func withUser(r *http.Request, user *domain.User) *http.Request {
+	var userContext domain.UserContext = "user"
+	return r.WithContext(context.WithValue(r.Context(), userContext, user))
+}
+
+func TestLogout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ss := mocks.NewMockSessionService(ctrl)
+
+	logger, err := logger.NewLogger()
+	if err != nil {
+		t.Fatalf("can't create logger: %s", err)
+	}
+
+	sessionHandler := NewSessionController(logger, ss, newTestCookie(t))
+
+	// OK
+	req := httptest.NewRequest("POST", "/logout", nil)
+	req.AddCookie(&http.Cookie{Name: "session-id", Value: "token"})
+	w := httptest.NewRecorder()
+
+	ss.EXPECT().Delete(&domain.DeleteSession{Token: "token"}).Return(nil)
+	sessionHandler.Logout(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got: %d", w.Code)
+	}
+
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != "session-id" || cookies[0].MaxAge >= 0 {
+		t.Errorf("expected the session-id cookie to be cleared, got: %v", cookies)
+	}
+
+	// Service returned error
+	w = httptest.NewRecorder()
+
+	ss.EXPECT().Delete(&domain.DeleteSession{Token: "token"}).Return(domain.ErrTest)
+	sessionHandler.Logout(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500, got: %d", w.Code)
+	}
+
+	// No cookie
+	req = httptest.NewRequest("POST", "/logout", nil)
+	w = httptest.NewRecorder()
+	sessionHandler.Logout(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got: %d", w.Code)
+	}
+
+	// Wrong method
+	req = httptest.NewRequest("GET", "/logout", nil)
+	w = httptest.NewRecorder()
+	sessionHandler.Logout(w, req)
+
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got: %d", w.Code)
+	}
+}
+
+func TestListSessions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ss := mocks.NewMockSessionService(ctrl)
+
+	logger, err := logger.NewLogger()
+	if err != nil {
+		t.Fatalf("can't create logger: %s", err)
+	}
+
+	sessionHandler := NewSessionController(logger, ss, newTestCookie(t))
+
+	user := &domain.User{Username: "user"}
+	admin := &domain.User{Username: "admin", IsAdmin: true}
+	list := &domain.SessionList{Sessions: []domain.Session{
+		{ID: "abc", Username: "user", IP: "192.0.2.1", Current: true},
+	}}
+
+	// OK. Own sessions
+	req := httptest.NewRequest("GET", "/sessions", nil)
+	req.AddCookie(&http.Cookie{Name: "session-id", Value: "token"})
+	w := httptest.NewRecorder()
+
+	ss.EXPECT().List(&domain.ListSessions{Username: "user", Token: "token"}).Return(list, nil)
+	sessionHandler.List(w, withUser(req, user))
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got: %d", w.Code)
+	}
+
+	got := &domain.SessionList{}
+	if err = json.Unmarshal(w.Body.Bytes(), got); err != nil {
+		t.Fatalf("can't decode sessions: %s", err)
+	}
+
+	if !reflect.DeepEqual(got, list) {
+		t.Errorf("expected: %+v, got: %+v", list, got)
+	}
+
+	// OK. Admin lists the sessions of a user
+	req = httptest.NewRequest("GET", "/sessions?username=user", nil)
+	w = httptest.NewRecorder()
+
+	ss.EXPECT().List(&domain.ListSessions{Username: "user"}).Return(list, nil)
+	sessionHandler.List(w, withUser(req, admin))
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got: %d", w.Code)
+	}
+
+	// User lists the sessions of another user
+	req = httptest.NewRequest("GET", "/sessions?username=admin", nil)
+	w = httptest.NewRecorder()
+	sessionHandler.List(w, withUser(req, user))
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected 403, got: %d", w.Code)
+	}
+
+	// Service returned error
+	req = httptest.NewRequest("GET", "/sessions", nil)
+	w = httptest.NewRecorder()
+
+	ss.EXPECT().List(&domain.ListSessions{Username: "user"}).Return(nil, domain.ErrTest)
+	sessionHandler.List(w, withUser(req, user))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500, got: %d", w.Code)
+	}
+
+	// No user in the context
+	w = httptest.NewRecorder()
+	sessionHandler.List(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got: %d", w.Code)
+	}
+}
+
+func TestDeleteSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ss := mocks.NewMockSessionService(ctrl)
+
+	logger, err := logger.NewLogger()
+	if err != nil {
+		t.Fatalf("can't create logger: %s", err)
+	}
+
+	sessionHandler := NewSessionController(logger, ss, newTestCookie(t))
+
+	user := &domain.User{Username: "user"}
+	admin := &domain.User{Username: "admin", IsAdmin: true}
+
+	// OK. Users revoke their own sessions only
+	req := httptest.NewRequest("DELETE", "/sessions/abc", nil)
+	w := httptest.NewRecorder()
+
+	ss.EXPECT().Delete(&domain.DeleteSession{ID: "abc", Username: "user"}).Return(nil)
+	sessionHandler.ManageItem(w, withUser(req, user))
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got: %d", w.Code)
+	}
+
+	// OK. Admins revoke any session
+	w = httptest.NewRecorder()
+
+	ss.EXPECT().Delete(&domain.DeleteSession{ID: "abc"}).Return(nil)
+	sessionHandler.ManageItem(w, withUser(req, admin))
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got: %d", w.Code)
+	}
+
+	// Session not found
+	w = httptest.NewRecorder()
+
+	ss.EXPECT().Delete(&domain.DeleteSession{ID: "abc", Username: "user"}).Return(domain.ErrNotFound)
+	sessionHandler.ManageItem(w, withUser(req, user))
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got: %d", w.Code)
+	}
+
+	// No session ID
+	req = httptest.NewRequest("DELETE", "/sessions/", nil)
+	w = httptest.NewRecorder()
+	sessionHandler.ManageItem(w, withUser(req, user))
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got: %d", w.Code)
+	}
+
+	// Wrong method
+	req = httptest.NewRequest("GET", "/sessions/abc", nil)
+	w = httptest.NewRecorder()
+	sessionHandler.ManageItem(w, withUser(req, user))
+
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got: %d", w.Code)
+	}
+}
@@ -0,0 +1,117 @@
+package restapi
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+	"github.com/akrovv/filmlibrary/pkg/logger"
+	"github.com/akrovv/filmlibrary/pkg/sender"
+)
+
+const defaultSuggestLimit = 10
+
+type suggestController struct {
+	logger  logger.Logger
+	service SuggestService
+}
+
+func NewSuggestController(logger logger.Logger, service SuggestService) *suggestController {
+	return &suggestController{
+		logger:  logger,
+		service: service,
+	}
+}
+
+// querySuggest reads the q, types and limit query parameters of a typeahead.
+// The types are kept in the order of domain.SuggestTypes whatever order they
+// are given in, so that equal requests are cached alike.
+func querySuggest(r *http.Request) (*domain.Suggest, error) {
+	q := strings.TrimSpace(r.URL.Query().Get("q"))
+	if q == "" {
+		return nil, domain.NewFieldError(domain.ErrRequest, "q", "is required")
+	}
+	if utf8.RuneCountInString(q) < domain.MinSuggestLength {
+		return nil, domain.NewFieldError(domain.ErrRequest, "q", fmt.Sprintf("must be at least %d characters", domain.MinSuggestLength))
+	}
+	if utf8.RuneCountInString(q) > domain.MaxSearchQueryLength {
+		return nil, domain.NewFieldError(domain.ErrRequest, "q", fmt.Sprintf("must be at most %d characters", domain.MaxSearchQueryLength))
+	}
+
+	limit, err := queryInt(r, "limit", defaultSuggestLimit)
+	if err != nil {
+		return nil, err
+	}
+	if limit == 0 || limit > domain.MaxSuggestLimit {
+		return nil, domain.NewFieldError(domain.ErrRequest, "limit", fmt.Sprintf("must be between 1 and %d", domain.MaxSuggestLimit))
+	}
+
+	dto := domain.Suggest{
+		Prefix: q,
+		Types:  domain.SuggestTypes,
+		Limit:  limit,
+	}
+
+	types := r.URL.Query().Get("types")
+	if types == "" {
+		return &dto, nil
+	}
+
+	wanted := make(map[string]bool)
+	for _, kind := range strings.Split(types, ",") {
+		wanted[strings.TrimSpace(kind)] = true
+	}
+
+	dto.Types = make([]string, 0, len(domain.SuggestTypes))
+	for _, kind := range domain.SuggestTypes {
+		if wanted[kind] {
+			dto.Types = append(dto.Types, kind)
+			delete(wanted, kind)
+		}
+	}
+
+	if len(wanted) > 0 || len(dto.Types) == 0 {
+		return nil, domain.NewFieldError(domain.ErrRequest, "types", "must be a list of "+strings.Join(domain.SuggestTypes, ", "))
+	}
+
+	return &dto, nil
+}
+
+// @Summary Suggest
+// @Description  Typeahead suggestions of movie titles and actor names starting with q, or having a word starting with it when q is at least 3 characters long. Exact matches come first, then the titles and names starting with q, then the rest, the shortest first.
+// @Tags		 movie
+// @Produce      json
+// @Param q query string true "Prefix typed so far, at least 2 characters"
+// @Param types query string false "Comma-separated suggestion types: movie, actor (default both)"
+// @Param limit query int false "Number of suggestions (1-20, default 10)"
+// @Success 200 {object} domain.SuggestList
+// @Failure 400 {object} sender.Problem
+// @Failure 500 {object} sender.Problem
+// @Router       /suggest [get]
+func (c *suggestController) Suggest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		methodNotAllowed(w, r, "GET")
+		return
+	}
+
+	suggestDTO, err := querySuggest(r)
+	if err != nil {
+		c.logger.Infof("querySuggest error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	list, err := c.service.Suggest(suggestDTO)
+	if err != nil {
+		c.logger.Infof("c.SuggestService.Suggest error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	if err = sender.WriteJSON(w, http.StatusOK, list); err != nil {
+		c.logger.Infof("WriteJSON %w", err)
+		return
+	}
+}
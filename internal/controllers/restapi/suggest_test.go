@@ -0,0 +1,108 @@
+package restapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+	"github.com/akrovv/filmlibrary/internal/service/mocks"
+	"github.com/akrovv/filmlibrary/pkg/logger"
+	"github.com/golang/mock/gomock"
+)
+
+func TestSuggest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ss := mocks.NewMockSuggestService(ctrl)
+
+	logger, err := logger.NewLogger()
+	if err != nil {
+		t.Fatalf("can't create logger: %s", err)
+	}
+
+	suggestHandler := NewSuggestController(logger, ss)
+
+	list := &domain.SuggestList{Suggestions: []domain.Suggestion{
+		{Type: "movie", ID: 3, Text: "Cars"},
+		{Type: "actor", ID: 7, Text: "Cate Blanchett"},
+	}}
+
+	// OK. Every type by default
+	req := httptest.NewRequest("GET", "/suggest?q=ca", nil)
+	w := httptest.NewRecorder()
+
+	ss.EXPECT().Suggest(&domain.Suggest{Prefix: "ca", Types: []string{"movie", "actor"}, Limit: 10}).Return(list, nil)
+	suggestHandler.Suggest(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got: %d", w.Code)
+	}
+
+	got := &domain.SuggestList{}
+	if err = json.Unmarshal(w.Body.Bytes(), got); err != nil {
+		t.Fatalf("can't decode suggestions: %s", err)
+	}
+
+	if !reflect.DeepEqual(got, list) {
+		t.Errorf("expected: %+v, got: %+v", list, got)
+	}
+
+	// OK. Types in any order and limit
+	req = httptest.NewRequest("GET", "/suggest?q=ca&types=actor,movie,actor&limit=3", nil)
+	w = httptest.NewRecorder()
+
+	ss.EXPECT().Suggest(&domain.Suggest{Prefix: "ca", Types: []string{"movie", "actor"}, Limit: 3}).Return(list, nil)
+	suggestHandler.Suggest(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got: %d", w.Code)
+	}
+
+	// OK. Actors only
+	req = httptest.NewRequest("GET", "/suggest?q=ca&types=actor", nil)
+	w = httptest.NewRecorder()
+
+	ss.EXPECT().Suggest(&domain.Suggest{Prefix: "ca", Types: []string{"actor"}, Limit: 10}).Return(&domain.SuggestList{}, nil)
+	suggestHandler.Suggest(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got: %d", w.Code)
+	}
+
+	// Suggest returned error
+	req = httptest.NewRequest("GET", "/suggest?q=ca", nil)
+	w = httptest.NewRecorder()
+
+	ss.EXPECT().Suggest(gomock.Any()).Return(nil, domain.ErrTest)
+	suggestHandler.Suggest(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500, got: %d", w.Code)
+	}
+
+	// Bad request
+	for _, query := range []string{"", "q=+", "q=c", "q=+%D1%8F+", "q=ca&types=genre", "q=ca&types=movie,genre", "q=ca&types=,", "q=ca&limit=0", "q=ca&limit=21"} {
+		req = httptest.NewRequest("GET", "/suggest?"+query, nil)
+		w = httptest.NewRecorder()
+
+		suggestHandler.Suggest(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got: %d", query, w.Code)
+		}
+	}
+
+	// Wrong method
+	req = httptest.NewRequest("POST", "/suggest?q=ca", nil)
+	w = httptest.NewRecorder()
+
+	suggestHandler.Suggest(w, req)
+
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got: %d", w.Code)
+	}
+}
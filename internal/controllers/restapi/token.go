@@ -0,0 +1,75 @@
+package restapi
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+	"github.com/akrovv/filmlibrary/pkg/logger"
+	"github.com/akrovv/filmlibrary/pkg/sender"
+)
+
+type tokenController struct {
+	logger  logger.Logger
+	service TokenService
+}
+
+func NewTokenController(logger logger.Logger, service TokenService) *tokenController {
+	return &tokenController{
+		logger:  logger,
+		service: service,
+	}
+}
+
+// @Summary Token
+// @Description  Issue an access token to send as Authorization: Bearer, with grant_type password and the credentials of a user or with grant_type refresh_token and a refresh token. A refresh token is good for one use, the response carries the next one.
+// @Tags		 user
+// @Accept       json
+// @Produce      json
+// @Param request body domain.TokenRequest true "request"
+// @Success 200 {object} domain.TokenPair
+// @Failure 400 {object} sender.Problem
+// @Failure 401 {object} sender.Problem
+// @Failure 422 {object} sender.Problem
+// @Failure 500 {object} sender.Problem
+// @Router       /token [post]
+func (c *tokenController) Token(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		methodNotAllowed(w, r, "POST")
+		return
+	}
+
+	if r.Header.Get("Content-Type") != "application/json" {
+		c.logger.Infof("request didnt contain application/json")
+		ErrorJSON(w, r, errContentType)
+		return
+	}
+
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		c.logger.Infof("io.ReadAll error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+	defer r.Body.Close()
+
+	tokenRequestDTO := domain.TokenRequest{}
+	if err = unmarshalRequest(data, &tokenRequestDTO); err != nil {
+		c.logger.Infof("unmarshalRequest error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	pair, err := c.service.Issue(&tokenRequestDTO)
+	if err != nil {
+		c.logger.Infof("c.TokenService.Issue error: %w", err)
+		ErrorJSON(w, r, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	if err = sender.WriteJSON(w, http.StatusOK, pair); err != nil {
+		c.logger.Infof("WriteJSON %w", err)
+		return
+	}
+}
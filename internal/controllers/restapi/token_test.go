@@ -0,0 +1,113 @@
+package restapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+	"github.com/akrovv/filmlibrary/internal/service/mocks"
+	"github.com/akrovv/filmlibrary/pkg/logger"
+	"github.com/golang/mock/gomock"
+)
+
+func TestToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ts := mocks.NewMockTokenService(ctrl)
+
+	logger, err := logger.NewLogger()
+	if err != nil {
+		t.Fatalf("can't create logger: %s", err)
+	}
+
+	tokenHandler := NewTokenController(logger, ts)
+
+	pair := &domain.TokenPair{
+		AccessToken:  "access",
+		TokenType:    "Bearer",
+		ExpiresIn:    900,
+		RefreshToken: "refresh",
+	}
+
+	// OK. Password grant
+	req := httptest.NewRequest("POST", "/token", strings.NewReader(`{"grant_type": "password", "username": "user", "password": "user"}`))
+	req.Header.Add("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	ts.EXPECT().Issue(&domain.TokenRequest{GrantType: "password", Username: "user", Password: "user"}).Return(pair, nil)
+	tokenHandler.Token(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got: %d", w.Code)
+	}
+
+	if w.Header().Get("Cache-Control") != "no-store" {
+		t.Errorf("expected Cache-Control: no-store, got: %s", w.Header().Get("Cache-Control"))
+	}
+
+	got := &domain.TokenPair{}
+	if err = json.Unmarshal(w.Body.Bytes(), got); err != nil {
+		t.Fatalf("can't decode token pair: %s", err)
+	}
+
+	if !reflect.DeepEqual(got, pair) {
+		t.Errorf("expected: %+v, got: %+v", pair, got)
+	}
+
+	// OK. Refresh token grant
+	req = httptest.NewRequest("POST", "/token", strings.NewReader(`{"grant_type": "refresh_token", "refresh_token": "refresh"}`))
+	req.Header.Add("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+
+	ts.EXPECT().Issue(&domain.TokenRequest{GrantType: "refresh_token", RefreshToken: "refresh"}).Return(pair, nil)
+	tokenHandler.Token(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got: %d", w.Code)
+	}
+
+	// Service returned error
+	req = httptest.NewRequest("POST", "/token", strings.NewReader(`{"grant_type": "refresh_token", "refresh_token": "spent"}`))
+	req.Header.Add("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+
+	ts.EXPECT().Issue(&domain.TokenRequest{GrantType: "refresh_token", RefreshToken: "spent"}).Return(nil, domain.ErrUnauthorized)
+	tokenHandler.Token(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got: %d", w.Code)
+	}
+
+	// Incorrect JSON
+	req = httptest.NewRequest("POST", "/token", strings.NewReader(`{"grant_type": {`))
+	req.Header.Add("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	tokenHandler.Token(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got: %d", w.Code)
+	}
+
+	// Missed Content-Type
+	req = httptest.NewRequest("POST", "/token", strings.NewReader(`{}`))
+	w = httptest.NewRecorder()
+	tokenHandler.Token(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got: %d", w.Code)
+	}
+
+	// Wrong method
+	req = httptest.NewRequest("GET", "/token", nil)
+	w = httptest.NewRecorder()
+	tokenHandler.Token(w, req)
+
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got: %d", w.Code)
+	}
+}
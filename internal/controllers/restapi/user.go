@@ -1,10 +1,8 @@
 package restapi
 
 import (
-	"encoding/json"
 	"io"
 	"net/http"
-	"time"
 
 	"github.com/akrovv/filmlibrary/internal/domain"
 	"github.com/akrovv/filmlibrary/pkg/logger"
@@ -15,22 +13,25 @@ type userController struct {
 	logger         logger.Logger
 	userService    UserService
 	sessionService SessionService
+	cookie         *SessionCookie
 }
 
 func NewUserController(logger logger.Logger,
 	userService UserService,
-	sessionService SessionService) *userController {
+	sessionService SessionService,
+	cookie *SessionCookie) *userController {
 	return &userController{
 		logger:         logger,
 		userService:    userService,
 		sessionService: sessionService,
+		cookie:         cookie,
 	}
 }
 
 func userUnmarshal(data []byte) (*domain.CRUser, error) {
 	user := &domain.CRUser{}
 
-	err := json.Unmarshal(data, user)
+	err := unmarshalRequest(data, user)
 
 	if err != nil {
 		return nil, err
@@ -46,56 +47,55 @@ func userUnmarshal(data []byte) (*domain.CRUser, error) {
 // @Produce      json
 // @Param request body domain.CRUser true "request"
 // @Success 201 {object} sender.JSONResponse
-// @Failure 400 {object} sender.JSONResponse
-// @Failure 500 {object} sender.JSONResponse
+// @Failure 400 {object} sender.Problem
+// @Failure 422 {object} sender.Problem
+// @Failure 409 {object} sender.Problem
+// @Failure 500 {object} sender.Problem
 // @Router       /register [post]
 func (c *userController) Register(w http.ResponseWriter, r *http.Request) {
 	if r.Header.Get("Content-Type") != "application/json" {
 		c.logger.Infof("request didnt contain application/json")
-		sender.ErrorJSON(w, domain.ErrRequest, http.StatusBadRequest)
+		ErrorJSON(w, r, errContentType)
 		return
 	}
 
 	data, err := io.ReadAll(r.Body)
 	if err != nil {
 		c.logger.Infof("io.ReadAll error: %w", err)
-		sender.ErrorJSON(w, err, http.StatusInternalServerError)
+		ErrorJSON(w, r, err)
 		return
 	}
 	defer r.Body.Close()
 
 	crUserDTO, err := userUnmarshal(data)
 	if err != nil {
-		c.logger.Infof("json.Unmarshal error: %w", err)
-		sender.ErrorJSON(w, err, http.StatusInternalServerError)
+		c.logger.Infof("unmarshalRequest error: %w", err)
+		ErrorJSON(w, r, err)
 		return
 	}
 
 	err = c.userService.Register(crUserDTO)
 	if err != nil {
 		c.logger.Infof("c.UserService.Register %w", err)
-		sender.ErrorJSON(w, err, http.StatusInternalServerError)
+		ErrorJSON(w, r, err)
 		return
 	}
 
 	createSessionDTO := domain.CreateSession{
-		Username: crUserDTO.Username,
+		Username:  crUserDTO.Username,
+		IP:        clientIP(r),
+		UserAgent: r.UserAgent(),
 	}
 
-	id, err := c.sessionService.Create(&createSessionDTO)
+	session, err := c.sessionService.Create(&createSessionDTO)
 	if err != nil {
 		c.logger.Infof("c.SessionService.Create %w", err)
-		sender.ErrorJSON(w, err, http.StatusInternalServerError)
+		ErrorJSON(w, r, err)
 		return
 	}
 
-	c.logger.Infof("created session for user: [%s] with session-id: [%s]", crUserDTO.Username, id)
-	http.SetCookie(w, &http.Cookie{
-		Name:     "session-id",
-		Value:    id,
-		Expires:  time.Now().Add(time.Hour * 8),
-		HttpOnly: true,
-	})
+	c.logger.Infof("created session for user: [%s]", crUserDTO.Username)
+	c.cookie.Set(w, session.Token, session.ExpiresAt)
 
 	if err = sender.WriteJSON(w, http.StatusCreated, struct {
 		Status  string `json:"status"`
@@ -116,57 +116,55 @@ func (c *userController) Register(w http.ResponseWriter, r *http.Request) {
 // @Produce      json
 // @Param request body domain.CRUser true "request"
 // @Success 201 {object} sender.JSONResponse
-// @Failure 400 {object} sender.JSONResponse
-// @Failure 500 {object} sender.JSONResponse
+// @Failure 400 {object} sender.Problem
+// @Failure 401 {object} sender.Problem
+// @Failure 500 {object} sender.Problem
 // @Router       /login [post]
 func (c *userController) Login(w http.ResponseWriter, r *http.Request) {
 	if r.Header.Get("Content-Type") != "application/json" {
 		c.logger.Infof("request didnt contain application/json")
-		sender.ErrorJSON(w, domain.ErrRequest, http.StatusBadRequest)
+		ErrorJSON(w, r, errContentType)
 		return
 	}
 
 	data, err := io.ReadAll(r.Body)
 	if err != nil {
 		c.logger.Infof("io.ReadAll error: %w", err)
-		sender.ErrorJSON(w, err, http.StatusInternalServerError)
+		ErrorJSON(w, r, err)
 		return
 	}
 	defer r.Body.Close()
 
 	crUserDTO, err := userUnmarshal(data)
 	if err != nil {
-		c.logger.Infof("json.Unmarshal error: %w", err)
-		sender.ErrorJSON(w, err, http.StatusInternalServerError)
+		c.logger.Infof("unmarshalRequest error: %w", err)
+		ErrorJSON(w, r, err)
 		return
 	}
 
 	user, err := c.userService.Login(crUserDTO)
 	if err != nil {
 		c.logger.Infof("c.UserService.Login error: %w", err)
-		sender.ErrorJSON(w, err, http.StatusInternalServerError)
+		ErrorJSON(w, r, err)
 		return
 	}
 
 	createSessionDTO := domain.CreateSession{
-		Username: user.Username,
-		IsAdmin:  user.IsAdmin,
+		Username:  user.Username,
+		IsAdmin:   user.IsAdmin,
+		IP:        clientIP(r),
+		UserAgent: r.UserAgent(),
 	}
 
-	id, err := c.sessionService.Create(&createSessionDTO)
+	session, err := c.sessionService.Create(&createSessionDTO)
 	if err != nil {
 		c.logger.Infof("c.SessionService.Create %w", err)
-		sender.ErrorJSON(w, err, http.StatusInternalServerError)
+		ErrorJSON(w, r, err)
 		return
 	}
-	c.logger.Infof("created session for user: [%s] with session-id: [%s]", user.Username, id)
+	c.logger.Infof("created session for user: [%s]", user.Username)
 
-	http.SetCookie(w, &http.Cookie{
-		Name:     "session-id",
-		Value:    id,
-		Expires:  time.Now().Add(time.Hour * 8),
-		HttpOnly: true,
-	})
+	c.cookie.Set(w, session.Token, session.ExpiresAt)
 
 	if err = sender.WriteJSON(w, http.StatusCreated, struct {
 		Status  string `json:"status"`
@@ -5,6 +5,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/akrovv/filmlibrary/internal/domain"
 	"github.com/akrovv/filmlibrary/internal/service/mocks"
@@ -24,7 +25,7 @@ func TestRegister(t *testing.T) {
 		t.Fatalf("can't create logger: %s", err)
 	}
 
-	userHandler := NewUserController(logger, us, ss)
+	userHandler := NewUserController(logger, us, ss, newTestCookie(t))
 
 	body := `{
 		"username": "user",
@@ -37,6 +38,7 @@ func TestRegister(t *testing.T) {
 	}
 	createSession := &domain.CreateSession{
 		Username: "user",
+		IP:       "192.0.2.1",
 	}
 
 	req := httptest.NewRequest("POST", "/register", strings.NewReader(body))
@@ -45,7 +47,7 @@ func TestRegister(t *testing.T) {
 
 	// OK
 	us.EXPECT().Register(createUser).Return(nil)
-	ss.EXPECT().Create(createSession).Return("id", nil)
+	ss.EXPECT().Create(createSession).Return(&domain.ActiveSession{Token: "id", ExpiresAt: time.Now().Add(time.Hour)}, nil)
 
 	userHandler.Register(w, req)
 
@@ -71,8 +73,8 @@ func TestRegister(t *testing.T) {
 
 	userHandler.Register(w, req)
 
-	if w.Code != http.StatusInternalServerError {
-		t.Errorf("expected 500, got: %d", w.Code)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got: %d", w.Code)
 		return
 	}
 
@@ -101,11 +103,25 @@ func TestRegister(t *testing.T) {
 		return
 	}
 
+	// Username already taken
+	req = httptest.NewRequest("POST", "/register", strings.NewReader(body))
+	req.Header.Add("Content-type", "application/json")
+	w = httptest.NewRecorder()
+
+	us.EXPECT().Register(createUser).Return(domain.ErrConflict)
+	userHandler.Register(w, req)
+
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected 409, got: %d", w.Code)
+		return
+	}
+
 	// Create Session returned error
 	req = httptest.NewRequest("POST", "/register", strings.NewReader(body))
 	req.Header.Add("Content-type", "application/json")
+	w = httptest.NewRecorder()
 	us.EXPECT().Register(createUser).Return(nil)
-	ss.EXPECT().Create(createSession).Return("id", domain.ErrTest)
+	ss.EXPECT().Create(createSession).Return(nil, domain.ErrTest)
 
 	userHandler.Register(w, req)
 
@@ -127,7 +143,7 @@ func TestLogin(t *testing.T) {
 		t.Fatalf("can't create logger: %s", err)
 	}
 
-	userHandler := NewUserController(logger, us, ss)
+	userHandler := NewUserController(logger, us, ss, newTestCookie(t))
 
 	body := `{
 		"username": "user",
@@ -144,6 +160,7 @@ func TestLogin(t *testing.T) {
 	}
 	createSession := &domain.CreateSession{
 		Username: "user",
+		IP:       "192.0.2.1",
 	}
 
 	req := httptest.NewRequest("POST", "/login", strings.NewReader(body))
@@ -152,7 +169,7 @@ func TestLogin(t *testing.T) {
 
 	// OK
 	us.EXPECT().Login(createUser).Return(user, nil)
-	ss.EXPECT().Create(createSession).Return("id", nil)
+	ss.EXPECT().Create(createSession).Return(&domain.ActiveSession{Token: "id", ExpiresAt: time.Now().Add(time.Hour)}, nil)
 
 	userHandler.Login(w, req)
 
@@ -161,6 +178,12 @@ func TestLogin(t *testing.T) {
 		return
 	}
 
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Value != "id" || !cookies[0].Secure || !cookies[0].HttpOnly ||
+		cookies[0].SameSite != http.SameSiteStrictMode || cookies[0].Path != "/" {
+		t.Errorf("expected a hardened session-id cookie, got: %v", cookies)
+	}
+
 	// Missed Content-Type
 	req.Header.Del("Content-type")
 	w = httptest.NewRecorder()
@@ -178,8 +201,8 @@ func TestLogin(t *testing.T) {
 
 	userHandler.Login(w, req)
 
-	if w.Code != http.StatusInternalServerError {
-		t.Errorf("expected 500, got: %d", w.Code)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got: %d", w.Code)
 		return
 	}
 
@@ -208,11 +231,25 @@ func TestLogin(t *testing.T) {
 		return
 	}
 
+	// Wrong password
+	req = httptest.NewRequest("POST", "/login", strings.NewReader(body))
+	req.Header.Add("Content-type", "application/json")
+	w = httptest.NewRecorder()
+
+	us.EXPECT().Login(createUser).Return(nil, domain.ErrUnauthorized)
+	userHandler.Login(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got: %d", w.Code)
+		return
+	}
+
 	// Create Session returned error
 	req = httptest.NewRequest("POST", "/login", strings.NewReader(body))
 	req.Header.Add("Content-type", "application/json")
+	w = httptest.NewRecorder()
 	us.EXPECT().Login(createUser).Return(user, nil)
-	ss.EXPECT().Create(createSession).Return("id", domain.ErrTest)
+	ss.EXPECT().Create(createSession).Return(nil, domain.ErrTest)
 
 	userHandler.Login(w, req)
 
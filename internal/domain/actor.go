@@ -7,6 +7,7 @@ type Actor struct {
 	Name      string    `json:"actor_name"`
 	Gender    string    `json:"gender"`
 	DateBirth time.Time `json:"date_of_birth"`
+	Version   int64     `json:"-"`
 }
 
 type CreateActor struct {
@@ -15,11 +16,47 @@ type CreateActor struct {
 	DateBirth time.Time `json:"date_of_birth"`
 }
 
+// PatchActor is a JSON Merge Patch of an actor: nil fields are left untouched.
+type PatchActor struct {
+	ID        int64      `json:"-"`
+	Name      *string    `json:"actor_name"`
+	Gender    *string    `json:"gender"`
+	DateBirth *time.Time `json:"date_of_birth"`
+	Version   int64      `json:"-"`
+
+	// NullDateBirth clears the date of birth, which a patch sets to null.
+	NullDateBirth bool `json:"-"`
+}
+
 type DeleteActor struct {
-	ID int64 `json:"actor_id"`
+	ID      int64 `json:"actor_id"`
+	Version int64 `json:"-"`
+}
+
+type GetActor struct {
+	ID int64
+}
+
+type GetActorMovies struct {
+	ID int64
+}
+
+type ActorMovie struct {
+	ID          int64     `json:"movie_id"`
+	Title       string    `json:"movie_title"`
+	ReleaseDate time.Time `json:"release_date"`
+}
+
+type ActorWithMovies struct {
+	Actor
+	Movies []ActorMovie `json:"movies"`
+}
+
+type GetActorList struct {
+	Page Page
 }
 
-type ActorWithMovie struct {
-	CreateActor
-	Title string
+type ActorList struct {
+	Actors []ActorWithMovies `json:"actors"`
+	Cursors
 }
@@ -0,0 +1,65 @@
+package domain
+
+import "time"
+
+// APIKeyScopes lists the scopes an API key can be given, rbac_scopes.csv
+// tells the requests each of them allows.
+var APIKeyScopes = []string{
+	"movies:read",
+	"movies:write",
+	"actors:read",
+	"actors:write",
+	"catalog:import",
+	"catalog:export",
+}
+
+const MaxAPIKeyNameLength = 100
+
+// CreateAPIKey creates a key of Username, who comes from the request
+// context rather than the body.
+type CreateAPIKey struct {
+	Username string   `json:"-"`
+	Name     string   `json:"name"`
+	Scopes   []string `json:"scopes"`
+}
+
+type APIKey struct {
+	ID         int64      `json:"id"`
+	Username   string     `json:"username"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+}
+
+// CreatedAPIKey is a key just created, the only time Key is known.
+type CreatedAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+type APIKeyList struct {
+	Keys []APIKey `json:"api_keys"`
+}
+
+type ListAPIKeys struct {
+	Username string
+}
+
+// DeleteAPIKey revokes a key. When Username is set, the key must belong to
+// that user.
+type DeleteAPIKey struct {
+	ID       int64
+	Username string
+}
+
+type GetAPIKey struct {
+	Key string
+}
+
+// ScopedUser is the owner of an API key, limited to the scopes of the key.
+type ScopedUser struct {
+	User   *User
+	Scopes []string
+}
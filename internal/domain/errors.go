@@ -1,6 +1,75 @@
 package domain
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
 
 var ErrTest error = errors.New("some error")
 var ErrRequest error = errors.New("incorrect request")
+var ErrNotFound error = errors.New("resource not found")
+var ErrMethod error = errors.New("method not allowed")
+var ErrPreconditionRequired error = errors.New("If-Match header is required")
+
+// The errors below classify failures of the services and storages, wrap them
+// with fmt.Errorf("%w: ...") to add details.
+var ErrValidation error = errors.New("validation failed")
+var ErrConflict error = errors.New("resource conflict")
+var ErrUnauthorized error = errors.New("unauthorized")
+var ErrForbidden error = errors.New("forbidden")
+
+// VersionConflictError is returned by a conditional write when the resource
+// was changed since the version the client based the write on. A zero
+// Version means the client named no version the resource can have.
+type VersionConflictError struct {
+	Resource string
+	ID       int64
+	Version  int64
+}
+
+func (e *VersionConflictError) Error() string {
+	if e.Version == 0 {
+		return fmt.Sprintf("%s %d doesn't match the If-Match entity tag", e.Resource, e.ID)
+	}
+
+	return fmt.Sprintf("%s %d was modified, current version is %d", e.Resource, e.ID, e.Version)
+}
+
+func (e *VersionConflictError) Unwrap() error {
+	return ErrConflict
+}
+
+// FieldError describes what is wrong with a single field of a request.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// InvalidFieldsError names every field of a request at fault. Kind is
+// ErrRequest for malformed input and ErrValidation for input breaking the rules.
+type InvalidFieldsError struct {
+	Kind   error
+	Fields []FieldError
+}
+
+// NewFieldError returns an InvalidFieldsError of kind with a single field.
+func NewFieldError(kind error, field, message string) *InvalidFieldsError {
+	return &InvalidFieldsError{
+		Kind:   kind,
+		Fields: []FieldError{{Field: field, Message: message}},
+	}
+}
+
+func (e *InvalidFieldsError) Error() string {
+	messages := make([]string, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		messages = append(messages, field.Field+": "+field.Message)
+	}
+
+	return fmt.Sprintf("%s: %s", e.Kind, strings.Join(messages, "; "))
+}
+
+func (e *InvalidFieldsError) Unwrap() error {
+	return e.Kind
+}
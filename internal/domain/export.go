@@ -0,0 +1,43 @@
+package domain
+
+import "time"
+
+// ExportManifest opens an export. SchemaVersion is the latest migration the
+// database had, the counts let a reader check it got the whole catalogue.
+type ExportManifest struct {
+	SchemaVersion int64
+	ExportedAt    time.Time
+	Movies        int64
+	Actors        int64
+	MovieActors   int64
+}
+
+// ExportActor is an actor as stored, zero DateBirth and empty Gender stand
+// for NULL.
+type ExportActor struct {
+	ID         int64
+	ExternalID string
+	Name       string
+	Gender     string
+	DateBirth  time.Time
+}
+
+// ExportMovie is a movie as stored with its cast, zero ReleaseDate and Rating
+// stand for NULL.
+type ExportMovie struct {
+	ID          int64
+	ExternalID  string
+	Title       string
+	Description string
+	ReleaseDate time.Time
+	Rating      uint8
+	Actors      []ExportActor
+}
+
+// ExportWriter receives the catalogue as it is read: the manifest first, then
+// every actor, then every movie with its cast.
+type ExportWriter interface {
+	WriteManifest(manifest *ExportManifest) error
+	WriteActor(actor *ExportActor) error
+	WriteMovie(movie *ExportMovie) error
+}
@@ -0,0 +1,42 @@
+package domain
+
+// IMDbTitle is a title of title.basics.tsv, a zero Year isn't known.
+type IMDbTitle struct {
+	TConst string
+	Title  string
+	Year   int
+}
+
+// IMDbName is a person of name.basics.tsv, a zero BirthYear isn't known.
+type IMDbName struct {
+	NConst    string
+	Name      string
+	BirthYear int
+}
+
+// IMDbPrincipal is a cast member of a title from title.principals.tsv, the
+// gender follows from whether they are credited as an actor or an actress.
+type IMDbPrincipal struct {
+	TConst string
+	NConst string
+	Gender string
+}
+
+// IMDbSource reads the files of an IMDb dataset in the order they are
+// listed, each of them keeping only the rows that belong to those before.
+type IMDbSource interface {
+	Titles(fn func(title *IMDbTitle) error) error
+	Principals(fn func(principal *IMDbPrincipal) error) error
+	Names(fn func(name *IMDbName) error) error
+}
+
+type IMDbReport struct {
+	Titles        int64 `json:"titles"`
+	Principals    int64 `json:"principals"`
+	Names         int64 `json:"names"`
+	MoviesCreated int64 `json:"movies_created"`
+	MoviesUpdated int64 `json:"movies_updated"`
+	ActorsCreated int64 `json:"actors_created"`
+	ActorsUpdated int64 `json:"actors_updated"`
+	LinksCreated  int64 `json:"links_created"`
+}
@@ -0,0 +1,82 @@
+package domain
+
+import "time"
+
+// ImportActor is a cast member of an imported movie, found by its external ID
+// or else by name, and created when there is none.
+type ImportActor struct {
+	ExternalID string    `json:"external_id,omitempty"`
+	Name       string    `json:"actor_name,omitempty"`
+	Gender     string    `json:"gender,omitempty"`
+	DateBirth  time.Time `json:"date_of_birth,omitempty"`
+}
+
+// ImportMovie is a movie of an import file. It is matched by its external ID
+// or else by title and release date. A zero ReleaseDate or Rating stands for
+// NULL, as in an export.
+type ImportMovie struct {
+	ExternalID  string        `json:"external_id,omitempty"`
+	Title       string        `json:"movie_title"`
+	Description string        `json:"description"`
+	ReleaseDate time.Time     `json:"release_date"`
+	Rating      uint8         `json:"rating"`
+	Actors      []ImportActor `json:"actors"`
+}
+
+// ImportRow is a movie read from a file, or an actor when Actor isn't nil.
+// Err tells why it couldn't be read or isn't valid. Row is the line of a CSV
+// or NDJSON file where the movie starts, or the position of an element of
+// a JSON array, from 1.
+type ImportRow struct {
+	Row   int
+	Movie ImportMovie
+	Actor *ImportActor
+	Err   error
+}
+
+type ImportMovies struct {
+	Rows      []ImportRow
+	BatchSize int
+	DryRun    bool
+}
+
+const (
+	ImportCreated = "created"
+	ImportUpdated = "updated"
+	ImportSkipped = "skipped"
+	ImportFailed  = "failed"
+)
+
+type ImportResult struct {
+	Row        int    `json:"row"`
+	ExternalID string `json:"external_id,omitempty"`
+	Title      string `json:"movie_title,omitempty"`
+	Name       string `json:"actor_name,omitempty"`
+	Status     string `json:"status"`
+	Error      string `json:"error,omitempty"`
+}
+
+type ImportReport struct {
+	DryRun  bool           `json:"dry_run"`
+	Created int            `json:"created"`
+	Updated int            `json:"updated"`
+	Skipped int            `json:"skipped"`
+	Failed  int            `json:"failed"`
+	Rows    []ImportResult `json:"rows"`
+}
+
+// Add records the result of a row.
+func (r *ImportReport) Add(result ImportResult) {
+	switch result.Status {
+	case ImportCreated:
+		r.Created++
+	case ImportUpdated:
+		r.Updated++
+	case ImportSkipped:
+		r.Skipped++
+	case ImportFailed:
+		r.Failed++
+	}
+
+	r.Rows = append(r.Rows, result)
+}
@@ -1,6 +1,10 @@
 package domain
 
-import "time"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
 type Movie struct {
 	ID          int64     `json:"movie_id"`
@@ -8,14 +12,15 @@ type Movie struct {
 	Description string    `json:"description"`
 	ReleaseDate time.Time `json:"release_date"`
 	Rating      uint8     `json:"rating"`
-	Actors      []int64   `json:"actors"`
+	Actors      []int64   `json:"actors,omitempty"`
+	// Version is sent as the ETag. On writes it holds the version from If-Match,
+	// where 0 (If-Match: *) matches any version.
+	Version int64 `json:"-"`
 }
 
-type MovieWithoudID struct {
-	Title       string
-	Description string
-	ReleaseDate time.Time
-	Rating      uint8
+type MovieWithActors struct {
+	Movie
+	Actors []Actor `json:"actors"`
 }
 
 type CreateMovie struct {
@@ -26,16 +31,170 @@ type CreateMovie struct {
 	Actors      []int64   `json:"actors"`
 }
 
+// PatchMovie is a JSON Merge Patch of a movie: nil fields are left untouched,
+// a non-nil Actors replaces the whole cast.
+type PatchMovie struct {
+	ID          int64      `json:"-"`
+	Title       *string    `json:"movie_title"`
+	Description *string    `json:"description"`
+	ReleaseDate *time.Time `json:"release_date"`
+	Rating      *uint8     `json:"rating"`
+	Actors      []int64    `json:"actors"`
+	Version     int64      `json:"-"`
+
+	// The fields a patch sets to null, which are cleared.
+	NullDescription bool `json:"-"`
+	NullReleaseDate bool `json:"-"`
+	NullRating      bool `json:"-"`
+}
+
+type MovieActor struct {
+	MovieID int64 `json:"-"`
+	ActorID int64 `json:"actor_id"`
+	Version int64 `json:"-"`
+}
+
 type DeleteMovie struct {
-	ID int64 `json:"movie_id"`
+	ID      int64 `json:"movie_id"`
+	Version int64 `json:"-"`
+}
+
+type GetMovieByID struct {
+	ID int64
+}
+
+type GetMovieActors struct {
+	ID int64
 }
 
+// DefaultSimilarity is the similarity threshold of a fuzzy search when
+// neither the request nor the configuration sets one.
+const DefaultSimilarity = 0.4
+
 type GetMovie struct {
-	Title      string
-	ActorName  string
-	SearchType string
+	Title     string
+	ActorName string
+	// Similarity is the least trigram word similarity, between 0 and 1, of
+	// the title and actor name a fuzzy search matches.
+	Similarity float64
+	Limit      int
+	Offset     int
+}
+
+type MoviePage struct {
+	Movies []Movie `json:"movies"`
+	Total  int64   `json:"total"`
+	Limit  int     `json:"limit"`
+	Offset int     `json:"offset"`
+	// Fuzzy is set when nothing contains the terms searched for and the
+	// movies are the ones matching them closely enough, most similar first.
+	Fuzzy      bool        `json:"fuzzy,omitempty"`
+	DidYouMean *DidYouMean `json:"did_you_mean,omitempty"`
+}
+
+// DidYouMean holds the known title and actor name closest to the misspelt
+// terms of a search that found nothing as typed.
+type DidYouMean struct {
+	Title string `json:"title,omitempty"`
+	Actor string `json:"actor,omitempty"`
 }
 
+// MovieSortFields lists the fields a movie listing can be sorted by.
+var MovieSortFields = []string{"title", "rating", "release_date"}
+
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// ParseSort parses a sort spec such as "-rating,title", where a leading "-"
+// means descending order. Only the fields listed in allowed are accepted.
+func ParseSort(spec string, allowed []string) ([]SortField, error) {
+	sort := make([]SortField, 0, len(allowed))
+	seen := make(map[string]bool, len(allowed))
+
+	for _, item := range strings.Split(spec, ",") {
+		field := SortField{
+			Field: strings.TrimSpace(item),
+		}
+
+		if strings.HasPrefix(field.Field, "-") {
+			field.Field, field.Desc = field.Field[1:], true
+		} else {
+			field.Field = strings.TrimPrefix(field.Field, "+")
+		}
+
+		known := false
+		for _, name := range allowed {
+			known = known || name == field.Field
+		}
+
+		if !known || seen[field.Field] {
+			return nil, NewFieldError(ErrRequest, "sort",
+				fmt.Sprintf("invalid sort field %q, allowed: %s", field.Field, strings.Join(allowed, ", ")))
+		}
+
+		seen[field.Field] = true
+		sort = append(sort, field)
+	}
+
+	return sort, nil
+}
+
+// MovieFilter narrows a movie listing down, a movie has to match every
+// filter that is set. Zero values are unset.
+type MovieFilter struct {
+	FromYear  int
+	ToYear    int
+	MinRating uint8
+	MaxRating uint8
+	ActorIDs  []int64
+	// AllActors requires every one of ActorIDs in the cast rather than any.
+	AllActors bool
+	// WithGenders requires an actor of each of the genders in the cast,
+	// WithoutGenders rules out the casts with an actor of any of them.
+	WithGenders    []string
+	WithoutGenders []string
+	// Query is a full-text query as taken by SearchMovies.
+	Query    string
+	Language string
+}
+
+// DefaultYearBucket is the width in years of the release year facet.
+const DefaultYearBucket = 10
+
 type GetOrderedMovie struct {
-	Order string
+	Sort   []SortField
+	Page   Page
+	Filter MovieFilter
+	// Facets asks for the facet counts, YearBucket is their width in years.
+	Facets     bool
+	YearBucket int
+}
+
+type MovieList struct {
+	Movies []Movie `json:"movies"`
+	Cursors
+	Facets *MovieFacets `json:"facets,omitempty"`
+}
+
+// MovieFacets count the movies of a filtered listing by release year and
+// rating. Each facet leaves out its own filter, so that the counts of the
+// other years or ratings one could switch to are there too. Movies without
+// a release date aren't counted by year, the ones without a rating count as 0.
+type MovieFacets struct {
+	Years   []YearBucket  `json:"years"`
+	Ratings []RatingCount `json:"ratings"`
+}
+
+// YearBucket counts the movies released from From to To, both included.
+type YearBucket struct {
+	From  int   `json:"from"`
+	To    int   `json:"to"`
+	Count int64 `json:"count"`
+}
+
+type RatingCount struct {
+	Rating uint8 `json:"rating"`
+	Count  int64 `json:"count"`
 }
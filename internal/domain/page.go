@@ -0,0 +1,12 @@
+package domain
+
+type Page struct {
+	Limit  int
+	After  string
+	Before string
+}
+
+type Cursors struct {
+	Next string `json:"next_cursor,omitempty"`
+	Prev string `json:"prev_cursor,omitempty"`
+}
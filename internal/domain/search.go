@@ -0,0 +1,34 @@
+package domain
+
+// SearchLanguages are the text search configurations a search can use, the
+// first one is the default.
+var SearchLanguages = []string{"english", "russian", "simple"}
+
+// MaxSearchQueryLength bounds a search query, longer ones are no longer
+// about finding a movie.
+const MaxSearchQueryLength = 256
+
+// SearchMovies is a full-text search written the way search engines take it:
+// words, "quoted phrases", or and -excluded words.
+type SearchMovies struct {
+	Query    string
+	Language string
+	Limit    int
+	Offset   int
+}
+
+// MovieHit is a movie found by a search. The title and snippet mark the
+// matched words with <mark></mark> and are otherwise escaped HTML.
+type MovieHit struct {
+	Movie
+	Rank           float32 `json:"rank"`
+	TitleHighlight string  `json:"title_highlight"`
+	Snippet        string  `json:"snippet"`
+}
+
+type SearchPage struct {
+	Movies []MovieHit `json:"movies"`
+	Total  int64      `json:"total"`
+	Limit  int        `json:"limit"`
+	Offset int        `json:"offset"`
+}
@@ -1,10 +1,58 @@
 package domain
 
+import "time"
+
 type CreateSession struct {
-	Username string
-	IsAdmin  bool
+	Username  string
+	IsAdmin   bool
+	IP        string
+	UserAgent string
 }
 
+// GetSession looks a session up by the token from the session-id cookie.
 type GetSession struct {
+	Token string
+}
+
+// ActiveSession is a session in use. Token is the one to send back to the
+// client, which differs from the one it came with once the session was
+// rotated, and empty for a request that came with the token of a session
+// rotated meanwhile, whose client is to keep the cookie it was given. The
+// session expires at ExpiresAt unless it is used again.
+type ActiveSession struct {
+	User      *User
+	Token     string
+	ExpiresAt time.Time
+}
+
+// Session is an active login. ID identifies it in the API without giving
+// away the token it was created with.
+type Session struct {
+	ID         string    `json:"id"`
+	Username   string    `json:"username"`
+	IsAdmin    bool      `json:"is_admin"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	IP         string    `json:"ip"`
+	UserAgent  string    `json:"user_agent"`
+	Current    bool      `json:"current"`
+}
+
+type SessionList struct {
+	Sessions []Session `json:"sessions"`
+}
+
+// ListSessions lists the sessions of Username, marking the one of Token as
+// current.
+type ListSessions struct {
+	Username string
+	Token    string
+}
+
+// DeleteSession names a session by its Token or by its ID. When Username is
+// set, the session must belong to that user.
+type DeleteSession struct {
+	Token    string
+	ID       string
 	Username string
 }
@@ -0,0 +1,38 @@
+package domain
+
+// SuggestTypes are the kinds of suggestions a typeahead can ask for.
+var SuggestTypes = []string{"movie", "actor"}
+
+// MaxSuggestLimit bounds the suggestions of a single keystroke.
+const MaxSuggestLimit = 20
+
+const (
+	// MinSuggestLength is the shortest prefix worth suggesting for, a single
+	// character matches too much of the catalogue to be of use.
+	MinSuggestLength = 2
+	// MinWordSuggestLength is the shortest prefix also looked for at the start
+	// of later words. The trigram index needs three characters, so shorter
+	// prefixes only complete the start of a title or a name.
+	MinWordSuggestLength = 3
+)
+
+// Suggest asks for the titles and names starting with a prefix. A word of
+// them starting with it counts too, so "cap" finds "Captain Marvel" as well
+// as "The Captain".
+type Suggest struct {
+	Prefix string
+	Types  []string
+	Limit  int
+}
+
+// Suggestion is a title or a name completing a prefix, Type tells whether
+// ID is a movie or an actor.
+type Suggestion struct {
+	Type string `json:"type"`
+	ID   int64  `json:"id"`
+	Text string `json:"text"`
+}
+
+type SuggestList struct {
+	Suggestions []Suggestion `json:"suggestions"`
+}
@@ -0,0 +1,34 @@
+package domain
+
+// The grant types of POST /token.
+const (
+	GrantPassword     = "password"
+	GrantRefreshToken = "refresh_token"
+)
+
+// TokenRequest asks for an access token either with the credentials of a
+// user or with a refresh token issued before.
+type TokenRequest struct {
+	GrantType    string `json:"grant_type"`
+	Username     string `json:"username,omitempty"`
+	Password     string `json:"password,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+}
+
+// TokenPair is an access token to send as Authorization: Bearer and a
+// refresh token for a new pair once it expires. A refresh token is good for
+// one use only.
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+type GetAccessToken struct {
+	Token string
+}
+
+type UseRefreshToken struct {
+	Token string
+}
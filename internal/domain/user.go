@@ -11,3 +11,21 @@ type CRUser struct {
 }
 
 type UserContext string
+
+type SetAdmin struct {
+	Username string
+	IsAdmin  bool
+}
+
+type GetUser struct {
+	Username string
+}
+
+type DeleteUser struct {
+	Username string
+}
+
+// RevokeUser ends every session and refresh token of a user.
+type RevokeUser struct {
+	Username string
+}
@@ -0,0 +1,242 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// The limits mirror the constraints of the schema migrations.
+const (
+	MaxMovieTitleLength  = 150
+	MaxDescriptionLength = 1000
+	MinRating            = 1
+	MaxRating            = 10
+	MaxActorNameLength   = 100
+	MaxUsernameLength    = 256
+	MaxExternalIDLength  = 64
+	// MaxPasswordLength is in bytes, bcrypt refuses to hash longer passwords.
+	MaxPasswordLength = 72
+)
+
+// Genders lists the values of the gender enum.
+var Genders = []string{"Male", "Female"}
+
+// validator collects the violations of a DTO, so that all of them are
+// reported at once.
+type validator struct {
+	fields []FieldError
+}
+
+func (v *validator) check(ok bool, field, message string) {
+	if !ok {
+		v.fields = append(v.fields, FieldError{Field: field, Message: message})
+	}
+}
+
+func (v *validator) text(field, value string, max int) {
+	if strings.TrimSpace(value) == "" {
+		v.check(false, field, "is required")
+		return
+	}
+
+	v.optionalText(field, value, max)
+}
+
+func (v *validator) optionalText(field, value string, max int) {
+	v.check(utf8.RuneCountInString(value) <= max, field, fmt.Sprintf("must be at most %d characters", max))
+}
+
+func (v *validator) rating(field string, rating uint8) {
+	v.check(rating >= MinRating && rating <= MaxRating, field, fmt.Sprintf("must be between %d and %d", MinRating, MaxRating))
+}
+
+func (v *validator) ids(field string, ids []int64) {
+	seen := make(map[int64]bool, len(ids))
+	for i, id := range ids {
+		name := fmt.Sprintf("%s[%d]", field, i)
+		v.check(id > 0, name, "must be a positive integer")
+		v.check(!seen[id], name, "is a duplicate")
+		seen[id] = true
+	}
+}
+
+func (v *validator) gender(field, gender string) {
+	for _, g := range Genders {
+		if gender == g {
+			return
+		}
+	}
+
+	v.check(false, field, "must be one of "+strings.Join(Genders, ", "))
+}
+
+func (v *validator) scopes(field string, scopes []string) {
+	known := make(map[string]bool, len(APIKeyScopes))
+	for _, scope := range APIKeyScopes {
+		known[scope] = true
+	}
+
+	seen := make(map[string]bool, len(scopes))
+	for i, scope := range scopes {
+		name := fmt.Sprintf("%s[%d]", field, i)
+		v.check(known[scope], name, "must be one of "+strings.Join(APIKeyScopes, ", "))
+		v.check(!seen[scope], name, "is a duplicate")
+		seen[scope] = true
+	}
+}
+
+func (v *validator) pastDate(field string, date time.Time) {
+	v.check(!date.After(time.Now()), field, "can't be in the future")
+}
+
+func (v *validator) err() error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+
+	return &InvalidFieldsError{Kind: ErrValidation, Fields: v.fields}
+}
+
+func (m *CreateMovie) Validate() error {
+	v := validator{}
+	v.text("movie_title", m.Title, MaxMovieTitleLength)
+	v.optionalText("description", m.Description, MaxDescriptionLength)
+	v.rating("rating", m.Rating)
+	v.ids("actors", m.Actors)
+
+	return v.err()
+}
+
+func (m *Movie) Validate() error {
+	v := validator{}
+	v.text("movie_title", m.Title, MaxMovieTitleLength)
+	v.optionalText("description", m.Description, MaxDescriptionLength)
+	v.rating("rating", m.Rating)
+	v.ids("actors", m.Actors)
+
+	return v.err()
+}
+
+// Validate checks only the fields present in the patch.
+func (m *PatchMovie) Validate() error {
+	v := validator{}
+	if m.Title != nil {
+		v.text("movie_title", *m.Title, MaxMovieTitleLength)
+	}
+	if m.Description != nil {
+		v.optionalText("description", *m.Description, MaxDescriptionLength)
+	}
+	if m.Rating != nil {
+		v.rating("rating", *m.Rating)
+	}
+	v.ids("actors", m.Actors)
+
+	return v.err()
+}
+
+func (a *CreateActor) Validate() error {
+	v := validator{}
+	v.text("actor_name", a.Name, MaxActorNameLength)
+	v.gender("gender", a.Gender)
+	v.pastDate("date_of_birth", a.DateBirth)
+
+	return v.err()
+}
+
+func (a *Actor) Validate() error {
+	v := validator{}
+	v.text("actor_name", a.Name, MaxActorNameLength)
+	v.gender("gender", a.Gender)
+	v.pastDate("date_of_birth", a.DateBirth)
+
+	return v.err()
+}
+
+// Validate checks only the fields present in the patch.
+func (a *PatchActor) Validate() error {
+	v := validator{}
+	if a.Name != nil {
+		v.text("actor_name", *a.Name, MaxActorNameLength)
+	}
+	if a.Gender != nil {
+		v.gender("gender", *a.Gender)
+	}
+	if a.DateBirth != nil {
+		v.pastDate("date_of_birth", *a.DateBirth)
+	}
+
+	return v.err()
+}
+
+// Validate checks an imported movie. Cast members referred to by external ID
+// may leave out the rest of their fields.
+func (m *ImportMovie) Validate() error {
+	v := validator{}
+	v.optionalText("external_id", m.ExternalID, MaxExternalIDLength)
+	v.text("movie_title", m.Title, MaxMovieTitleLength)
+	v.optionalText("description", m.Description, MaxDescriptionLength)
+	if m.Rating != 0 {
+		v.rating("rating", m.Rating)
+	}
+
+	for i := range m.Actors {
+		v.importActor(fmt.Sprintf("actors[%d].", i), &m.Actors[i])
+	}
+
+	return v.err()
+}
+
+func (a *ImportActor) Validate() error {
+	v := validator{}
+	v.importActor("", a)
+
+	return v.err()
+}
+
+// importActor checks an imported actor, whose name may be left out when it
+// is found by external ID.
+func (v *validator) importActor(prefix string, a *ImportActor) {
+	v.optionalText(prefix+"external_id", a.ExternalID, MaxExternalIDLength)
+	if a.ExternalID == "" || a.Name != "" {
+		v.text(prefix+"actor_name", a.Name, MaxActorNameLength)
+	}
+	if a.Gender != "" {
+		v.gender(prefix+"gender", a.Gender)
+	}
+	v.pastDate(prefix+"date_of_birth", a.DateBirth)
+}
+
+func (u *CRUser) Validate() error {
+	v := validator{}
+	v.text("username", u.Username, MaxUsernameLength)
+	v.check(u.Password != "", "password", "is required")
+	v.check(len(u.Password) <= MaxPasswordLength, "password", fmt.Sprintf("must be at most %d bytes", MaxPasswordLength))
+
+	return v.err()
+}
+
+func (t *TokenRequest) Validate() error {
+	v := validator{}
+	switch t.GrantType {
+	case GrantPassword:
+		v.text("username", t.Username, MaxUsernameLength)
+		v.check(t.Password != "", "password", "is required")
+	case GrantRefreshToken:
+		v.check(t.RefreshToken != "", "refresh_token", "is required")
+	default:
+		v.check(false, "grant_type", "must be one of "+GrantPassword+", "+GrantRefreshToken)
+	}
+
+	return v.err()
+}
+
+func (k *CreateAPIKey) Validate() error {
+	v := validator{}
+	v.text("name", k.Name, MaxAPIKeyNameLength)
+	v.check(len(k.Scopes) > 0, "scopes", "is required")
+	v.scopes("scopes", k.Scopes)
+
+	return v.err()
+}
@@ -0,0 +1,151 @@
+package domain
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type validatable interface {
+	Validate() error
+}
+
+// invalidFields returns the names of the fields err reports, nil for no error.
+func invalidFields(t *testing.T, err error) []string {
+	if err == nil {
+		return nil
+	}
+
+	invalid := &InvalidFieldsError{}
+	if !errors.As(err, &invalid) {
+		t.Fatalf("expected InvalidFieldsError, got: %v", err)
+	}
+
+	if !errors.Is(err, ErrValidation) {
+		t.Errorf("expected ErrValidation, got: %v", err)
+	}
+
+	fields := make([]string, 0, len(invalid.Fields))
+	for _, field := range invalid.Fields {
+		fields = append(fields, field.Field)
+	}
+
+	return fields
+}
+
+// validateTest is a DTO and the fields its Validate reports.
+type validateTest struct {
+	name   string
+	dto    validatable
+	fields []string
+}
+
+func runValidate(t *testing.T, tests []validateTest) {
+	t.Helper()
+
+	for _, test := range tests {
+		if fields := invalidFields(t, test.dto.Validate()); !reflect.DeepEqual(fields, test.fields) {
+			t.Errorf("%s: expected fields %v, got: %v", test.name, test.fields, fields)
+		}
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+var (
+	past   = time.Date(1974, 11, 11, 0, 0, 0, 0, time.UTC)
+	future = time.Now().AddDate(1, 0, 0)
+)
+
+func TestValidateMovie(t *testing.T) {
+	runValidate(t, []validateTest{
+		{"OK", &CreateMovie{Title: "Heat", Description: "Heist", Rating: 8, Actors: []int64{1, 2}}, nil},
+		{"OK. Longest title and description", &CreateMovie{
+			Title:       strings.Repeat("я", MaxMovieTitleLength),
+			Description: strings.Repeat("я", MaxDescriptionLength),
+			Rating:      MaxRating,
+		}, nil},
+		{"Empty title", &CreateMovie{Title: "  ", Rating: 8}, []string{"movie_title"}},
+		{"Title too long", &CreateMovie{Title: strings.Repeat("a", MaxMovieTitleLength+1), Rating: 8}, []string{"movie_title"}},
+		{"Description too long", &CreateMovie{Title: "Heat", Description: strings.Repeat("a", MaxDescriptionLength+1), Rating: 8}, []string{"description"}},
+		{"Rating below range", &CreateMovie{Title: "Heat", Rating: MinRating - 1}, []string{"rating"}},
+		{"Rating above range", &CreateMovie{Title: "Heat", Rating: MaxRating + 1}, []string{"rating"}},
+		{"Duplicate actor", &CreateMovie{Title: "Heat", Rating: 8, Actors: []int64{1, 2, 1}}, []string{"actors[2]"}},
+		{"Non-positive actor", &CreateMovie{Title: "Heat", Rating: 8, Actors: []int64{0}}, []string{"actors[0]"}},
+		{"All at once", &Movie{Title: "", Description: strings.Repeat("a", MaxDescriptionLength+1), Rating: 11, Actors: []int64{3, 3}},
+			[]string{"movie_title", "description", "rating", "actors[1]"}},
+		{"OK. Update", &Movie{ID: 1, Title: "Heat", Rating: 8}, nil},
+	})
+}
+
+func TestValidatePatchMovie(t *testing.T) {
+	runValidate(t, []validateTest{
+		{"OK. Empty patch", &PatchMovie{ID: 1}, nil},
+		{"OK. Nulls", &PatchMovie{ID: 1, NullDescription: true, NullRating: true}, nil},
+		{"OK. Rating", &PatchMovie{ID: 1, Rating: ptr(uint8(MinRating))}, nil},
+		{"Empty title", &PatchMovie{ID: 1, Title: ptr("")}, []string{"movie_title"}},
+		{"Title too long", &PatchMovie{ID: 1, Title: ptr(strings.Repeat("a", MaxMovieTitleLength+1))}, []string{"movie_title"}},
+		{"Description too long", &PatchMovie{ID: 1, Description: ptr(strings.Repeat("a", MaxDescriptionLength+1))}, []string{"description"}},
+		{"Rating out of range", &PatchMovie{ID: 1, Rating: ptr(uint8(0))}, []string{"rating"}},
+		{"Duplicate actor", &PatchMovie{ID: 1, Actors: []int64{5, 5}}, []string{"actors[1]"}},
+	})
+}
+
+func TestValidateActor(t *testing.T) {
+	runValidate(t, []validateTest{
+		{"OK", &CreateActor{Name: "Leonardo DiCaprio", Gender: "Male", DateBirth: past}, nil},
+		{"OK. Longest name", &CreateActor{Name: strings.Repeat("я", MaxActorNameLength), Gender: "Female", DateBirth: past}, nil},
+		{"Empty name", &CreateActor{Name: "", Gender: "Male", DateBirth: past}, []string{"actor_name"}},
+		{"Name too long", &CreateActor{Name: strings.Repeat("a", MaxActorNameLength+1), Gender: "Male", DateBirth: past}, []string{"actor_name"}},
+		{"Unknown gender", &CreateActor{Name: "Name", Gender: "male", DateBirth: past}, []string{"gender"}},
+		{"Missing gender", &CreateActor{Name: "Name", DateBirth: past}, []string{"gender"}},
+		{"Born in the future", &CreateActor{Name: "Name", Gender: "Male", DateBirth: future}, []string{"date_of_birth"}},
+		{"All at once", &Actor{ID: 1, Gender: "Other", DateBirth: future}, []string{"actor_name", "gender", "date_of_birth"}},
+		{"OK. Patch", &PatchActor{ID: 1, Gender: ptr("Female"), DateBirth: ptr(past)}, nil},
+		{"Patch. Unknown gender", &PatchActor{ID: 1, Gender: ptr("")}, []string{"gender"}},
+		{"Patch. Born in the future", &PatchActor{ID: 1, DateBirth: ptr(future)}, []string{"date_of_birth"}},
+		{"Patch. Name too long", &PatchActor{ID: 1, Name: ptr(strings.Repeat("a", MaxActorNameLength+1))}, []string{"actor_name"}},
+	})
+}
+
+func TestValidateImport(t *testing.T) {
+	runValidate(t, []validateTest{
+		{"OK. Without a rating", &ImportMovie{Title: "Heat"}, nil},
+		{"OK. Cast member by external ID", &ImportMovie{Title: "Heat", Rating: 8, Actors: []ImportActor{{ExternalID: "nm0000199"}}}, nil},
+		{"Rating out of range", &ImportMovie{Title: "Heat", Rating: 11}, []string{"rating"}},
+		{"External ID too long", &ImportMovie{ExternalID: strings.Repeat("a", MaxExternalIDLength+1), Title: "Heat"}, []string{"external_id"}},
+		{"Cast member without a name", &ImportMovie{Title: "Heat", Actors: []ImportActor{{Gender: "Male"}}}, []string{"actors[0].actor_name"}},
+		{"Cast member born in the future", &ImportMovie{Title: "Heat", Actors: []ImportActor{{Name: "Name", DateBirth: future}}}, []string{"actors[0].date_of_birth"}},
+		{"OK. Actor without a gender", &ImportActor{Name: "Name"}, nil},
+		{"Actor with an unknown gender", &ImportActor{Name: "Name", Gender: "Other"}, []string{"gender"}},
+	})
+}
+
+func TestValidateUser(t *testing.T) {
+	runValidate(t, []validateTest{
+		{"OK", &CRUser{Username: "user", Password: "secret"}, nil},
+		{"Empty username", &CRUser{Username: " ", Password: "secret"}, []string{"username"}},
+		{"Username too long", &CRUser{Username: strings.Repeat("a", MaxUsernameLength+1), Password: "secret"}, []string{"username"}},
+		{"Empty password", &CRUser{Username: "user"}, []string{"password"}},
+		{"OK. Longest password", &CRUser{Username: "user", Password: strings.Repeat("a", MaxPasswordLength)}, nil},
+		{"Password too long", &CRUser{Username: "user", Password: strings.Repeat("я", MaxPasswordLength/2+1)}, []string{"password"}},
+		{"OK. Password grant", &TokenRequest{GrantType: GrantPassword, Username: "user", Password: "secret"}, nil},
+		{"Password grant without a password", &TokenRequest{GrantType: GrantPassword, Username: "user"}, []string{"password"}},
+		{"Refresh grant without a token", &TokenRequest{GrantType: GrantRefreshToken}, []string{"refresh_token"}},
+		{"Unknown grant", &TokenRequest{GrantType: "client_credentials"}, []string{"grant_type"}},
+	})
+}
+
+func TestValidateAPIKey(t *testing.T) {
+	runValidate(t, []validateTest{
+		{"OK", &CreateAPIKey{Name: "ci", Scopes: []string{APIKeyScopes[0]}}, nil},
+		{"No scopes", &CreateAPIKey{Name: "ci"}, []string{"scopes"}},
+		{"Unknown and duplicate scopes", &CreateAPIKey{Name: "ci", Scopes: []string{"root", APIKeyScopes[0], APIKeyScopes[0]}},
+			[]string{"scopes[0]", "scopes[2]"}},
+		{"Name too long", &CreateAPIKey{Name: strings.Repeat("a", MaxAPIKeyNameLength+1), Scopes: []string{APIKeyScopes[0]}}, []string{"name"}},
+	})
+}
@@ -13,17 +13,41 @@ func NewActorService(storage ActorStorage) *actorService {
 }
 
 func (s *actorService) Create(dto *domain.CreateActor) error {
+	if err := dto.Validate(); err != nil {
+		return err
+	}
+
 	return s.storage.Create(dto)
 }
 
 func (s *actorService) Update(dto *domain.Actor) error {
+	if err := dto.Validate(); err != nil {
+		return err
+	}
+
 	return s.storage.Update(dto)
 }
 
+func (s *actorService) Patch(dto *domain.PatchActor) error {
+	if err := dto.Validate(); err != nil {
+		return err
+	}
+
+	return s.storage.Patch(dto)
+}
+
 func (s *actorService) Delete(dto *domain.DeleteActor) error {
 	return s.storage.Delete(dto)
 }
 
-func (s *actorService) GetList() ([]domain.ActorWithMovie, error) {
-	return s.storage.GetList()
+func (s *actorService) GetList(dto *domain.GetActorList) (*domain.ActorList, error) {
+	return s.storage.GetList(dto)
+}
+
+func (s *actorService) Get(dto *domain.GetActor) (*domain.Actor, error) {
+	return s.storage.Get(dto)
+}
+
+func (s *actorService) GetMovies(dto *domain.GetActorMovies) ([]domain.Movie, error) {
+	return s.storage.GetMovies(dto)
 }
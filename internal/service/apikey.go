@@ -0,0 +1,33 @@
+package service
+
+import "github.com/akrovv/filmlibrary/internal/domain"
+
+type apiKeyService struct {
+	storage APIKeyStorage
+}
+
+func NewAPIKeyService(storage APIKeyStorage) *apiKeyService {
+	return &apiKeyService{
+		storage: storage,
+	}
+}
+
+func (s *apiKeyService) Create(dto *domain.CreateAPIKey) (*domain.CreatedAPIKey, error) {
+	if err := dto.Validate(); err != nil {
+		return nil, err
+	}
+
+	return s.storage.Create(dto)
+}
+
+func (s *apiKeyService) List(dto *domain.ListAPIKeys) (*domain.APIKeyList, error) {
+	return s.storage.List(dto)
+}
+
+func (s *apiKeyService) Delete(dto *domain.DeleteAPIKey) error {
+	return s.storage.Delete(dto)
+}
+
+func (s *apiKeyService) Authenticate(dto *domain.GetAPIKey) (*domain.ScopedUser, error) {
+	return s.storage.Authenticate(dto)
+}
@@ -0,0 +1,17 @@
+package service
+
+import "github.com/akrovv/filmlibrary/internal/domain"
+
+type exportService struct {
+	storage ExportStorage
+}
+
+func NewExportService(storage ExportStorage) *exportService {
+	return &exportService{
+		storage: storage,
+	}
+}
+
+func (s *exportService) Export(w domain.ExportWriter) error {
+	return s.storage.Export(w)
+}
@@ -0,0 +1,17 @@
+package service
+
+import "github.com/akrovv/filmlibrary/internal/domain"
+
+type imdbService struct {
+	storage IMDbStorage
+}
+
+func NewIMDbService(storage IMDbStorage) *imdbService {
+	return &imdbService{
+		storage: storage,
+	}
+}
+
+func (s *imdbService) ImportIMDb(source domain.IMDbSource) (*domain.IMDbReport, error) {
+	return s.storage.ImportIMDb(source)
+}
@@ -0,0 +1,30 @@
+package service
+
+import "github.com/akrovv/filmlibrary/internal/domain"
+
+type importService struct {
+	storage ImportStorage
+}
+
+func NewImportService(storage ImportStorage) *importService {
+	return &importService{
+		storage: storage,
+	}
+}
+
+// ImportMovies validates the rows first, the invalid ones are reported as
+// failed without reaching the storage.
+func (s *importService) ImportMovies(dto *domain.ImportMovies) (*domain.ImportReport, error) {
+	for i := range dto.Rows {
+		row := &dto.Rows[i]
+		switch {
+		case row.Err != nil:
+		case row.Actor != nil:
+			row.Err = row.Actor.Validate()
+		default:
+			row.Err = row.Movie.Validate()
+		}
+	}
+
+	return s.storage.ImportMovies(dto)
+}
@@ -1,28 +1,104 @@
 package service
 
-import "github.com/akrovv/filmlibrary/internal/domain"
+import (
+	"time"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+	"github.com/akrovv/filmlibrary/pkg/token"
+)
 
 type UserStorage interface {
 	Register(user *domain.CRUser) error
 	Login(user *domain.CRUser) (*domain.User, error)
+	Get(dto *domain.GetUser) (*domain.User, error)
+	SetPassword(user *domain.CRUser) error
+	SetAdmin(dto *domain.SetAdmin) error
+	Delete(dto *domain.DeleteUser) error
+	List() ([]domain.User, error)
+}
+
+// Revoker keeps logins of users, such as refresh tokens, which end once the
+// user is deleted or their password changes.
+type Revoker interface {
+	Revoke(dto *domain.RevokeUser) error
+}
+
+// PrivilegeHolder keeps the privileges of users outside of the Users table,
+// as sessions do, which have to follow their changes as well.
+type PrivilegeHolder interface {
+	Revoker
+	SetAdmin(dto *domain.SetAdmin) error
 }
 
 type SessionStorage interface {
-	Create(dto *domain.CreateSession) (string, error)
-	Get(dto *domain.GetSession) (*domain.User, error)
+	Create(dto *domain.CreateSession) (*domain.ActiveSession, error)
+	Get(dto *domain.GetSession) (*domain.ActiveSession, error)
+	List(dto *domain.ListSessions) (*domain.SessionList, error)
+	Delete(dto *domain.DeleteSession) error
+	SetAdmin(dto *domain.SetAdmin) error
+	Revoke(dto *domain.RevokeUser) error
 }
 
 type ActorStorage interface {
 	Create(dto *domain.CreateActor) error
 	Update(dto *domain.Actor) error
+	Patch(dto *domain.PatchActor) error
 	Delete(dto *domain.DeleteActor) error
-	GetList() ([]domain.ActorWithMovie, error)
+	GetList(dto *domain.GetActorList) (*domain.ActorList, error)
+	Get(dto *domain.GetActor) (*domain.Actor, error)
+	GetMovies(dto *domain.GetActorMovies) ([]domain.Movie, error)
 }
 
 type MovieStorage interface {
 	Create(dto *domain.CreateMovie) error
 	Update(dto *domain.Movie) error
+	Patch(dto *domain.PatchMovie) error
+	AddActor(dto *domain.MovieActor) error
+	RemoveActor(dto *domain.MovieActor) error
 	Delete(dto *domain.DeleteMovie) error
-	Get(dto *domain.GetMovie) (*domain.MovieWithoudID, error)
-	GetOrderedList(dto *domain.GetOrderedMovie) ([]domain.MovieWithoudID, error)
+	Get(dto *domain.GetMovie) (*domain.MoviePage, error)
+	Search(dto *domain.SearchMovies) (*domain.SearchPage, error)
+	GetOrderedList(dto *domain.GetOrderedMovie) (*domain.MovieList, error)
+	GetByID(dto *domain.GetMovieByID) (*domain.MovieWithActors, error)
+	GetActors(dto *domain.GetMovieActors) ([]domain.Actor, error)
+}
+
+type ImportStorage interface {
+	ImportMovies(dto *domain.ImportMovies) (*domain.ImportReport, error)
+}
+
+type ExportStorage interface {
+	Export(w domain.ExportWriter) error
+}
+
+type IMDbStorage interface {
+	ImportIMDb(source domain.IMDbSource) (*domain.IMDbReport, error)
+}
+
+type SuggestStorage interface {
+	Suggest(dto *domain.Suggest) (*domain.SuggestList, error)
+}
+
+type SuggestCache interface {
+	Get(dto *domain.Suggest) (*domain.SuggestList, error)
+	Set(dto *domain.Suggest, list *domain.SuggestList) error
+}
+
+type RefreshTokenStorage interface {
+	Create(user *domain.User) (string, error)
+	Use(dto *domain.UseRefreshToken) (*domain.User, error)
+	Revoke(dto *domain.RevokeUser) error
+}
+
+// TokenSigner issues and verifies the signed access tokens.
+type TokenSigner interface {
+	Issue(subject string, admin bool, ttl time.Duration) (string, time.Time, error)
+	Verify(signed string) (*token.Claims, error)
+}
+
+type APIKeyStorage interface {
+	Create(dto *domain.CreateAPIKey) (*domain.CreatedAPIKey, error)
+	List(dto *domain.ListAPIKeys) (*domain.APIKeyList, error)
+	Delete(dto *domain.DeleteAPIKey) error
+	Authenticate(dto *domain.GetAPIKey) (*domain.ScopedUser, error)
 }
@@ -62,19 +62,63 @@ func (mr *MockActorServiceMockRecorder) Delete(dto interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockActorService)(nil).Delete), dto)
 }
 
+// Get mocks base method.
+func (m *MockActorService) Get(dto *domain.GetActor) (*domain.Actor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", dto)
+	ret0, _ := ret[0].(*domain.Actor)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockActorServiceMockRecorder) Get(dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockActorService)(nil).Get), dto)
+}
+
 // GetList mocks base method.
-func (m *MockActorService) GetList() ([]domain.ActorWithMovie, error) {
+func (m *MockActorService) GetList(dto *domain.GetActorList) (*domain.ActorList, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetList")
-	ret0, _ := ret[0].([]domain.ActorWithMovie)
+	ret := m.ctrl.Call(m, "GetList", dto)
+	ret0, _ := ret[0].(*domain.ActorList)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetList indicates an expected call of GetList.
-func (mr *MockActorServiceMockRecorder) GetList() *gomock.Call {
+func (mr *MockActorServiceMockRecorder) GetList(dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetList", reflect.TypeOf((*MockActorService)(nil).GetList), dto)
+}
+
+// GetMovies mocks base method.
+func (m *MockActorService) GetMovies(dto *domain.GetActorMovies) ([]domain.Movie, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMovies", dto)
+	ret0, _ := ret[0].([]domain.Movie)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMovies indicates an expected call of GetMovies.
+func (mr *MockActorServiceMockRecorder) GetMovies(dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMovies", reflect.TypeOf((*MockActorService)(nil).GetMovies), dto)
+}
+
+// Patch mocks base method.
+func (m *MockActorService) Patch(dto *domain.PatchActor) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Patch", dto)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Patch indicates an expected call of Patch.
+func (mr *MockActorServiceMockRecorder) Patch(dto interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetList", reflect.TypeOf((*MockActorService)(nil).GetList))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Patch", reflect.TypeOf((*MockActorService)(nil).Patch), dto)
 }
 
 // Update mocks base method.
@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./interfaces.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	domain "github.com/akrovv/filmlibrary/internal/domain"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIMDbService is a mock of IMDbService interface.
+type MockIMDbService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIMDbServiceMockRecorder
+}
+
+// MockIMDbServiceMockRecorder is the mock recorder for MockIMDbService.
+type MockIMDbServiceMockRecorder struct {
+	mock *MockIMDbService
+}
+
+// NewMockIMDbService creates a new mock instance.
+func NewMockIMDbService(ctrl *gomock.Controller) *MockIMDbService {
+	mock := &MockIMDbService{ctrl: ctrl}
+	mock.recorder = &MockIMDbServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIMDbService) EXPECT() *MockIMDbServiceMockRecorder {
+	return m.recorder
+}
+
+// ImportIMDb mocks base method.
+func (m *MockIMDbService) ImportIMDb(source domain.IMDbSource) (*domain.IMDbReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportIMDb", source)
+	ret0, _ := ret[0].(*domain.IMDbReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportIMDb indicates an expected call of ImportIMDb.
+func (mr *MockIMDbServiceMockRecorder) ImportIMDb(source interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportIMDb", reflect.TypeOf((*MockIMDbService)(nil).ImportIMDb), source)
+}
@@ -34,6 +34,20 @@ func (m *MockMovieService) EXPECT() *MockMovieServiceMockRecorder {
 	return m.recorder
 }
 
+// AddActor mocks base method.
+func (m *MockMovieService) AddActor(dto *domain.MovieActor) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddActor", dto)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddActor indicates an expected call of AddActor.
+func (mr *MockMovieServiceMockRecorder) AddActor(dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddActor", reflect.TypeOf((*MockMovieService)(nil).AddActor), dto)
+}
+
 // Create mocks base method.
 func (m *MockMovieService) Create(dto *domain.CreateMovie) error {
 	m.ctrl.T.Helper()
@@ -63,10 +77,10 @@ func (mr *MockMovieServiceMockRecorder) Delete(dto interface{}) *gomock.Call {
 }
 
 // Get mocks base method.
-func (m *MockMovieService) Get(dto *domain.GetMovie) (*domain.MovieWithoudID, error) {
+func (m *MockMovieService) Get(dto *domain.GetMovie) (*domain.MoviePage, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Get", dto)
-	ret0, _ := ret[0].(*domain.MovieWithoudID)
+	ret0, _ := ret[0].(*domain.MoviePage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -77,11 +91,41 @@ func (mr *MockMovieServiceMockRecorder) Get(dto interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockMovieService)(nil).Get), dto)
 }
 
+// GetActors mocks base method.
+func (m *MockMovieService) GetActors(dto *domain.GetMovieActors) ([]domain.Actor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActors", dto)
+	ret0, _ := ret[0].([]domain.Actor)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActors indicates an expected call of GetActors.
+func (mr *MockMovieServiceMockRecorder) GetActors(dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActors", reflect.TypeOf((*MockMovieService)(nil).GetActors), dto)
+}
+
+// GetByID mocks base method.
+func (m *MockMovieService) GetByID(dto *domain.GetMovieByID) (*domain.MovieWithActors, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", dto)
+	ret0, _ := ret[0].(*domain.MovieWithActors)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockMovieServiceMockRecorder) GetByID(dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockMovieService)(nil).GetByID), dto)
+}
+
 // GetOrderedList mocks base method.
-func (m *MockMovieService) GetOrderedList(dto *domain.GetOrderedMovie) ([]domain.MovieWithoudID, error) {
+func (m *MockMovieService) GetOrderedList(dto *domain.GetOrderedMovie) (*domain.MovieList, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetOrderedList", dto)
-	ret0, _ := ret[0].([]domain.MovieWithoudID)
+	ret0, _ := ret[0].(*domain.MovieList)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -92,6 +136,49 @@ func (mr *MockMovieServiceMockRecorder) GetOrderedList(dto interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderedList", reflect.TypeOf((*MockMovieService)(nil).GetOrderedList), dto)
 }
 
+// Patch mocks base method.
+func (m *MockMovieService) Patch(dto *domain.PatchMovie) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Patch", dto)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Patch indicates an expected call of Patch.
+func (mr *MockMovieServiceMockRecorder) Patch(dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Patch", reflect.TypeOf((*MockMovieService)(nil).Patch), dto)
+}
+
+// RemoveActor mocks base method.
+func (m *MockMovieService) RemoveActor(dto *domain.MovieActor) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveActor", dto)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveActor indicates an expected call of RemoveActor.
+func (mr *MockMovieServiceMockRecorder) RemoveActor(dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveActor", reflect.TypeOf((*MockMovieService)(nil).RemoveActor), dto)
+}
+
+// Search mocks base method.
+func (m *MockMovieService) Search(dto *domain.SearchMovies) (*domain.SearchPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", dto)
+	ret0, _ := ret[0].(*domain.SearchPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockMovieServiceMockRecorder) Search(dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockMovieService)(nil).Search), dto)
+}
+
 // Update mocks base method.
 func (m *MockMovieService) Update(dto *domain.Movie) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,63 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./interfaces.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	domain "github.com/akrovv/filmlibrary/internal/domain"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockPrivilegeHolder is a mock of PrivilegeHolder interface.
+type MockPrivilegeHolder struct {
+	ctrl     *gomock.Controller
+	recorder *MockPrivilegeHolderMockRecorder
+}
+
+// MockPrivilegeHolderMockRecorder is the mock recorder for MockPrivilegeHolder.
+type MockPrivilegeHolderMockRecorder struct {
+	mock *MockPrivilegeHolder
+}
+
+// NewMockPrivilegeHolder creates a new mock instance.
+func NewMockPrivilegeHolder(ctrl *gomock.Controller) *MockPrivilegeHolder {
+	mock := &MockPrivilegeHolder{ctrl: ctrl}
+	mock.recorder = &MockPrivilegeHolderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPrivilegeHolder) EXPECT() *MockPrivilegeHolderMockRecorder {
+	return m.recorder
+}
+
+// Revoke mocks base method.
+func (m *MockPrivilegeHolder) Revoke(dto *domain.RevokeUser) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", dto)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockPrivilegeHolderMockRecorder) Revoke(dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockPrivilegeHolder)(nil).Revoke), dto)
+}
+
+// SetAdmin mocks base method.
+func (m *MockPrivilegeHolder) SetAdmin(dto *domain.SetAdmin) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAdmin", dto)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAdmin indicates an expected call of SetAdmin.
+func (mr *MockPrivilegeHolderMockRecorder) SetAdmin(dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAdmin", reflect.TypeOf((*MockPrivilegeHolder)(nil).SetAdmin), dto)
+}
@@ -0,0 +1,79 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./interfaces.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	domain "github.com/akrovv/filmlibrary/internal/domain"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRefreshTokenStorage is a mock of RefreshTokenStorage interface.
+type MockRefreshTokenStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockRefreshTokenStorageMockRecorder
+}
+
+// MockRefreshTokenStorageMockRecorder is the mock recorder for MockRefreshTokenStorage.
+type MockRefreshTokenStorageMockRecorder struct {
+	mock *MockRefreshTokenStorage
+}
+
+// NewMockRefreshTokenStorage creates a new mock instance.
+func NewMockRefreshTokenStorage(ctrl *gomock.Controller) *MockRefreshTokenStorage {
+	mock := &MockRefreshTokenStorage{ctrl: ctrl}
+	mock.recorder = &MockRefreshTokenStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRefreshTokenStorage) EXPECT() *MockRefreshTokenStorageMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockRefreshTokenStorage) Create(user *domain.User) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", user)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockRefreshTokenStorageMockRecorder) Create(user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRefreshTokenStorage)(nil).Create), user)
+}
+
+// Revoke mocks base method.
+func (m *MockRefreshTokenStorage) Revoke(dto *domain.RevokeUser) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", dto)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockRefreshTokenStorageMockRecorder) Revoke(dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockRefreshTokenStorage)(nil).Revoke), dto)
+}
+
+// Use mocks base method.
+func (m *MockRefreshTokenStorage) Use(dto *domain.UseRefreshToken) (*domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Use", dto)
+	ret0, _ := ret[0].(*domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Use indicates an expected call of Use.
+func (mr *MockRefreshTokenStorageMockRecorder) Use(dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Use", reflect.TypeOf((*MockRefreshTokenStorage)(nil).Use), dto)
+}
@@ -35,10 +35,10 @@ func (m *MockSessionService) EXPECT() *MockSessionServiceMockRecorder {
 }
 
 // Create mocks base method.
-func (m *MockSessionService) Create(dto *domain.CreateSession) (string, error) {
+func (m *MockSessionService) Create(dto *domain.CreateSession) (*domain.ActiveSession, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Create", dto)
-	ret0, _ := ret[0].(string)
+	ret0, _ := ret[0].(*domain.ActiveSession)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -49,11 +49,25 @@ func (mr *MockSessionServiceMockRecorder) Create(dto interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSessionService)(nil).Create), dto)
 }
 
+// Delete mocks base method.
+func (m *MockSessionService) Delete(dto *domain.DeleteSession) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", dto)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockSessionServiceMockRecorder) Delete(dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockSessionService)(nil).Delete), dto)
+}
+
 // Get mocks base method.
-func (m *MockSessionService) Get(dto *domain.GetSession) (*domain.User, error) {
+func (m *MockSessionService) Get(dto *domain.GetSession) (*domain.ActiveSession, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Get", dto)
-	ret0, _ := ret[0].(*domain.User)
+	ret0, _ := ret[0].(*domain.ActiveSession)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -63,3 +77,18 @@ func (mr *MockSessionServiceMockRecorder) Get(dto interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockSessionService)(nil).Get), dto)
 }
+
+// List mocks base method.
+func (m *MockSessionService) List(dto *domain.ListSessions) (*domain.SessionList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", dto)
+	ret0, _ := ret[0].(*domain.SessionList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockSessionServiceMockRecorder) List(dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockSessionService)(nil).List), dto)
+}
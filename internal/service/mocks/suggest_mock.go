@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./interfaces.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	domain "github.com/akrovv/filmlibrary/internal/domain"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockSuggestService is a mock of SuggestService interface.
+type MockSuggestService struct {
+	ctrl     *gomock.Controller
+	recorder *MockSuggestServiceMockRecorder
+}
+
+// MockSuggestServiceMockRecorder is the mock recorder for MockSuggestService.
+type MockSuggestServiceMockRecorder struct {
+	mock *MockSuggestService
+}
+
+// NewMockSuggestService creates a new mock instance.
+func NewMockSuggestService(ctrl *gomock.Controller) *MockSuggestService {
+	mock := &MockSuggestService{ctrl: ctrl}
+	mock.recorder = &MockSuggestServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSuggestService) EXPECT() *MockSuggestServiceMockRecorder {
+	return m.recorder
+}
+
+// Suggest mocks base method.
+func (m *MockSuggestService) Suggest(dto *domain.Suggest) (*domain.SuggestList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Suggest", dto)
+	ret0, _ := ret[0].(*domain.SuggestList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Suggest indicates an expected call of Suggest.
+func (mr *MockSuggestServiceMockRecorder) Suggest(dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Suggest", reflect.TypeOf((*MockSuggestService)(nil).Suggest), dto)
+}
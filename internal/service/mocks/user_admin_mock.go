@@ -0,0 +1,106 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./interfaces.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	domain "github.com/akrovv/filmlibrary/internal/domain"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockUserAdminService is a mock of UserAdminService interface.
+type MockUserAdminService struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserAdminServiceMockRecorder
+}
+
+// MockUserAdminServiceMockRecorder is the mock recorder for MockUserAdminService.
+type MockUserAdminServiceMockRecorder struct {
+	mock *MockUserAdminService
+}
+
+// NewMockUserAdminService creates a new mock instance.
+func NewMockUserAdminService(ctrl *gomock.Controller) *MockUserAdminService {
+	mock := &MockUserAdminService{ctrl: ctrl}
+	mock.recorder = &MockUserAdminServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserAdminService) EXPECT() *MockUserAdminServiceMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockUserAdminService) Delete(dto *domain.DeleteUser) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", dto)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockUserAdminServiceMockRecorder) Delete(dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockUserAdminService)(nil).Delete), dto)
+}
+
+// List mocks base method.
+func (m *MockUserAdminService) List() ([]domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List")
+	ret0, _ := ret[0].([]domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockUserAdminServiceMockRecorder) List() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockUserAdminService)(nil).List))
+}
+
+// Register mocks base method.
+func (m *MockUserAdminService) Register(user *domain.CRUser) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Register", user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Register indicates an expected call of Register.
+func (mr *MockUserAdminServiceMockRecorder) Register(user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockUserAdminService)(nil).Register), user)
+}
+
+// SetAdmin mocks base method.
+func (m *MockUserAdminService) SetAdmin(dto *domain.SetAdmin) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAdmin", dto)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAdmin indicates an expected call of SetAdmin.
+func (mr *MockUserAdminServiceMockRecorder) SetAdmin(dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAdmin", reflect.TypeOf((*MockUserAdminService)(nil).SetAdmin), dto)
+}
+
+// SetPassword mocks base method.
+func (m *MockUserAdminService) SetPassword(user *domain.CRUser) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPassword", user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPassword indicates an expected call of SetPassword.
+func (mr *MockUserAdminServiceMockRecorder) SetPassword(user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPassword", reflect.TypeOf((*MockUserAdminService)(nil).SetPassword), user)
+}
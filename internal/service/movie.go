@@ -3,31 +3,84 @@ package service
 import "github.com/akrovv/filmlibrary/internal/domain"
 
 type movieService struct {
-	storage MovieStorage
+	storage    MovieStorage
+	similarity float64
 }
 
-func NewMovieService(storage MovieStorage) *movieService {
+// NewMovieService returns the movie service, similarity is the threshold of
+// the fuzzy searches that don't set their own, domain.DefaultSimilarity when
+// it is out of (0, 1].
+func NewMovieService(storage MovieStorage, similarity float64) *movieService {
+	if similarity <= 0 || similarity > 1 {
+		similarity = domain.DefaultSimilarity
+	}
+
 	return &movieService{
-		storage: storage,
+		storage:    storage,
+		similarity: similarity,
 	}
 }
 
 func (s *movieService) Create(dto *domain.CreateMovie) error {
+	if err := dto.Validate(); err != nil {
+		return err
+	}
+
 	return s.storage.Create(dto)
 }
 
 func (s *movieService) Update(dto *domain.Movie) error {
+	if err := dto.Validate(); err != nil {
+		return err
+	}
+
 	return s.storage.Update(dto)
 }
 
+func (s *movieService) Patch(dto *domain.PatchMovie) error {
+	if err := dto.Validate(); err != nil {
+		return err
+	}
+
+	return s.storage.Patch(dto)
+}
+
+func (s *movieService) AddActor(dto *domain.MovieActor) error {
+	return s.storage.AddActor(dto)
+}
+
+func (s *movieService) RemoveActor(dto *domain.MovieActor) error {
+	return s.storage.RemoveActor(dto)
+}
+
 func (s *movieService) Delete(dto *domain.DeleteMovie) error {
 	return s.storage.Delete(dto)
 }
 
-func (s *movieService) Get(dto *domain.GetMovie) (*domain.MovieWithoudID, error) {
+func (s *movieService) Get(dto *domain.GetMovie) (*domain.MoviePage, error) {
+	if dto.Similarity == 0 {
+		dto.Similarity = s.similarity
+	}
+
 	return s.storage.Get(dto)
 }
 
-func (s *movieService) GetOrderedList(dto *domain.GetOrderedMovie) ([]domain.MovieWithoudID, error) {
+func (s *movieService) Search(dto *domain.SearchMovies) (*domain.SearchPage, error) {
+	return s.storage.Search(dto)
+}
+
+func (s *movieService) GetOrderedList(dto *domain.GetOrderedMovie) (*domain.MovieList, error) {
+	if dto.YearBucket <= 0 {
+		dto.YearBucket = domain.DefaultYearBucket
+	}
+
 	return s.storage.GetOrderedList(dto)
 }
+
+func (s *movieService) GetByID(dto *domain.GetMovieByID) (*domain.MovieWithActors, error) {
+	return s.storage.GetByID(dto)
+}
+
+func (s *movieService) GetActors(dto *domain.GetMovieActors) ([]domain.Actor, error) {
+	return s.storage.GetActors(dto)
+}
@@ -12,10 +12,18 @@ func NewSessionService(storage SessionStorage) *sessionService {
 	}
 }
 
-func (s *sessionService) Create(dto *domain.CreateSession) (string, error) {
+func (s *sessionService) Create(dto *domain.CreateSession) (*domain.ActiveSession, error) {
 	return s.storage.Create(dto)
 }
 
-func (s *sessionService) Get(dto *domain.GetSession) (*domain.User, error) {
+func (s *sessionService) Get(dto *domain.GetSession) (*domain.ActiveSession, error) {
 	return s.storage.Get(dto)
 }
+
+func (s *sessionService) List(dto *domain.ListSessions) (*domain.SessionList, error) {
+	return s.storage.List(dto)
+}
+
+func (s *sessionService) Delete(dto *domain.DeleteSession) error {
+	return s.storage.Delete(dto)
+}
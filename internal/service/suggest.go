@@ -0,0 +1,38 @@
+package service
+
+import "github.com/akrovv/filmlibrary/internal/domain"
+
+type suggestService struct {
+	storage SuggestStorage
+	cache   SuggestCache
+}
+
+// NewSuggestService returns the suggestion service, cache may be nil to
+// query the database on every keystroke.
+func NewSuggestService(storage SuggestStorage, cache SuggestCache) *suggestService {
+	return &suggestService{
+		storage: storage,
+		cache:   cache,
+	}
+}
+
+// Suggest answers from the cache when it can. The cache only saves work, so
+// a failing one is passed over rather than failing the suggestions.
+func (s *suggestService) Suggest(dto *domain.Suggest) (*domain.SuggestList, error) {
+	if s.cache != nil {
+		if list, err := s.cache.Get(dto); err == nil && list != nil {
+			return list, nil
+		}
+	}
+
+	list, err := s.storage.Suggest(dto)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.cache != nil {
+		_ = s.cache.Set(dto, list)
+	}
+
+	return list, nil
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+)
+
+const DefaultAccessTokenTTL = time.Minute * 15
+
+type tokenService struct {
+	users   UserStorage
+	refresh RefreshTokenStorage
+	signer  TokenSigner
+	ttl     time.Duration
+}
+
+// NewTokenService returns the service of the bearer tokens, whose access
+// tokens are valid for ttl, DefaultAccessTokenTTL when it is zero.
+func NewTokenService(users UserStorage, refresh RefreshTokenStorage, signer TokenSigner, ttl time.Duration) *tokenService {
+	if ttl <= 0 {
+		ttl = DefaultAccessTokenTTL
+	}
+
+	return &tokenService{
+		users:   users,
+		refresh: refresh,
+		signer:  signer,
+		ttl:     ttl,
+	}
+}
+
+// Issue grants a token pair for the credentials of a user or for a refresh
+// token, which is spent and replaced by the refresh token of the new pair.
+func (s *tokenService) Issue(dto *domain.TokenRequest) (*domain.TokenPair, error) {
+	if err := dto.Validate(); err != nil {
+		return nil, err
+	}
+
+	var (
+		user *domain.User
+		err  error
+	)
+
+	switch dto.GrantType {
+	case domain.GrantPassword:
+		user, err = s.users.Login(&domain.CRUser{Username: dto.Username, Password: dto.Password})
+	case domain.GrantRefreshToken:
+		user, err = s.refreshUser(dto.RefreshToken)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	access, _, err := s.signer.Issue(user.Username, user.IsAdmin, s.ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	refresh, err := s.refresh.Create(user)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.TokenPair{
+		AccessToken:  access,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(s.ttl.Seconds()),
+		RefreshToken: refresh,
+	}, nil
+}
+
+// refreshUser spends a refresh token and returns its user as the Users table
+// has them now, so that a deleted user gets no more tokens and a demoted one
+// no more admin tokens.
+func (s *tokenService) refreshUser(refreshToken string) (*domain.User, error) {
+	spent, err := s.refresh.Use(&domain.UseRefreshToken{Token: refreshToken})
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.users.Get(&domain.GetUser{Username: spent.Username})
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, fmt.Errorf("%w: user %s no longer exists", domain.ErrUnauthorized, spent.Username)
+	}
+
+	return user, err
+}
+
+// Authenticate returns the user an access token was issued to.
+func (s *tokenService) Authenticate(dto *domain.GetAccessToken) (*domain.User, error) {
+	claims, err := s.signer.Verify(dto.Token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domain.ErrUnauthorized, err)
+	}
+
+	return &domain.User{
+		Username: claims.Subject,
+		IsAdmin:  claims.Admin,
+	}, nil
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+	"github.com/akrovv/filmlibrary/internal/service/mocks"
+	"github.com/golang/mock/gomock"
+)
+
+func TestTokenIssueRefresh(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	us := mocks.NewMockUserStorage(ctrl)
+	rs := mocks.NewMockRefreshTokenStorage(ctrl)
+	signer := mocks.NewMockTokenSigner(ctrl)
+
+	service := NewTokenService(us, rs, signer, time.Minute)
+	dto := &domain.TokenRequest{GrantType: domain.GrantRefreshToken, RefreshToken: "refresh"}
+
+	// OK. The privileges come from the Users table, not the spent token
+	rs.EXPECT().Use(&domain.UseRefreshToken{Token: "refresh"}).Return(&domain.User{Username: "user", IsAdmin: true}, nil)
+	us.EXPECT().Get(&domain.GetUser{Username: "user"}).Return(&domain.User{Username: "user"}, nil)
+	signer.EXPECT().Issue("user", false, time.Minute).Return("access", time.Time{}, nil)
+	rs.EXPECT().Create(&domain.User{Username: "user"}).Return("next", nil)
+
+	pair, err := service.Issue(dto)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if pair.AccessToken != "access" || pair.RefreshToken != "next" || pair.ExpiresIn != 60 {
+		t.Errorf("unexpected token pair: %+v", pair)
+	}
+
+	// User deleted since the token was issued
+	rs.EXPECT().Use(&domain.UseRefreshToken{Token: "refresh"}).Return(&domain.User{Username: "user"}, nil)
+	us.EXPECT().Get(&domain.GetUser{Username: "user"}).Return(nil, domain.ErrNotFound)
+
+	if _, err = service.Issue(dto); !errors.Is(err, domain.ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized, got: %v", err)
+	}
+
+	// Token spent or expired
+	rs.EXPECT().Use(&domain.UseRefreshToken{Token: "refresh"}).Return(nil, domain.ErrUnauthorized)
+
+	if _, err = service.Issue(dto); !errors.Is(err, domain.ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized, got: %v", err)
+	}
+
+	// Postgres returned error
+	rs.EXPECT().Use(&domain.UseRefreshToken{Token: "refresh"}).Return(&domain.User{Username: "user"}, nil)
+	us.EXPECT().Get(&domain.GetUser{Username: "user"}).Return(nil, domain.ErrTest)
+
+	if _, err = service.Issue(dto); !errors.Is(err, domain.ErrTest) {
+		t.Errorf("expected ErrTest, got: %v", err)
+	}
+}
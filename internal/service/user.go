@@ -3,19 +3,71 @@ package service
 import "github.com/akrovv/filmlibrary/internal/domain"
 
 type userService struct {
-	storage UserStorage
+	storage       UserStorage
+	sessions      PrivilegeHolder
+	refreshTokens Revoker
 }
 
-func NewUserService(storage UserStorage) *userService {
+// NewUserService returns a service of users whose privilege changes are
+// carried over to their sessions, and who are logged out of them and lose
+// their refresh tokens when they are deleted or their password changes.
+// Refresh tokens keep no privileges, the user is read again when one is used.
+func NewUserService(storage UserStorage, sessions PrivilegeHolder, refreshTokens Revoker) *userService {
 	return &userService{
-		storage: storage,
+		storage:       storage,
+		sessions:      sessions,
+		refreshTokens: refreshTokens,
 	}
 }
 
 func (s *userService) Register(user *domain.CRUser) error {
+	if err := user.Validate(); err != nil {
+		return err
+	}
+
 	return s.storage.Register(user)
 }
 
 func (s *userService) Login(user *domain.CRUser) (*domain.User, error) {
 	return s.storage.Login(user)
 }
+
+func (s *userService) SetPassword(user *domain.CRUser) error {
+	if err := user.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.storage.SetPassword(user); err != nil {
+		return err
+	}
+
+	return s.revoke(user.Username)
+}
+
+func (s *userService) SetAdmin(dto *domain.SetAdmin) error {
+	if err := s.storage.SetAdmin(dto); err != nil {
+		return err
+	}
+
+	return s.sessions.SetAdmin(dto)
+}
+
+func (s *userService) Delete(dto *domain.DeleteUser) error {
+	if err := s.storage.Delete(dto); err != nil {
+		return err
+	}
+
+	return s.revoke(dto.Username)
+}
+
+func (s *userService) revoke(username string) error {
+	if err := s.sessions.Revoke(&domain.RevokeUser{Username: username}); err != nil {
+		return err
+	}
+
+	return s.refreshTokens.Revoke(&domain.RevokeUser{Username: username})
+}
+
+func (s *userService) List() ([]domain.User, error) {
+	return s.storage.List()
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+	"github.com/akrovv/filmlibrary/internal/service/mocks"
+	"github.com/golang/mock/gomock"
+)
+
+func TestUserDelete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	us := mocks.NewMockUserStorage(ctrl)
+	sessions := mocks.NewMockPrivilegeHolder(ctrl)
+	refresh := mocks.NewMockRevoker(ctrl)
+
+	service := NewUserService(us, sessions, refresh)
+
+	// OK. The sessions and refresh tokens of the user end with it
+	gomock.InOrder(
+		us.EXPECT().Delete(&domain.DeleteUser{Username: "admin"}).Return(nil),
+		sessions.EXPECT().Revoke(&domain.RevokeUser{Username: "admin"}).Return(nil),
+		refresh.EXPECT().Revoke(&domain.RevokeUser{Username: "admin"}).Return(nil),
+	)
+
+	if err := service.Delete(&domain.DeleteUser{Username: "admin"}); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	// User not found, nothing to revoke
+	us.EXPECT().Delete(&domain.DeleteUser{Username: "admin"}).Return(domain.ErrNotFound)
+
+	if err := service.Delete(&domain.DeleteUser{Username: "admin"}); err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	// Holder returned error
+	us.EXPECT().Delete(&domain.DeleteUser{Username: "admin"}).Return(nil)
+	sessions.EXPECT().Revoke(&domain.RevokeUser{Username: "admin"}).Return(domain.ErrTest)
+
+	if err := service.Delete(&domain.DeleteUser{Username: "admin"}); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
+
+func TestUserSetPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	us := mocks.NewMockUserStorage(ctrl)
+	sessions := mocks.NewMockPrivilegeHolder(ctrl)
+	refresh := mocks.NewMockRevoker(ctrl)
+
+	service := NewUserService(us, sessions, refresh)
+	user := &domain.CRUser{Username: "user", Password: "new password"}
+
+	// OK. The user is logged out everywhere
+	gomock.InOrder(
+		us.EXPECT().SetPassword(user).Return(nil),
+		sessions.EXPECT().Revoke(&domain.RevokeUser{Username: "user"}).Return(nil),
+		refresh.EXPECT().Revoke(&domain.RevokeUser{Username: "user"}).Return(nil),
+	)
+
+	if err := service.SetPassword(user); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	// Storage returned error, nothing to revoke
+	us.EXPECT().SetPassword(user).Return(domain.ErrTest)
+
+	if err := service.SetPassword(user); err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	// Invalid password
+	if err := service.SetPassword(&domain.CRUser{Username: "user"}); err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	// Holder returned error
+	us.EXPECT().SetPassword(user).Return(nil)
+	sessions.EXPECT().Revoke(&domain.RevokeUser{Username: "user"}).Return(nil)
+	refresh.EXPECT().Revoke(&domain.RevokeUser{Username: "user"}).Return(domain.ErrTest)
+
+	if err := service.SetPassword(user); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
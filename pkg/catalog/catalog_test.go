@@ -0,0 +1,109 @@
+package catalog
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+)
+
+func TestRoundTrip(t *testing.T) {
+	releaseDate := time.Date(1995, 12, 15, 0, 0, 0, 0, time.UTC)
+	dateBirth := time.Date(1940, 4, 25, 0, 0, 0, 0, time.UTC)
+
+	actors := []domain.ExportActor{
+		{ID: 1, ExternalID: "nm0000199", Name: "Al Pacino", Gender: "Male", DateBirth: dateBirth},
+		{ID: 2, ExternalID: "nm0000134", Name: "Robert De Niro", Gender: "Male"},
+	}
+	movies := []domain.ExportMovie{
+		{ID: 1, ExternalID: "tt0113277", Title: "Heat", Description: "description", ReleaseDate: releaseDate, Rating: 8, Actors: actors},
+		// As the IMDb import leaves them, without a description or rating
+		{ID: 2, ExternalID: "tt0122690", Title: "Ronin", Actors: actors[1:]},
+		{ID: 3, Title: "Untitled"},
+	}
+
+	cast := func(actors []domain.ExportActor) []domain.ImportActor {
+		imported := make([]domain.ImportActor, 0, len(actors))
+		for _, a := range actors {
+			imported = append(imported, domain.ImportActor{ExternalID: a.ExternalID, Name: a.Name, Gender: a.Gender, DateBirth: a.DateBirth})
+		}
+		return imported
+	}
+
+	expectedActors := cast(actors)
+	expectedMovies := make([]domain.ImportMovie, 0, len(movies))
+	for _, m := range movies {
+		expectedMovies = append(expectedMovies, domain.ImportMovie{
+			ExternalID:  m.ExternalID,
+			Title:       m.Title,
+			Description: m.Description,
+			ReleaseDate: m.ReleaseDate,
+			Rating:      m.Rating,
+			Actors:      cast(m.Actors),
+		})
+	}
+
+	for _, format := range []string{CSV, JSON, NDJSON} {
+		buf := bytes.Buffer{}
+
+		encoder, err := NewEncoder(&buf, format)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", format, err)
+		}
+
+		if err = encoder.WriteManifest(&domain.ExportManifest{SchemaVersion: 8, Movies: 3, Actors: 2, MovieActors: 3}); err != nil {
+			t.Fatalf("%s: unexpected error: %s", format, err)
+		}
+		for i := range actors {
+			if err = encoder.WriteActor(&actors[i]); err != nil {
+				t.Fatalf("%s: unexpected error: %s", format, err)
+			}
+		}
+		for i := range movies {
+			if err = encoder.WriteMovie(&movies[i]); err != nil {
+				t.Fatalf("%s: unexpected error: %s", format, err)
+			}
+		}
+		if err = encoder.Close(); err != nil {
+			t.Fatalf("%s: unexpected error: %s", format, err)
+		}
+
+		rows, err := DecodeMovies(&buf, format)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", format, err)
+		}
+
+		if len(rows) != len(actors)+len(movies) {
+			t.Fatalf("%s: expected %d rows, got: %d", format, len(actors)+len(movies), len(rows))
+		}
+
+		for i, row := range rows {
+			if row.Err != nil {
+				t.Errorf("%s: row %d: unexpected error: %s", format, row.Row, row.Err)
+				continue
+			}
+
+			if i < len(actors) {
+				if row.Actor == nil || !reflect.DeepEqual(*row.Actor, expectedActors[i]) {
+					t.Errorf("%s: expected actor: %+v, got: %+v", format, expectedActors[i], row.Actor)
+				}
+				if err = row.Actor.Validate(); err != nil {
+					t.Errorf("%s: actor %s: unexpected error: %s", format, row.Actor.Name, err)
+				}
+				continue
+			}
+
+			expected := expectedMovies[i-len(actors)]
+			if row.Actor != nil || !reflect.DeepEqual(row.Movie, expected) {
+				t.Errorf("%s: expected movie: %+v, got: %+v", format, expected, row.Movie)
+			}
+
+			// An export must import back
+			if err = row.Movie.Validate(); err != nil {
+				t.Errorf("%s: movie %s: unexpected error: %s", format, row.Movie.Title, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,418 @@
+package catalog
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+)
+
+const (
+	CSV    = "csv"
+	JSON   = "json"
+	NDJSON = "ndjson"
+)
+
+// maxLine is the longest line of an NDJSON file, a movie with its whole cast.
+const maxLine = 1 << 20
+
+// CSVColumns are the columns of a CSV file. Each line holds a movie and one
+// of its cast members, consecutive lines of the same movie are merged. A line
+// with the movie columns left empty is an actor of its own. The IDs only tell
+// the movies of an export apart and are not kept by an import.
+var CSVColumns = []string{
+	"movie_id", "external_id", "movie_title", "description", "release_date", "rating",
+	"actor_id", "actor_external_id", "actor_name", "gender", "date_of_birth",
+}
+
+// The types of the records of a JSON or NDJSON file. A record without a type
+// is a movie, and the manifest of an export is skipped by an import.
+const (
+	manifestType = "manifest"
+	actorType    = "actor"
+	movieType    = "movie"
+)
+
+// FormatOf returns the format of a file given its content type or name,
+// or an empty string when it isn't known.
+func FormatOf(contentType, name string) string {
+	switch strings.TrimSpace(strings.Split(contentType, ";")[0]) {
+	case "text/csv":
+		return CSV
+	case "application/json":
+		return JSON
+	case "application/x-ndjson", "application/ndjson":
+		return NDJSON
+	}
+
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".csv":
+		return CSV
+	case ".json":
+		return JSON
+	case ".ndjson", ".jsonl":
+		return NDJSON
+	}
+
+	return ""
+}
+
+type manifestRecord struct {
+	Type          string    `json:"type"`
+	SchemaVersion int64     `json:"schema_version"`
+	ExportedAt    time.Time `json:"exported_at"`
+	Movies        int64     `json:"movies"`
+	Actors        int64     `json:"actors"`
+	MovieActors   int64     `json:"movie_actors"`
+}
+
+type actorRecord struct {
+	Type       string `json:"type,omitempty"`
+	ID         int64  `json:"actor_id,omitempty"`
+	ExternalID string `json:"external_id,omitempty"`
+	Name       string `json:"actor_name,omitempty"`
+	Gender     string `json:"gender,omitempty"`
+	DateBirth  string `json:"date_of_birth,omitempty"`
+}
+
+type movieRecord struct {
+	Type        string        `json:"type,omitempty"`
+	ID          int64         `json:"movie_id,omitempty"`
+	ExternalID  string        `json:"external_id,omitempty"`
+	Title       string        `json:"movie_title"`
+	Description string        `json:"description,omitempty"`
+	ReleaseDate string        `json:"release_date,omitempty"`
+	Rating      uint8         `json:"rating,omitempty"`
+	Actors      []actorRecord `json:"actors"`
+}
+
+// DecodeMovies reads the movies and actors of an import file or an export.
+// A file that can't be read through is an error, while a record that can't
+// be read is returned as a row with Err set.
+func DecodeMovies(r io.Reader, format string) ([]domain.ImportRow, error) {
+	switch format {
+	case CSV:
+		return decodeCSV(r)
+	case JSON:
+		return decodeJSON(r)
+	case NDJSON:
+		return decodeNDJSON(r)
+	}
+
+	return nil, domain.NewFieldError(domain.ErrRequest, "format", fmt.Sprintf("must be one of %s, %s, %s", CSV, JSON, NDJSON))
+}
+
+func decodeJSON(r io.Reader) ([]domain.ImportRow, error) {
+	decoder := json.NewDecoder(r)
+
+	if token, err := decoder.Token(); err != nil || token != json.Delim('[') {
+		return nil, fmt.Errorf("%w: a JSON array of movies is expected", domain.ErrRequest)
+	}
+
+	rows := make([]domain.ImportRow, 0)
+	for n := 1; decoder.More(); n++ {
+		data := json.RawMessage{}
+		if err := decoder.Decode(&data); err != nil {
+			return nil, fmt.Errorf("%w: record %d: %s", domain.ErrRequest, n, err)
+		}
+
+		if row, ok := recordRow(n, data); ok {
+			rows = append(rows, row)
+		}
+	}
+
+	if _, err := decoder.Token(); err != nil {
+		return nil, fmt.Errorf("%w: %s", domain.ErrRequest, err)
+	}
+
+	return rows, nil
+}
+
+func decodeNDJSON(r io.Reader) ([]domain.ImportRow, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLine)
+
+	rows := make([]domain.ImportRow, 0)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+
+		if row, ok := recordRow(line, scanner.Bytes()); ok {
+			rows = append(rows, row)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %s", domain.ErrRequest, err)
+	}
+
+	return rows, nil
+}
+
+// recordRow reads the n-th record of a JSON or NDJSON file, it returns false
+// for a manifest.
+func recordRow(n int, data []byte) (domain.ImportRow, bool) {
+	head := struct {
+		Type string `json:"type"`
+	}{}
+	if err := json.Unmarshal(data, &head); err != nil {
+		return domain.ImportRow{Row: n, Err: fmt.Errorf("%w: %s", domain.ErrRequest, err)}, true
+	}
+
+	switch head.Type {
+	case manifestType:
+		return domain.ImportRow{}, false
+	case actorType:
+		record := actorRecord{}
+		err := json.Unmarshal(data, &record)
+		return actorRow(n, record, err), true
+	case movieType, "":
+		record := movieRecord{}
+		err := json.Unmarshal(data, &record)
+		return movieRow(n, record, err), true
+	}
+
+	return domain.ImportRow{
+		Row: n,
+		Err: domain.NewFieldError(domain.ErrRequest, "type", fmt.Sprintf("must be one of %s, %s, %s", movieType, actorType, manifestType)),
+	}, true
+}
+
+func actorRow(n int, record actorRecord, err error) domain.ImportRow {
+	row := domain.ImportRow{
+		Row: n,
+		Actor: &domain.ImportActor{
+			ExternalID: record.ExternalID,
+			Name:       record.Name,
+			Gender:     record.Gender,
+		},
+	}
+
+	if err != nil {
+		row.Err = fmt.Errorf("%w: %s", domain.ErrRequest, err)
+		return row
+	}
+
+	row.Actor.DateBirth, row.Err = parseDate("date_of_birth", record.DateBirth)
+
+	return row
+}
+
+// movieRow turns a decoded JSON movie into a row, err is the error decoding it.
+func movieRow(n int, record movieRecord, err error) domain.ImportRow {
+	row := domain.ImportRow{
+		Row: n,
+		Movie: domain.ImportMovie{
+			ExternalID:  record.ExternalID,
+			Title:       record.Title,
+			Description: record.Description,
+			Rating:      record.Rating,
+			Actors:      make([]domain.ImportActor, 0, len(record.Actors)),
+		},
+	}
+
+	if err != nil {
+		row.Err = fmt.Errorf("%w: %s", domain.ErrRequest, err)
+		return row
+	}
+
+	if row.Movie.ReleaseDate, row.Err = parseDate("release_date", record.ReleaseDate); row.Err != nil {
+		return row
+	}
+
+	for i, a := range record.Actors {
+		actor := domain.ImportActor{
+			ExternalID: a.ExternalID,
+			Name:       a.Name,
+			Gender:     a.Gender,
+		}
+
+		if actor.DateBirth, row.Err = parseDate(fmt.Sprintf("actors[%d].date_of_birth", i), a.DateBirth); row.Err != nil {
+			return row
+		}
+
+		row.Movie.Actors = append(row.Movie.Actors, actor)
+	}
+
+	return row
+}
+
+func decodeCSV(r io.Reader) ([]domain.ImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.ReuseRecord = true
+	reader.Comment = '#'
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't read the CSV header: %s", domain.ErrRequest, err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+		if !knownColumn(name) {
+			return nil, domain.NewFieldError(domain.ErrRequest, name, "unknown column, expected "+strings.Join(CSVColumns, ", "))
+		}
+		columns[name] = i
+	}
+
+	if _, ok := columns["movie_title"]; !ok {
+		return nil, domain.NewFieldError(domain.ErrRequest, "movie_title", "column is required")
+	}
+
+	rows := make([]domain.ImportRow, 0)
+	lastKey := ""
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			rows = append(rows, domain.ImportRow{
+				Row: parseErr.StartLine,
+				Err: fmt.Errorf("%w: %s", domain.ErrRequest, parseErr.Err),
+			})
+			lastKey = ""
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := reader.FieldPos(0)
+		get := func(column string) string {
+			if i, ok := columns[column]; ok {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		hasActor := get("actor_external_id") != "" || get("actor_name") != ""
+
+		// A movie is told by its ID in an export, its external ID or else
+		// by title and release date.
+		var key string
+		switch {
+		case get("movie_id") != "":
+			key = "movie:" + get("movie_id")
+		case get("external_id") != "":
+			key = "id:" + get("external_id")
+		case get("movie_title") == "" && hasActor:
+			rows = append(rows, csvActor(line, get))
+			lastKey = ""
+			continue
+		default:
+			key = "title:" + get("movie_title") + "\x00" + get("release_date")
+		}
+
+		if key != lastKey {
+			rows = append(rows, csvMovie(line, get))
+			lastKey = key
+		}
+
+		row := &rows[len(rows)-1]
+		if !hasActor {
+			continue
+		}
+
+		i := len(row.Movie.Actors)
+		actor := domain.ImportActor{
+			ExternalID: get("actor_external_id"),
+			Name:       get("actor_name"),
+			Gender:     get("gender"),
+		}
+
+		actor.DateBirth, err = parseDate(fmt.Sprintf("actors[%d].date_of_birth", i), get("date_of_birth"))
+		if err != nil && row.Err == nil {
+			row.Err = err
+		}
+
+		row.Movie.Actors = append(row.Movie.Actors, actor)
+	}
+
+	return rows, nil
+}
+
+// csvMovie reads the movie columns of the first line of a movie.
+func csvMovie(line int, get func(column string) string) domain.ImportRow {
+	row := domain.ImportRow{
+		Row: line,
+		Movie: domain.ImportMovie{
+			ExternalID:  get("external_id"),
+			Title:       get("movie_title"),
+			Description: get("description"),
+			Actors:      make([]domain.ImportActor, 0, 1),
+		},
+	}
+
+	if row.Movie.ReleaseDate, row.Err = parseDate("release_date", get("release_date")); row.Err != nil {
+		return row
+	}
+
+	if rating := get("rating"); rating != "" {
+		value, err := strconv.ParseUint(rating, 10, 8)
+		if err != nil {
+			row.Err = domain.NewFieldError(domain.ErrRequest, "rating", "must be an integer")
+			return row
+		}
+		row.Movie.Rating = uint8(value)
+	}
+
+	return row
+}
+
+// csvActor reads a line that holds an actor alone.
+func csvActor(line int, get func(column string) string) domain.ImportRow {
+	row := domain.ImportRow{
+		Row: line,
+		Actor: &domain.ImportActor{
+			ExternalID: get("actor_external_id"),
+			Name:       get("actor_name"),
+			Gender:     get("gender"),
+		},
+	}
+
+	row.Actor.DateBirth, row.Err = parseDate("date_of_birth", get("date_of_birth"))
+
+	return row
+}
+
+func knownColumn(name string) bool {
+	for _, column := range CSVColumns {
+		if column == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseDate accepts a date as YYYY-MM-DD or RFC 3339, an empty one is zero.
+func parseDate(field, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if date, err := time.Parse("2006-01-02", value); err == nil {
+		return date, nil
+	}
+
+	date, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, domain.NewFieldError(domain.ErrRequest, field, "must be a date as YYYY-MM-DD")
+	}
+
+	return date, nil
+}
@@ -0,0 +1,202 @@
+package catalog
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+)
+
+// ContentType returns the media type of a format.
+func ContentType(format string) string {
+	switch format {
+	case CSV:
+		return "text/csv; charset=utf-8"
+	case JSON:
+		return "application/json"
+	}
+
+	return "application/x-ndjson"
+}
+
+// Encoder writes an export as records of the same shape DecodeMovies reads,
+// so an export can be imported back. It keeps no more than a record in
+// memory and must be closed to finish the file.
+type Encoder struct {
+	w       *bufio.Writer
+	csv     *csv.Writer
+	format  string
+	records int
+}
+
+func NewEncoder(w io.Writer, format string) (*Encoder, error) {
+	if format != CSV && format != JSON && format != NDJSON {
+		return nil, domain.NewFieldError(domain.ErrRequest, "format", fmt.Sprintf("must be one of %s, %s, %s", CSV, JSON, NDJSON))
+	}
+
+	e := &Encoder{
+		w:      bufio.NewWriter(w),
+		format: format,
+	}
+	if format == CSV {
+		e.csv = csv.NewWriter(e.w)
+	}
+
+	return e, nil
+}
+
+// WriteManifest writes the manifest, a comment line ahead of the header of
+// a CSV file.
+func (e *Encoder) WriteManifest(manifest *domain.ExportManifest) error {
+	record := manifestRecord{
+		Type:          manifestType,
+		SchemaVersion: manifest.SchemaVersion,
+		ExportedAt:    manifest.ExportedAt.UTC(),
+		Movies:        manifest.Movies,
+		Actors:        manifest.Actors,
+		MovieActors:   manifest.MovieActors,
+	}
+
+	if e.format != CSV {
+		return e.record(record)
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	if _, err = fmt.Fprintf(e.w, "# %s\n", data); err != nil {
+		return err
+	}
+
+	return e.csv.Write(CSVColumns)
+}
+
+func (e *Encoder) WriteActor(actor *domain.ExportActor) error {
+	if e.format == CSV {
+		return e.csv.Write(append(make([]string, 6), csvActorColumns(actor)...))
+	}
+
+	record := exportActor(actor)
+	record.Type = actorType
+
+	return e.record(record)
+}
+
+// WriteMovie writes a movie with its cast, in a CSV file as a line for each
+// cast member.
+func (e *Encoder) WriteMovie(movie *domain.ExportMovie) error {
+	if e.format != CSV {
+		record := movieRecord{
+			Type:        movieType,
+			ID:          movie.ID,
+			ExternalID:  movie.ExternalID,
+			Title:       movie.Title,
+			Description: movie.Description,
+			ReleaseDate: formatDate(movie.ReleaseDate),
+			Rating:      movie.Rating,
+			Actors:      make([]actorRecord, 0, len(movie.Actors)),
+		}
+
+		for i := range movie.Actors {
+			record.Actors = append(record.Actors, exportActor(&movie.Actors[i]))
+		}
+
+		return e.record(record)
+	}
+
+	columns := []string{
+		strconv.FormatInt(movie.ID, 10), movie.ExternalID, movie.Title, movie.Description,
+		formatDate(movie.ReleaseDate), "",
+	}
+	if movie.Rating != 0 {
+		columns[5] = strconv.Itoa(int(movie.Rating))
+	}
+
+	if len(movie.Actors) == 0 {
+		return e.csv.Write(append(columns, make([]string, 5)...))
+	}
+
+	for i := range movie.Actors {
+		if err := e.csv.Write(append(columns[:6:6], csvActorColumns(&movie.Actors[i])...)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Close finishes the file and flushes what is buffered.
+func (e *Encoder) Close() error {
+	switch e.format {
+	case CSV:
+		e.csv.Flush()
+		if err := e.csv.Error(); err != nil {
+			return err
+		}
+	case JSON:
+		end := "\n]\n"
+		if e.records == 0 {
+			end = "[]\n"
+		}
+		if _, err := e.w.WriteString(end); err != nil {
+			return err
+		}
+	}
+
+	return e.w.Flush()
+}
+
+// record writes a record of a JSON array or a line of an NDJSON file.
+func (e *Encoder) record(record interface{}) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	if e.format == JSON {
+		separator := ",\n"
+		if e.records == 0 {
+			separator = "[\n"
+		}
+		data = append([]byte(separator), data...)
+	} else {
+		data = append(data, '\n')
+	}
+	e.records++
+
+	_, err = e.w.Write(data)
+
+	return err
+}
+
+func exportActor(actor *domain.ExportActor) actorRecord {
+	return actorRecord{
+		ID:         actor.ID,
+		ExternalID: actor.ExternalID,
+		Name:       actor.Name,
+		Gender:     actor.Gender,
+		DateBirth:  formatDate(actor.DateBirth),
+	}
+}
+
+func csvActorColumns(actor *domain.ExportActor) []string {
+	return []string{
+		strconv.FormatInt(actor.ID, 10), actor.ExternalID, actor.Name, actor.Gender, formatDate(actor.DateBirth),
+	}
+}
+
+// formatDate writes a date as YYYY-MM-DD, a zero one as an empty string.
+func formatDate(date time.Time) string {
+	if date.IsZero() {
+		return ""
+	}
+
+	return date.Format("2006-01-02")
+}
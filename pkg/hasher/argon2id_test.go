@@ -0,0 +1,76 @@
+package hasher
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// testArgon2idParams keep the tests fast, the format is the same.
+var testArgon2idParams = Argon2idParams{
+	Memory:     64,
+	Time:       1,
+	Threads:    1,
+	SaltLength: 16,
+	KeyLength:  32,
+}
+
+func expectVerify(t *testing.T, name string, v PasswordVerifier, password, encoded string, expectedOK, expectedRehash bool) {
+	t.Helper()
+
+	ok, rehash, err := v.Verify(password, encoded)
+	if err != nil {
+		t.Errorf("%s: unexpected error: %s", name, err)
+	}
+	if ok != expectedOK || rehash != expectedRehash {
+		t.Errorf("%s: expected ok %t and rehash %t, got: %t and %t", name, expectedOK, expectedRehash, ok, rehash)
+	}
+}
+
+func TestArgon2id(t *testing.T) {
+	h := NewArgon2id(testArgon2idParams)
+
+	// OK
+	encoded, err := h.Hash("password")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if !strings.HasPrefix(encoded, "$argon2id$v=19$m=64,t=1,p=1$") {
+		t.Errorf("unexpected hash: %s", encoded)
+	}
+
+	expectVerify(t, "Round trip", h, "password", encoded, true, false)
+	expectVerify(t, "Wrong password", h, "Password", encoded, false, false)
+
+	// OK. Every hash has a salt of its own
+	other, err := h.Hash("password")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if other == encoded {
+		t.Error("expected different hashes of the same password")
+	}
+
+	// OK. Changed parameters ask for a rehash of a matching password only
+	stronger := NewArgon2id(Argon2idParams{Memory: 128, Time: 2, Threads: 1, SaltLength: 16, KeyLength: 32})
+	expectVerify(t, "Changed parameters", stronger, "password", encoded, true, true)
+	expectVerify(t, "Changed parameters, wrong password", stronger, "Password", encoded, false, false)
+
+	// Another algorithm
+	if _, _, err = h.Verify("password", "$2b$04$abcdefghijklmnopqrstuv"); !errors.Is(err, ErrUnknownHash) {
+		t.Errorf("expected ErrUnknownHash, got: %v", err)
+	}
+
+	// Malformed hashes
+	for _, malformed := range []string{
+		"$argon2id$v=16$m=64,t=1,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=64$c2FsdA$a2V5",
+		"$argon2id$v=19$m=64,t=1,p=1$!$a2V5",
+		"$argon2id$v=19$m=64,t=1,p=1$c2FsdA$",
+	} {
+		if _, _, err = h.Verify("password", malformed); err == nil || errors.Is(err, ErrUnknownHash) {
+			t.Errorf("%s: expected a malformed hash error, got: %v", malformed, err)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package hasher
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestBcrypt(t *testing.T) {
+	h := NewBcrypt(bcrypt.MinCost)
+
+	// OK
+	encoded, err := h.Hash("password")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if !strings.HasPrefix(encoded, "$2a$04$") {
+		t.Errorf("unexpected hash: %s", encoded)
+	}
+
+	expectVerify(t, "Round trip", h, "password", encoded, true, false)
+	expectVerify(t, "Wrong password", h, "Password", encoded, false, false)
+
+	// OK. Another cost asks for a rehash of a matching password only
+	costlier := NewBcrypt(bcrypt.MinCost + 1)
+	expectVerify(t, "Changed cost", costlier, "password", encoded, true, true)
+	expectVerify(t, "Changed cost, wrong password", costlier, "Password", encoded, false, false)
+
+	// Another algorithm
+	if _, _, err = h.Verify("password", "$argon2id$v=19$m=64,t=1,p=1$c2FsdA$a2V5"); !errors.Is(err, ErrUnknownHash) {
+		t.Errorf("expected ErrUnknownHash, got: %v", err)
+	}
+
+	// Password too long
+	if _, err = h.Hash(strings.Repeat("a", 73)); !errors.Is(err, bcrypt.ErrPasswordTooLong) {
+		t.Errorf("expected ErrPasswordTooLong, got: %v", err)
+	}
+}
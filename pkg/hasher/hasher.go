@@ -2,8 +2,10 @@ package hasher
 
 import (
 	"crypto/md5"
+	"crypto/subtle"
 	"encoding/hex"
 	"errors"
+	"strings"
 )
 
 type hasher struct {
@@ -27,14 +29,48 @@ func (h hasher) GetHash(msg string) (string, error) {
 	return hex.EncodeToString(md.Sum(h.salt)), nil
 }
 
+type md5Verifier struct {
+	hasher hasher
+	prefix string
+}
+
+// NewMD5Verifier verifies the passwords hashed by a hasher of the salt, which
+// are the hex of the salt followed by the unsalted MD5 of the password. They
+// can't be trusted and always ask for a rehash.
+func NewMD5Verifier(salt []byte) *md5Verifier {
+	return &md5Verifier{
+		hasher: hasher{salt: salt},
+		prefix: hex.EncodeToString(salt),
+	}
+}
+
+func (v *md5Verifier) Verify(password, encoded string) (bool, bool, error) {
+	if len(encoded) != len(v.prefix)+2*md5.Size || !strings.HasPrefix(encoded, v.prefix) {
+		return false, false, ErrUnknownHash
+	}
+
+	hash, err := v.hasher.GetHash(password)
+	if err != nil {
+		return false, false, err
+	}
+
+	ok := subtle.ConstantTimeCompare([]byte(hash), []byte(encoded)) == 1
+
+	return ok, ok, nil
+}
+
 // only for testing
-type badHasher struct {
+type badPasswordHasher struct {
 }
 
-func NewBadHasher() *badHasher {
-	return &badHasher{}
+func NewBadPasswordHasher() *badPasswordHasher {
+	return &badPasswordHasher{}
 }
 
-func (b badHasher) GetHash(msg string) (string, error) {
+func (b badPasswordHasher) Hash(password string) (string, error) {
 	return "", errors.New("some error")
 }
+
+func (b badPasswordHasher) Verify(password, encoded string) (bool, bool, error) {
+	return false, false, errors.New("some error")
+}
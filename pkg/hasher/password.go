@@ -0,0 +1,88 @@
+package hasher
+
+import (
+	"errors"
+	"fmt"
+)
+
+// LegacySalt is the salt of the MD5 hashes the passwords were stored as
+// before NewPasswordHasher.
+var LegacySalt = []byte("secret")
+
+// ErrUnknownHash is returned by a verifier for an encoded hash of another
+// algorithm.
+var ErrUnknownHash = errors.New("unknown password hash format")
+
+// PasswordVerifier checks passwords against encoded hashes. rehash reports a
+// match against a hash that is outdated and should be replaced by a new one.
+type PasswordVerifier interface {
+	Verify(password, encoded string) (ok, rehash bool, err error)
+}
+
+// PasswordHasher hashes passwords with a random salt of their own into a
+// string that carries the algorithm and its parameters as well.
+type PasswordHasher interface {
+	PasswordVerifier
+	Hash(password string) (string, error)
+}
+
+// The algorithms NewPasswordHasher knows.
+const (
+	Argon2id = "argon2id"
+	Bcrypt   = "bcrypt"
+)
+
+type upgradingHasher struct {
+	current PasswordHasher
+	older   []PasswordVerifier
+}
+
+// NewUpgradingHasher hashes with current and verifies with whichever of the
+// hashers recognises the hash, asking for a rehash of those matched by an
+// older one.
+func NewUpgradingHasher(current PasswordHasher, older ...PasswordVerifier) *upgradingHasher {
+	return &upgradingHasher{
+		current: current,
+		older:   older,
+	}
+}
+
+func (h *upgradingHasher) Hash(password string) (string, error) {
+	return h.current.Hash(password)
+}
+
+func (h *upgradingHasher) Verify(password, encoded string) (bool, bool, error) {
+	ok, rehash, err := h.current.Verify(password, encoded)
+	if !errors.Is(err, ErrUnknownHash) {
+		return ok, rehash, err
+	}
+
+	for _, verifier := range h.older {
+		ok, _, err = verifier.Verify(password, encoded)
+		if !errors.Is(err, ErrUnknownHash) {
+			return ok, ok, err
+		}
+	}
+
+	return false, false, ErrUnknownHash
+}
+
+// NewPasswordHasher returns a hasher of the given algorithm, Argon2id when it
+// is empty, that still verifies the hashes of the other one and the salted
+// MD5 hashes of legacySalt, so that they are replaced as users log in.
+func NewPasswordHasher(algorithm string, legacySalt []byte) (PasswordHasher, error) {
+	var (
+		argon2id = NewArgon2id(DefaultArgon2idParams)
+		bcrypt   = NewBcrypt(DefaultBcryptCost)
+		legacy   = NewMD5Verifier(legacySalt)
+	)
+
+	switch algorithm {
+	case Argon2id, "":
+		return NewUpgradingHasher(argon2id, bcrypt, legacy), nil
+	case Bcrypt:
+		return NewUpgradingHasher(bcrypt, argon2id, legacy), nil
+	}
+
+	return nil, fmt.Errorf("unknown password hash algorithm %q, expected %s or %s", algorithm, Argon2id, Bcrypt)
+}
@@ -0,0 +1,110 @@
+package hasher
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// legacyHash is the hash of "password" stored before per-password salts:
+// the hex of LegacySalt followed by the MD5 of the password.
+const legacyHash = "736563726574" + "5f4dcc3b5aa765d61d8327deb882cf99"
+
+func TestMD5Verifier(t *testing.T) {
+	v := NewMD5Verifier(LegacySalt)
+
+	// OK. A match is always to be rehashed
+	expectVerify(t, "Legacy hash", v, "password", legacyHash, true, true)
+	expectVerify(t, "Wrong password", v, "Password", legacyHash, false, false)
+
+	// Another algorithm or salt
+	for _, encoded := range []string{"$argon2id$v=19$m=64,t=1,p=1$c2FsdA$a2V5", "736563726574", "000000000000" + legacyHash[12:]} {
+		if _, _, err := v.Verify("password", encoded); !errors.Is(err, ErrUnknownHash) {
+			t.Errorf("%s: expected ErrUnknownHash, got: %v", encoded, err)
+		}
+	}
+}
+
+func TestUpgradingHasher(t *testing.T) {
+	argon2id := NewArgon2id(testArgon2idParams)
+	bcryptHasher := NewBcrypt(bcrypt.MinCost)
+	h := NewUpgradingHasher(argon2id, bcryptHasher, NewMD5Verifier(LegacySalt))
+
+	// OK. New hashes are of the current algorithm
+	encoded, err := h.Hash("password")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !strings.HasPrefix(encoded, "$argon2id$") {
+		t.Errorf("unexpected hash: %s", encoded)
+	}
+
+	expectVerify(t, "Current algorithm", h, "password", encoded, true, false)
+	expectVerify(t, "Current algorithm, wrong password", h, "Password", encoded, false, false)
+
+	// OK. The hashes of an older algorithm match and are to be rehashed
+	older, err := bcryptHasher.Hash("password")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expectVerify(t, "Older algorithm", h, "password", older, true, true)
+	expectVerify(t, "Older algorithm, wrong password", h, "Password", older, false, false)
+
+	// OK. Legacy MD5 hashes too
+	expectVerify(t, "Legacy hash", h, "password", legacyHash, true, true)
+	expectVerify(t, "Legacy hash, wrong password", h, "Password", legacyHash, false, false)
+
+	// OK. Outdated parameters of the current algorithm
+	outdated, err := NewArgon2id(Argon2idParams{Memory: 32, Time: 1, Threads: 1, SaltLength: 16, KeyLength: 32}).Hash("password")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expectVerify(t, "Outdated parameters", h, "password", outdated, true, true)
+
+	// Unknown hash
+	if _, _, err = h.Verify("password", "plaintext"); !errors.Is(err, ErrUnknownHash) {
+		t.Errorf("expected ErrUnknownHash, got: %v", err)
+	}
+}
+
+func TestNewPasswordHasher(t *testing.T) {
+	// OK
+	for algorithm, prefix := range map[string]string{"": "$argon2id$", Argon2id: "$argon2id$", Bcrypt: "$2a$"} {
+		h, err := NewPasswordHasher(algorithm, LegacySalt)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", algorithm, err)
+		}
+
+		upgrading, ok := h.(*upgradingHasher)
+		if !ok || len(upgrading.older) != 2 {
+			t.Fatalf("%s: expected a hasher verifying two older algorithms, got: %T", algorithm, h)
+		}
+
+		// The current hasher is swapped for a cheaper one of the same kind.
+		switch upgrading.current.(type) {
+		case *argon2idHasher:
+			upgrading.current = NewArgon2id(testArgon2idParams)
+		case *bcryptHasher:
+			upgrading.current = NewBcrypt(bcrypt.MinCost)
+		}
+
+		encoded, err := h.Hash("password")
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", algorithm, err)
+		}
+		if !strings.HasPrefix(encoded, prefix) {
+			t.Errorf("%s: expected a hash starting with %s, got: %s", algorithm, prefix, encoded)
+		}
+
+		expectVerify(t, algorithm+" legacy hash", h, "password", legacyHash, true, true)
+	}
+
+	// Unknown algorithm
+	if _, err := NewPasswordHasher("scrypt", LegacySalt); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
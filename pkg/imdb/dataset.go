@@ -0,0 +1,271 @@
+package imdb
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/akrovv/filmlibrary/internal/domain"
+)
+
+// The files of the dataset, each of them may be gzipped as it is published,
+// with .gz added to the name.
+const (
+	TitlesFile     = "title.basics.tsv"
+	PrincipalsFile = "title.principals.tsv"
+	NamesFile      = "name.basics.tsv"
+)
+
+// null is how the dataset writes a missing value.
+const null = `\N`
+
+// maxLine is the longest line of a file, the longest ones list the genres
+// or professions and are far shorter.
+const maxLine = 1 << 20
+
+// Filter selects the titles to load and, through them, their cast.
+type Filter struct {
+	// Types are the title types to keep, such as movie or tvMovie.
+	Types []string
+	// FromYear and ToYear bound the start year of a title when not zero,
+	// titles whose year isn't known are left out then.
+	FromYear int
+	ToYear   int
+}
+
+// Dataset reads the TSV files of an IMDb dataset from a directory. It is
+// a domain.IMDbSource: the principals are only those of the titles read and
+// the names only those of the principals, so the files must be read in order.
+type Dataset struct {
+	dir    string
+	filter Filter
+	titles map[string]struct{}
+	names  map[string]struct{}
+}
+
+func NewDataset(dir string, filter Filter) *Dataset {
+	return &Dataset{
+		dir:    dir,
+		filter: filter,
+		titles: make(map[string]struct{}),
+		names:  make(map[string]struct{}),
+	}
+}
+
+// Check reports a file missing from the directory before anything is read.
+func (d *Dataset) Check() error {
+	for _, name := range []string{TitlesFile, PrincipalsFile, NamesFile} {
+		file, err := d.open(name)
+		if err != nil {
+			return err
+		}
+		file.Close()
+	}
+
+	return nil
+}
+
+func (d *Dataset) Titles(fn func(title *domain.IMDbTitle) error) error {
+	types := make(map[string]bool, len(d.filter.Types))
+	for _, t := range d.filter.Types {
+		types[t] = true
+	}
+
+	return d.read(TitlesFile, []string{"tconst", "titleType", "primaryTitle", "startYear"}, func(values []string) error {
+		if len(types) > 0 && !types[values[1]] || values[2] == null {
+			return nil
+		}
+
+		year, err := parseYear("startYear", values[3])
+		if err != nil {
+			return err
+		}
+
+		if d.filter.FromYear != 0 && (year == 0 || year < d.filter.FromYear) ||
+			d.filter.ToYear != 0 && (year == 0 || year > d.filter.ToYear) {
+			return nil
+		}
+
+		d.titles[values[0]] = struct{}{}
+
+		return fn(&domain.IMDbTitle{
+			TConst: values[0],
+			Title:  truncate(values[2], domain.MaxMovieTitleLength),
+			Year:   year,
+		})
+	})
+}
+
+// Principals reads the actors and actresses of the titles, the rest of the
+// crew is left out.
+func (d *Dataset) Principals(fn func(principal *domain.IMDbPrincipal) error) error {
+	return d.read(PrincipalsFile, []string{"tconst", "nconst", "category"}, func(values []string) error {
+		if _, ok := d.titles[values[0]]; !ok {
+			return nil
+		}
+
+		gender := ""
+		switch values[2] {
+		case "actor":
+			gender = "Male"
+		case "actress":
+			gender = "Female"
+		default:
+			return nil
+		}
+
+		d.names[values[1]] = struct{}{}
+
+		return fn(&domain.IMDbPrincipal{
+			TConst: values[0],
+			NConst: values[1],
+			Gender: gender,
+		})
+	})
+}
+
+func (d *Dataset) Names(fn func(name *domain.IMDbName) error) error {
+	return d.read(NamesFile, []string{"nconst", "primaryName", "birthYear"}, func(values []string) error {
+		if _, ok := d.names[values[0]]; !ok || values[1] == null {
+			return nil
+		}
+
+		year, err := parseYear("birthYear", values[2])
+		if err != nil {
+			return err
+		}
+
+		return fn(&domain.IMDbName{
+			NConst:    values[0],
+			Name:      truncate(values[1], domain.MaxActorNameLength),
+			BirthYear: year,
+		})
+	})
+}
+
+// open opens a file of the dataset, gzipped or not.
+func (d *Dataset) open(name string) (io.ReadCloser, error) {
+	file, err := os.Open(filepath.Join(d.dir, name))
+	if err == nil {
+		return file, nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	file, err = os.Open(filepath.Join(d.dir, name+".gz"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("neither %s nor %s.gz is in %s", name, name, d.dir)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("%s.gz: %w", name, err)
+	}
+
+	return &gzipFile{Reader: reader, file: file}, nil
+}
+
+// read calls fn for every line of a file with the values of columns, which
+// are found by the header.
+func (d *Dataset) read(name string, columns []string, fn func(values []string) error) error {
+	file, err := d.open(name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLine)
+
+	if !scanner.Scan() {
+		if err = scanner.Err(); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		return fmt.Errorf("%s: the header is missing", name)
+	}
+
+	header := strings.Split(scanner.Text(), "\t")
+	indexes := make([]int, len(columns))
+	for i, column := range columns {
+		indexes[i] = -1
+		for j, name := range header {
+			if name == column {
+				indexes[i] = j
+			}
+		}
+		if indexes[i] < 0 {
+			return fmt.Errorf("%s: the %s column is missing", name, column)
+		}
+	}
+
+	values := make([]string, len(columns))
+	for line := 2; scanner.Scan(); line++ {
+		fields := strings.Split(scanner.Text(), "\t")
+		if len(fields) != len(header) {
+			return fmt.Errorf("%s:%d: expected %d columns, got %d", name, line, len(header), len(fields))
+		}
+
+		for i, index := range indexes {
+			values[i] = fields[index]
+		}
+
+		if err = fn(values); err != nil {
+			return fmt.Errorf("%s:%d: %w", name, line, err)
+		}
+	}
+
+	if err = scanner.Err(); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+
+	return nil
+}
+
+type gzipFile struct {
+	*gzip.Reader
+	file *os.File
+}
+
+func (f *gzipFile) Close() error {
+	f.Reader.Close()
+	return f.file.Close()
+}
+
+func parseYear(column, value string) (int, error) {
+	if value == null {
+		return 0, nil
+	}
+
+	year, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q", column, value)
+	}
+
+	return year, nil
+}
+
+// truncate cuts s to max characters, the columns it goes into are no longer.
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+
+	return string(runes[:max])
+}
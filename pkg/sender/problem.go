@@ -0,0 +1,74 @@
+package sender
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+const (
+	ProblemContentType = "application/problem+json"
+	RequestIDHeader    = "X-Request-ID"
+
+	problemTypeBase = "https://github.com/akrovv/film-library/blob/main/docs/problems.md#"
+)
+
+// FieldError describes what is wrong with a single field of a request.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Classifier tells the HTTP status code of err, its stable machine-readable
+// code and the fields of the request at fault, if it names any.
+type Classifier func(err error) (status int, code string, fields []FieldError)
+
+// Problem is an RFC 7807 problem details object.
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	Code      string       `json:"code"`
+	RequestID string       `json:"request_id,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+}
+
+// NewProblem describes err for a client. Internal errors keep their message
+// to the log, as it may contain database or infrastructure details.
+func NewProblem(r *http.Request, err error, classify Classifier) *Problem {
+	status, code, fields := classify(err)
+
+	problem := Problem{
+		Type:     problemTypeBase + code,
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   err.Error(),
+		Instance: r.URL.Path,
+		Code:     code,
+		Errors:   fields,
+	}
+
+	if status == http.StatusInternalServerError {
+		problem.Detail = "the server failed to handle the request"
+	}
+
+	return &problem
+}
+
+// ErrorJSON sends err to the client as a problem+json response, with the
+// status and code classify gives it.
+func ErrorJSON(w http.ResponseWriter, r *http.Request, err error, classify Classifier) {
+	problem := NewProblem(r, err, classify)
+	problem.RequestID = w.Header().Get(RequestIDHeader)
+
+	out, marshalErr := json.MarshalIndent(problem, "", "\t")
+	if marshalErr != nil {
+		http.Error(w, http.StatusText(problem.Status), problem.Status)
+		return
+	}
+
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(problem.Status)
+	_, _ = w.Write(out)
+}
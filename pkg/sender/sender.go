@@ -32,14 +32,3 @@ func WriteJSON(w http.ResponseWriter, status int, data interface{}, headers ...h
 
 	return nil
 }
-
-func ErrorJSON(w http.ResponseWriter, err error, status ...int) {
-	statusCode := http.StatusBadRequest
-	if len(status) > 0 {
-		statusCode = status[0]
-	}
-	var payload JSONResponse
-	payload.Error = true
-	payload.Msg = err.Error()
-	_ = WriteJSON(w, statusCode, payload)
-}
@@ -0,0 +1,187 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ErrInvalidToken is returned for a token that is malformed, expired, or
+// wasn't signed by a key of the keyring.
+var ErrInvalidToken = errors.New("invalid token")
+
+// MinSecretLength is the shortest HS256 secret accepted, as long as the hash.
+const MinSecretLength = 32
+
+// Key is a signing key named by the kid header of the tokens it signs.
+type Key struct {
+	ID     string
+	method jwt.SigningMethod
+	sign   interface{}
+	verify interface{}
+}
+
+// NewHS256Key returns an HMAC key shared by the issuer and the verifier.
+func NewHS256Key(id string, secret []byte) (Key, error) {
+	if len(secret) < MinSecretLength {
+		return Key{}, fmt.Errorf("key %s: HS256 secret must be at least %d bytes", id, MinSecretLength)
+	}
+
+	return Key{ID: id, method: jwt.SigningMethodHS256, sign: secret, verify: secret}, nil
+}
+
+// NewEdDSAKey returns an Ed25519 key made from its 32 byte seed.
+func NewEdDSAKey(id string, seed []byte) (Key, error) {
+	if len(seed) != ed25519.SeedSize {
+		return Key{}, fmt.Errorf("key %s: EdDSA seed must be %d bytes", id, ed25519.SeedSize)
+	}
+
+	private := ed25519.NewKeyFromSeed(seed)
+
+	return Key{ID: id, method: jwt.SigningMethodEdDSA, sign: private, verify: private.Public()}, nil
+}
+
+// ParseKeys reads comma-separated keys of the form kid:alg:base64, where alg
+// is HS256 with the secret or EdDSA with the seed of the key.
+func ParseKeys(spec string) ([]Key, error) {
+	keys := make([]Key, 0)
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		parts := strings.SplitN(item, ":", 3)
+		if len(parts) != 3 || parts[0] == "" {
+			return nil, fmt.Errorf("key %q must be kid:alg:base64", item)
+		}
+
+		material, err := base64.StdEncoding.DecodeString(parts[2])
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", parts[0], err)
+		}
+
+		var key Key
+		switch parts[1] {
+		case "HS256":
+			key, err = NewHS256Key(parts[0], material)
+		case "EdDSA":
+			key, err = NewEdDSAKey(parts[0], material)
+		default:
+			err = fmt.Errorf("key %s: unknown algorithm %s, want HS256 or EdDSA", parts[0], parts[1])
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// Claims are the claims of an access token.
+type Claims struct {
+	jwt.RegisteredClaims
+	Admin bool `json:"adm,omitempty"`
+}
+
+type keyring struct {
+	issuer  string
+	current Key
+	keys    map[string]Key
+	now     func() time.Time
+}
+
+// NewKeyring signs with the first of keys and verifies with any of them, so
+// that a key is rotated by putting the new one first and dropping the old
+// one once the tokens it signed have expired.
+func NewKeyring(issuer string, keys ...Key) (*keyring, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("no signing keys")
+	}
+
+	ring := keyring{
+		issuer:  issuer,
+		current: keys[0],
+		keys:    make(map[string]Key, len(keys)),
+		now:     time.Now,
+	}
+
+	for _, key := range keys {
+		if _, ok := ring.keys[key.ID]; ok {
+			return nil, fmt.Errorf("duplicate key %s", key.ID)
+		}
+		ring.keys[key.ID] = key
+	}
+
+	return &ring, nil
+}
+
+// Issue signs a token of subject valid for ttl and returns it with the time
+// it expires.
+func (k *keyring) Issue(subject string, admin bool, ttl time.Duration) (string, time.Time, error) {
+	now := k.now()
+	expires := now.Add(ttl)
+
+	claims := Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    k.issuer,
+			Subject:   subject,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expires),
+		},
+		Admin: admin,
+	}
+
+	token := jwt.NewWithClaims(k.current.method, claims)
+	token.Header["kid"] = k.current.ID
+
+	signed, err := token.SignedString(k.current.sign)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return signed, expires, nil
+}
+
+// Verify checks the signature, the issuer and the expiry of a token. The
+// algorithm must be the one of the key named by kid, so that a token can't
+// pass an EdDSA public key off as an HMAC secret.
+func (k *keyring) Verify(signed string) (*Claims, error) {
+	claims := Claims{}
+
+	_, err := jwt.ParseWithClaims(signed, &claims, func(token *jwt.Token) (interface{}, error) {
+		id, _ := token.Header["kid"].(string)
+
+		key, ok := k.keys[id]
+		if !ok {
+			return nil, fmt.Errorf("unknown key %q", id)
+		}
+
+		if token.Method.Alg() != key.method.Alg() {
+			return nil, fmt.Errorf("key %s doesn't sign %s", id, token.Method.Alg())
+		}
+
+		return key.verify, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg(), jwt.SigningMethodEdDSA.Alg()}),
+		jwt.WithIssuer(k.issuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithTimeFunc(k.now),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidToken, err)
+	}
+
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: no subject", ErrInvalidToken)
+	}
+
+	return &claims, nil
+}
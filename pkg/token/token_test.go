@@ -0,0 +1,235 @@
+package token
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var testNow = time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestKeyring(t *testing.T, issuer string, keys ...Key) *keyring {
+	ring, err := NewKeyring(issuer, keys...)
+	if err != nil {
+		t.Fatalf("can't create keyring: %s", err)
+	}
+	ring.now = func() time.Time { return testNow }
+
+	return ring
+}
+
+func newTestHS256Key(t *testing.T, id string, fill byte) Key {
+	key, err := NewHS256Key(id, bytes.Repeat([]byte{fill}, MinSecretLength))
+	if err != nil {
+		t.Fatalf("can't create key: %s", err)
+	}
+
+	return key
+}
+
+func newTestEdDSAKey(t *testing.T, id string, fill byte) Key {
+	key, err := NewEdDSAKey(id, bytes.Repeat([]byte{fill}, ed25519.SeedSize))
+	if err != nil {
+		t.Fatalf("can't create key: %s", err)
+	}
+
+	return key
+}
+
+func TestParseKeys(t *testing.T) {
+	secret := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, MinSecretLength))
+	seed := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{2}, ed25519.SeedSize))
+
+	// OK
+	keys, err := ParseKeys(" new:EdDSA:" + seed + ", old:HS256:" + secret + ",")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(keys) != 2 || keys[0].ID != "new" || keys[0].method != jwt.SigningMethodEdDSA ||
+		keys[1].ID != "old" || keys[1].method != jwt.SigningMethodHS256 {
+		t.Errorf("unexpected keys: %+v", keys)
+	}
+
+	// OK. No keys
+	if keys, err = ParseKeys(""); err != nil || len(keys) != 0 {
+		t.Errorf("expected no keys, got: %v, %v", keys, err)
+	}
+
+	short := base64.StdEncoding.EncodeToString([]byte("short"))
+	for _, spec := range []string{
+		"key",
+		"key:HS256",
+		":HS256:" + secret,
+		"key:HS256:not base64!",
+		"key:RS256:" + secret,
+		"key:none:" + secret,
+		"key:HS256:" + short,
+		"key:EdDSA:" + short,
+		"key:EdDSA:" + secret + "AAAA",
+	} {
+		if _, err = ParseKeys(spec); err == nil {
+			t.Errorf("%q: expected error, got nil", spec)
+		}
+	}
+}
+
+func TestNewKeyring(t *testing.T) {
+	if _, err := NewKeyring("test"); err == nil {
+		t.Error("no keys: expected error, got nil")
+	}
+
+	key := newTestHS256Key(t, "key", 1)
+	if _, err := NewKeyring("test", key, key); err == nil {
+		t.Error("duplicate key: expected error, got nil")
+	}
+}
+
+func TestIssueVerify(t *testing.T) {
+	for _, key := range []Key{newTestHS256Key(t, "hs", 1), newTestEdDSAKey(t, "ed", 2)} {
+		ring := newTestKeyring(t, "test", key)
+
+		signed, expires, err := ring.Issue("user", true, time.Minute)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", key.ID, err)
+		}
+
+		if !expires.Equal(testNow.Add(time.Minute)) {
+			t.Errorf("%s: expected expiry %v, got: %v", key.ID, testNow.Add(time.Minute), expires)
+		}
+
+		claims, err := ring.Verify(signed)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", key.ID, err)
+		}
+
+		if claims.Subject != "user" || !claims.Admin || claims.Issuer != "test" {
+			t.Errorf("%s: unexpected claims: %+v", key.ID, claims)
+		}
+	}
+}
+
+func TestVerifyRotation(t *testing.T) {
+	oldKey := newTestHS256Key(t, "old", 1)
+	newKey := newTestEdDSAKey(t, "new", 2)
+
+	before := newTestKeyring(t, "test", oldKey)
+	during := newTestKeyring(t, "test", newKey, oldKey)
+	after := newTestKeyring(t, "test", newKey)
+
+	oldToken, _, err := before.Issue("user", false, time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	newToken, _, err := during.Issue("user", false, time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// OK. Tokens of the old key still verify while it is kept
+	if _, err = during.Verify(oldToken); err != nil {
+		t.Errorf("old token: unexpected error: %s", err)
+	}
+
+	if _, err = during.Verify(newToken); err != nil {
+		t.Errorf("new token: unexpected error: %s", err)
+	}
+
+	// OK. The new key signs
+	token, _, err := jwt.NewParser().ParseUnverified(newToken, &Claims{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if token.Header["kid"] != "new" || token.Method != jwt.SigningMethodEdDSA {
+		t.Errorf("expected kid new and EdDSA, got: %v", token.Header)
+	}
+
+	// Old key dropped
+	if _, err = after.Verify(oldToken); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("expected ErrInvalidToken, got: %v", err)
+	}
+}
+
+func TestVerifyRejects(t *testing.T) {
+	hsKey := newTestHS256Key(t, "hs", 1)
+	edKey := newTestEdDSAKey(t, "ed", 2)
+	ring := newTestKeyring(t, "test", hsKey, edKey)
+
+	claims := Claims{RegisteredClaims: jwt.RegisteredClaims{
+		Issuer:    "test",
+		Subject:   "user",
+		ExpiresAt: jwt.NewNumericDate(testNow.Add(time.Minute)),
+	}}
+
+	sign := func(method jwt.SigningMethod, kid interface{}, claims Claims, key interface{}) string {
+		token := jwt.NewWithClaims(method, claims)
+		if kid != nil {
+			token.Header["kid"] = kid
+		}
+
+		signed, err := token.SignedString(key)
+		if err != nil {
+			t.Fatalf("can't sign token: %s", err)
+		}
+
+		return signed
+	}
+
+	expired := claims
+	expired.ExpiresAt = jwt.NewNumericDate(testNow.Add(-time.Second))
+
+	noExpiry := claims
+	noExpiry.ExpiresAt = nil
+
+	noSubject := claims
+	noSubject.Subject = ""
+
+	otherIssuer := claims
+	otherIssuer.Issuer = "other"
+
+	foreign := newTestHS256Key(t, "hs", 3)
+	public := []byte(edKey.verify.(ed25519.PublicKey))
+
+	for name, signed := range map[string]string{
+		"unknown kid":          sign(jwt.SigningMethodHS256, "gone", claims, hsKey.sign),
+		"no kid":               sign(jwt.SigningMethodHS256, nil, claims, hsKey.sign),
+		"kid not a string":     sign(jwt.SigningMethodHS256, 1, claims, hsKey.sign),
+		"wrong secret":         sign(jwt.SigningMethodHS256, "hs", claims, foreign.sign),
+		"HS256 with EdDSA key": sign(jwt.SigningMethodHS256, "ed", claims, public),
+		"EdDSA with HS256 key": sign(jwt.SigningMethodEdDSA, "hs", claims, edKey.sign),
+		"alg none":             sign(jwt.SigningMethodNone, "hs", claims, jwt.UnsafeAllowNoneSignatureType),
+		"HS512":                sign(jwt.SigningMethodHS512, "hs", claims, hsKey.sign),
+		"expired":              sign(jwt.SigningMethodHS256, "hs", expired, hsKey.sign),
+		"no expiry":            sign(jwt.SigningMethodHS256, "hs", noExpiry, hsKey.sign),
+		"no subject":           sign(jwt.SigningMethodHS256, "hs", noSubject, hsKey.sign),
+		"wrong issuer":         sign(jwt.SigningMethodHS256, "hs", otherIssuer, hsKey.sign),
+		"malformed":            "not.a.token",
+	} {
+		if _, err := ring.Verify(signed); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("%s: expected ErrInvalidToken, got: %v", name, err)
+		}
+	}
+
+	// OK. The same claims pass when signed right
+	if _, err := ring.Verify(sign(jwt.SigningMethodHS256, "hs", claims, hsKey.sign)); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	// Expired once the time passes
+	signed, _, err := ring.Issue("user", false, time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	ring.now = func() time.Time { return testNow.Add(time.Minute + time.Second) }
+	if _, err = ring.Verify(signed); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("expected ErrInvalidToken, got: %v", err)
+	}
+}